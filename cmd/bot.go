@@ -1,20 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/br0-space/bot/container"
+	"github.com/br0-space/bot/interfaces"
 	"github.com/br0-space/bot/pkg/config"
+	"github.com/br0-space/bot/pkg/db"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/pflag"
 )
 
 const (
-	readTimeout    = 15 * time.Second
-	writeTimeout   = 15 * time.Second
-	maxHeaderBytes = 4096
+	readTimeout            = 15 * time.Second
+	writeTimeout           = 15 * time.Second
+	maxHeaderBytes         = 4096
+	defaultShutdownTimeout = 30 * time.Second
 )
 
 func main() {
@@ -24,6 +32,10 @@ func main() {
 	logger := container.ProvideLogger()
 	cfg := container.ProvideConfig()
 
+	if cfg.Mode != interfaces.ModeWebhook && cfg.Mode != interfaces.ModePolling {
+		logger.Fatalf("unknown mode %q (expected %q or %q)", cfg.Mode, interfaces.ModeWebhook, interfaces.ModePolling)
+	}
+
 	if cfg.Database.AutoMigrate {
 		logger.Info("Running database migrations")
 
@@ -32,15 +44,57 @@ func main() {
 		}
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	logger.Info("Starting HTTP server listening on", cfg.Server.ListenAddr)
 
+	srv := newServer(cfg)
+	errs := make(chan error, 1)
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+
+	pollerDone := make(chan struct{})
+
+	if cfg.Mode == interfaces.ModePolling {
+		logger.Info("Receiving Telegram updates via long polling")
+
+		go func() {
+			defer close(pollerDone)
+
+			if err := container.ProvideTelegramPoller().Run(ctx); err != nil {
+				errs <- err
+			}
+		}()
+	} else {
+		close(pollerDone)
+	}
+
+	select {
+	case err := <-errs:
+		logger.Fatal(err)
+	case <-ctx.Done():
+	}
+
+	shutdown(srv, pollerDone)
+}
+
+func newServer(cfg *interfaces.ConfigStruct) *http.Server {
 	r := mux.NewRouter()
-	r.HandleFunc("/webhook", container.ProvideTelegramWebhookHandler().ServeHTTP)
+
+	if cfg.Mode == interfaces.ModeWebhook {
+		r.HandleFunc("/webhook", container.ProvideTelegramWebhookHandler().ServeHTTP)
+	}
+
 	r.Handle("/metrics", promhttp.Handler())
 	r.NotFoundHandler = http.HandlerFunc(notFound)
 	http.Handle("/", r)
 
-	srv := &http.Server{
+	return &http.Server{
 		Addr:           cfg.Server.ListenAddr,
 		Handler:        r,
 		ReadTimeout:    readTimeout,
@@ -48,10 +102,42 @@ func main() {
 		IdleTimeout:    0,
 		MaxHeaderBytes: maxHeaderBytes,
 	}
+}
 
-	if err := srv.ListenAndServe(); err != nil {
-		logger.Fatal(err)
+// shutdown stops accepting webhooks, waits for the poller and all in-flight
+// messages to finish within the configured deadline and closes the database.
+func shutdown(srv *http.Server, pollerDone <-chan struct{}) {
+	logger := container.ProvideLogger()
+	cfg := container.ProvideConfig()
+
+	timeout := cfg.Server.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
 	}
+
+	logger.Infof("Shutting down (deadline %s)", timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("Error while shutting down HTTP server:", err)
+	}
+
+	select {
+	case <-pollerDone:
+	case <-ctx.Done():
+	}
+
+	if err := container.ProvideMessageDispatcher().Shutdown(ctx); err != nil {
+		logger.Error("Error while waiting for in-flight messages:", err)
+	}
+
+	if err := db.CloseConnection(container.ProvideDatabaseConnection()); err != nil {
+		logger.Error("Error while closing database connection:", err)
+	}
+
+	logger.Info("Shutdown complete")
 }
 
 func notFound(_ http.ResponseWriter, req *http.Request) {
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/br0-space/bot/container"
+	"github.com/br0-space/bot/pkg/config"
+	"github.com/br0-space/bot/pkg/db"
+	"github.com/spf13/pflag"
+)
+
+const usage = "Usage: migrate up|down|status"
+
+func main() {
+	config.Init()
+	pflag.Parse()
+
+	if pflag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	logger := container.ProvideLogger()
+
+	err := run(pflag.Arg(0))
+
+	if closeErr := db.CloseConnection(container.ProvideDatabaseConnection()); closeErr != nil {
+		logger.Error("Error while closing database connection:", closeErr)
+	}
+
+	if err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func run(command string) error {
+	migration := container.ProvideDatabaseMigration()
+
+	switch command {
+	case "up":
+		return migration.Migrate()
+	case "down":
+		return migration.Rollback()
+	case "status":
+		status, err := migration.Status()
+		if err != nil {
+			return err
+		}
+
+		for _, step := range status {
+			appliedAt := "pending"
+			if step.AppliedAt != nil {
+				appliedAt = step.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+
+			fmt.Printf("%4d  %-24s %s\n", step.Version, step.Name, appliedAt)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, usage)
+	}
+}
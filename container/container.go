@@ -10,32 +10,56 @@ import (
 	"github.com/br0-space/bot/interfaces"
 	"github.com/br0-space/bot/pkg/config"
 	"github.com/br0-space/bot/pkg/db"
+	"github.com/br0-space/bot/pkg/dedup"
+	"github.com/br0-space/bot/pkg/dispatcher"
 	"github.com/br0-space/bot/pkg/fortune"
+	"github.com/br0-space/bot/pkg/matchers/activity"
+	"github.com/br0-space/bot/pkg/matchers/alias"
 	"github.com/br0-space/bot/pkg/matchers/atall"
 	"github.com/br0-space/bot/pkg/matchers/buzzwords"
 	"github.com/br0-space/bot/pkg/matchers/choose"
 	fortune2 "github.com/br0-space/bot/pkg/matchers/fortune"
 	"github.com/br0-space/bot/pkg/matchers/goodmorning"
+	"github.com/br0-space/bot/pkg/matchers/initiative"
 	"github.com/br0-space/bot/pkg/matchers/janein"
+	"github.com/br0-space/bot/pkg/matchers/karma"
+	"github.com/br0-space/bot/pkg/matchers/matchers"
 	"github.com/br0-space/bot/pkg/matchers/ping"
 	"github.com/br0-space/bot/pkg/matchers/plusplus"
 	"github.com/br0-space/bot/pkg/matchers/roll"
 	"github.com/br0-space/bot/pkg/matchers/stats"
 	"github.com/br0-space/bot/pkg/matchers/topflop"
+	"github.com/br0-space/bot/pkg/matchers/undo"
+	"github.com/br0-space/bot/pkg/matchers/why"
+	"github.com/br0-space/bot/pkg/matchers/wordstats"
 	xkcd2 "github.com/br0-space/bot/pkg/matchers/xkcd"
+	"github.com/br0-space/bot/pkg/matchersettings"
 	"github.com/br0-space/bot/pkg/repo"
 	"github.com/br0-space/bot/pkg/state"
+	"github.com/br0-space/bot/pkg/telegram"
 	"github.com/br0-space/bot/pkg/xkcd"
 	"gorm.io/gorm"
 )
 
 var (
-	configInstance          *interfaces.ConfigStruct
-	configLock              = &sync.Mutex{}
-	matcherRegistryInstance *matcher.Registry
-	matcherRegistryLock     = &sync.Mutex{}
-	stateInstance           interfaces.StateServiceInterface
-	stateLock               = &sync.Mutex{}
+	chatAdminCheckerInstance interfaces.ChatAdminCheckerInterface
+	chatAdminCheckerLock     = &sync.Mutex{}
+	configInstance           *interfaces.ConfigStruct
+	configLock               = &sync.Mutex{}
+	databaseInstance         *gorm.DB
+	databaseLock             = &sync.Mutex{}
+	dispatcherInstance       interfaces.MessageDispatcherInterface
+	dispatcherLock           = &sync.Mutex{}
+	deduplicatorInstance     interfaces.UpdateDeduplicatorInterface
+	deduplicatorLock         = &sync.Mutex{}
+	entitiesStoreInstance    interfaces.MessageEntitiesStoreInterface
+	entitiesStoreLock        = &sync.Mutex{}
+	matcherRegistryInstance  *matcher.Registry
+	matcherRegistryLock      = &sync.Mutex{}
+	matcherSettingsInstance  interfaces.MatcherSettingsServiceInterface
+	matcherSettingsLock      = &sync.Mutex{}
+	stateInstance            interfaces.StateServiceInterface
+	stateLock                = &sync.Mutex{}
 )
 
 func runsAsTest() bool {
@@ -70,23 +94,69 @@ func ProvideMatchersRegistry() *matcher.Registry {
 			ProvideLogger(),
 			ProvideTelegramClient(),
 		)
-		matcherRegistryInstance.Register(atall.MakeMatcher(ProvideUserStatsRepo()))
-		matcherRegistryInstance.Register(buzzwords.MakeMatcher(ProvidePlusplusRepo()))
-		matcherRegistryInstance.Register(choose.MakeMatcher())
-		matcherRegistryInstance.Register(goodmorning.MakeMatcher(ProvideState(), ProvideFortuneService()))
-		matcherRegistryInstance.Register(fortune2.MakeMatcher(ProvideFortuneService()))
-		matcherRegistryInstance.Register(janein.MakeMatcher())
-		matcherRegistryInstance.Register(ping.MakeMatcher())
-		matcherRegistryInstance.Register(plusplus.MakeMatcher(ProvidePlusplusRepo()))
-		matcherRegistryInstance.Register(roll.MakeMatcher(ProvideRollRepo()))
-		matcherRegistryInstance.Register(stats.MakeMatcher(ProvideUserStatsRepo()))
-		matcherRegistryInstance.Register(topflop.MakeMatcher(ProvidePlusplusRepo()))
-		matcherRegistryInstance.Register(xkcd2.MakeMatcher(ProvideXkcdService()))
+
+		settings := ProvideMatcherSettings()
+		toggleableMatchers := []matcher.Interface{
+			activity.MakeMatcher(ProvideMessageStatsRepo(), ProvideUserStatsRepo(), ProvideTelegramPhotoSender()),
+			alias.MakeMatcher(ProvidePlusplusRepo(), ProvideChatAdminChecker()),
+			atall.MakeMatcher(ProvideUserStatsRepo()),
+			buzzwords.MakeMatcher(ProvidePlusplusRepo()),
+			choose.MakeMatcher(),
+			goodmorning.MakeMatcher(ProvideState(), ProvideFortuneService()),
+			fortune2.MakeMatcher(ProvideFortuneService()),
+			initiative.MakeMatcher(),
+			janein.MakeMatcher(),
+			karma.MakeMatcher(ProvidePlusplusRepo(), ProvideChatAdminChecker()),
+			ping.MakeMatcher(),
+			plusplus.MakeMatcher(ProvidePlusplusRepo(), ProvideMessageEntitiesStore(), ProvideConfig().Plusplus),
+			roll.MakeMatcher(ProvideRollRepo(), ProvideTelegramClient()),
+			stats.MakeMatcher(ProvideUserStatsRepo()),
+			topflop.MakeMatcher(ProvidePlusplusRepo()),
+			undo.MakeMatcher(ProvidePlusplusRepo(), ProvideConfig().Plusplus),
+			wordstats.MakeMatcher(ProvideMessageStatsRepo()),
+			why.MakeMatcher(ProvidePlusplusRepo()),
+			xkcd2.MakeMatcher(ProvideXkcdService()),
+		}
+		identifiers := make([]string, 0, len(toggleableMatchers))
+
+		for _, m := range toggleableMatchers {
+			matcherRegistryInstance.Register(matchersettings.Wrap(m, settings))
+			identifiers = append(identifiers, m.Identifier())
+		}
+
+		matcherRegistryInstance.Register(matchers.MakeMatcher(identifiers, settings, ProvideChatAdminChecker()))
 	}
 
 	return matcherRegistryInstance
 }
 
+func ProvideMatcherSettings() interfaces.MatcherSettingsServiceInterface {
+	matcherSettingsLock.Lock()
+	defer matcherSettingsLock.Unlock()
+
+	if matcherSettingsInstance == nil {
+		matcherSettingsInstance = matchersettings.NewService(
+			ProvideMatcherSettingRepo(),
+		)
+	}
+
+	return matcherSettingsInstance
+}
+
+func ProvideChatAdminChecker() interfaces.ChatAdminCheckerInterface {
+	chatAdminCheckerLock.Lock()
+	defer chatAdminCheckerLock.Unlock()
+
+	if chatAdminCheckerInstance == nil {
+		chatAdminCheckerInstance = telegram.NewChatAdminChecker(
+			&ProvideConfig().Telegram,
+			ProvideConfig().Chats.AdminUserIDs,
+		)
+	}
+
+	return chatAdminCheckerInstance
+}
+
 func ProvideState() interfaces.StateServiceInterface {
 	stateLock.Lock()
 	defer stateLock.Unlock()
@@ -101,16 +171,151 @@ func ProvideState() interfaces.StateServiceInterface {
 	return stateInstance
 }
 
-func ProvideTelegramWebhookHandler() telegramclient.WebhookHandlerInterface {
+// ProvideMessageHandler returns the handler for new messages. The entities are
+// stored right before processing, so the matchers see those of the version
+// being processed even if an edit is already queued.
+func ProvideMessageHandler() func(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct) {
 	matchersRegistry := ProvideMatchersRegistry()
 	stateService := ProvideState()
+	entitiesStore := ProvideMessageEntitiesStore()
+
+	return func(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct) {
+		entitiesStore.Put(messageIn.Chat.ID, messageIn.ID, entities)
+		stateService.ProcessMessage(messageIn)
+		matchersRegistry.Process(messageIn)
+	}
+}
+
+// ProvideEditedMessageHandler returns the handler for edited messages. Only
+// matchers that can revise their earlier changes see edits, and they aren't
+// counted in the stats again. Like for new messages, the entities of the
+// edited version are stored right before processing.
+func ProvideEditedMessageHandler() func(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct) {
+	logger := ProvideLogger()
+	entitiesStore := ProvideMessageEntitiesStore()
+	settings := ProvideMatcherSettings()
+	client := ProvideTelegramClient()
+	editMatchers := []interfaces.EditedMessageMatcherInterface{
+		plusplus.MakeMatcher(ProvidePlusplusRepo(), ProvideMessageEntitiesStore(), ProvideConfig().Plusplus),
+	}
+
+	return func(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct) {
+		entitiesStore.Put(messageIn.Chat.ID, messageIn.ID, entities)
+
+		for _, m := range editMatchers {
+			if !settings.IsEnabled(messageIn.Chat.ID, m.Identifier()) {
+				continue
+			}
+
+			replies, err := m.ProcessEdit(messageIn)
+			if err != nil {
+				logger.Errorf("Error processing edited message %d in matcher %s: %s", messageIn.ID, m.Identifier(), err)
+
+				continue
+			}
+
+			for _, reply := range replies {
+				if err := client.SendMessage(messageIn.Chat.ID, reply); err != nil {
+					logger.Errorf("Error sending reply to edited message %d: %s", messageIn.ID, err)
+				}
+			}
+		}
+	}
+}
+
+func ProvideMessageDispatcher() interfaces.MessageDispatcherInterface {
+	dispatcherLock.Lock()
+	defer dispatcherLock.Unlock()
+
+	if dispatcherInstance == nil {
+		dispatcherInstance = dispatcher.NewDispatcher(
+			ProvideConfig().Queue,
+			ProvideMessageHandler(),
+			ProvideEditedMessageHandler(),
+		)
+	}
+
+	return dispatcherInstance
+}
+
+func ProvideUpdateDeduplicator() interfaces.UpdateDeduplicatorInterface {
+	deduplicatorLock.Lock()
+	defer deduplicatorLock.Unlock()
+
+	if deduplicatorInstance == nil {
+		deduplicatorInstance = dedup.NewDeduplicator(
+			ProvideConfig().Dedup,
+			ProvideProcessedUpdateRepo(),
+		)
+	}
+
+	return deduplicatorInstance
+}
+
+func ProvideMessageEntitiesStore() interfaces.MessageEntitiesStoreInterface {
+	entitiesStoreLock.Lock()
+	defer entitiesStoreLock.Unlock()
 
-	return telegramclient.NewHandler(
+	if entitiesStoreInstance == nil {
+		entitiesStoreInstance = telegram.NewEntitiesStore()
+	}
+
+	return entitiesStoreInstance
+}
+
+func ProvideUpdateHandler() func(update telegram.Update) {
+	logger := ProvideLogger()
+	deduplicator := ProvideUpdateDeduplicator()
+	messageDispatcher := ProvideMessageDispatcher()
+	chatFilter := telegram.NewChatFilter(
+		ProvideConfig().Telegram.ChatID,
+		ProvideConfig().Chats.AllowedIDs,
+	)
+
+	return func(update telegram.Update) {
+		message, edited := update.MessageOrEdit()
+
+		if !chatFilter.Allows(message.Chat.ID) {
+			logger.Warningf("Dropping update %d from chat %d which is not allowed", update.ID, message.Chat.ID)
+
+			return
+		}
+
+		if deduplicator.IsDuplicate(update.ID) {
+			logger.Debugf("Dropping duplicate update %d", update.ID)
+
+			return
+		}
+
+		if edited {
+			messageDispatcher.DispatchEdit(message.WebhookMessageStruct, message.TextOrCaptionEntities())
+
+			return
+		}
+
+		messageDispatcher.Dispatch(message.WebhookMessageStruct, message.TextOrCaptionEntities())
+	}
+}
+
+func ProvideTelegramWebhookHandler() telegramclient.WebhookHandlerInterface {
+	return telegram.NewWebhookHandler(
+		&ProvideConfig().Telegram,
+		ProvideConfig().Webhook,
+		ProvideUpdateHandler(),
+	)
+}
+
+func ProvideTelegramPoller() interfaces.TelegramPollerInterface {
+	return telegram.NewPoller(
+		&ProvideConfig().Telegram,
+		ProvideConfig().Polling,
+		ProvideUpdateHandler(),
+	)
+}
+
+func ProvideTelegramPhotoSender() interfaces.TelegramPhotoSenderInterface {
+	return telegram.NewPhotoSender(
 		&ProvideConfig().Telegram,
-		func(messageIn telegramclient.WebhookMessageStruct) {
-			stateService.ProcessMessage(messageIn)
-			matchersRegistry.Process(messageIn)
-		},
 	)
 }
 
@@ -125,18 +330,23 @@ func ProvideTelegramClient() telegramclient.ClientInterface {
 }
 
 func ProvideDatabaseConnection() *gorm.DB {
-	return db.NewConnection(
-		ProvideLogger(),
-		ProvideConfig().Database,
-	)
+	databaseLock.Lock()
+	defer databaseLock.Unlock()
+
+	if databaseInstance == nil {
+		databaseInstance = db.NewConnection(
+			ProvideLogger(),
+			ProvideConfig().Database,
+		)
+	}
+
+	return databaseInstance
 }
 
 func ProvideDatabaseMigration() interfaces.DatabaseMigrationInterface {
 	return db.MakeDatabaseMigration(
-		ProvideMessageStatsRepo(),
-		ProvidePlusplusRepo(),
-		ProvideRollRepo(),
-		ProvideUserStatsRepo(),
+		ProvideDatabaseConnection(),
+		db.Migrations(ProvideConfig().Telegram.ChatID),
 	)
 }
 
@@ -164,6 +374,18 @@ func ProvideUserStatsRepo() interfaces.UserStatsRepoInterface {
 	)
 }
 
+func ProvideProcessedUpdateRepo() interfaces.ProcessedUpdateRepoInterface {
+	return repo.NewProcessedUpdateRepo(
+		ProvideDatabaseConnection(),
+	)
+}
+
+func ProvideMatcherSettingRepo() interfaces.MatcherSettingRepoInterface {
+	return repo.NewMatcherSettingRepo(
+		ProvideDatabaseConnection(),
+	)
+}
+
 func ProvideFortuneService() fortune.Service {
 	return fortune.MakeService()
 }
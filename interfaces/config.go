@@ -1,17 +1,34 @@
 package interfaces
 
-import telegramclient "github.com/br0-space/bot-telegramclient"
+import (
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+)
+
+const (
+	ModeWebhook = "webhook"
+	ModePolling = "polling"
+)
 
 type ConfigStruct struct {
-	Verbose  bool `mapstructure:"verbose"`
-	Quiet    bool `mapstructure:"quiet"`
+	Verbose  bool   `mapstructure:"verbose"`
+	Quiet    bool   `mapstructure:"quiet"`
+	Mode     string `mapstructure:"mode"`
 	Server   ServerConfigStruct
 	Database DatabaseConfigStruct
 	Telegram telegramclient.ConfigStruct
+	Chats    ChatsConfigStruct
+	Webhook  WebhookConfigStruct
+	Polling  PollingConfigStruct
+	Queue    QueueConfigStruct
+	Dedup    DeduplicationConfigStruct
+	Plusplus PlusplusConfigStruct
 }
 
 type ServerConfigStruct struct {
-	ListenAddr string
+	ListenAddr      string
+	ShutdownTimeout time.Duration
 }
 
 type DatabaseConfigStruct struct {
@@ -31,6 +48,44 @@ type DatabaseConfigStruct struct {
 	AutoMigrate bool
 }
 
+type ChatsConfigStruct struct {
+	AllowedIDs   []int64
+	AdminUserIDs []int64
+}
+
+type WebhookConfigStruct struct {
+	SecretToken    string
+	AllowedCIDRs   []string
+	RealIPHeader   string
+	TrustedProxies []string
+}
+
+type PollingConfigStruct struct {
+	EndpointGetUpdates    string
+	EndpointDeleteWebhook string
+	Timeout               uint
+	Limit                 uint
+}
+
+type QueueConfigStruct struct {
+	Size    uint
+	Workers uint
+}
+
+type DeduplicationConfigStruct struct {
+	CacheSize uint
+	Retention time.Duration
+}
+
+// PlusplusConfigStruct holds the rules for changing terms with ++ and --.
+// A cooldown, maximum or undo window of 0 disables the respective rule.
+type PlusplusConfigStruct struct {
+	AllowSelfIncrement bool
+	Cooldown           time.Duration
+	MaxDelta           int
+	UndoWindow         time.Duration
+}
+
 type MatcherConfigStruct struct {
 	Enabled bool
 }
@@ -1,7 +1,21 @@
 package interfaces
 
+import "time"
+
 type DatabaseMigrationInterface interface {
+	// Migrate applies all pending migrations.
 	Migrate() error
+	// Rollback reverts the most recently applied migration.
+	Rollback() error
+	// RollbackTo reverts all applied migrations newer than the given version.
+	RollbackTo(version uint) error
+	Status() ([]MigrationStatusStruct, error)
+}
+
+type MigrationStatusStruct struct {
+	Version   uint
+	Name      string
+	AppliedAt *time.Time
 }
 
 type DatabaseRepositoryInterface interface {
@@ -9,9 +23,3 @@ type DatabaseRepositoryInterface interface {
 	Plusplus() PlusplusRepoInterface
 	Stats() UserStatsRepoInterface
 }
-
-type RepoInterface interface {
-	Model() any
-	TableName() string
-	Migrate() error
-}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+)
+
+// MessageDispatcherInterface queues messages for processing. The entities of
+// a message travel with it, so every version of an edited message is processed
+// with its own entities.
+type MessageDispatcherInterface interface {
+	Dispatch(messageIn telegramclient.WebhookMessageStruct, entities []MessageEntityStruct)
+	DispatchEdit(messageIn telegramclient.WebhookMessageStruct, entities []MessageEntityStruct)
+	Shutdown(ctx context.Context) error
+}
+
+// EditedMessageMatcherInterface is implemented by matchers that also handle
+// edited messages. Unlike Process, ProcessEdit is called for every edit, even
+// if the edited text doesn't match anymore.
+type EditedMessageMatcherInterface interface {
+	Identifier() string
+	ProcessEdit(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error)
+}
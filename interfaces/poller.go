@@ -0,0 +1,7 @@
+package interfaces
+
+import "context"
+
+type TelegramPollerInterface interface {
+	Run(ctx context.Context) error
+}
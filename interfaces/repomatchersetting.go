@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"gorm.io/gorm"
+)
+
+type MatcherSetting struct {
+	gorm.Model `exhaustruct:"optional"`
+
+	ChatID     int64  `gorm:"<-:create;not null;uniqueIndex:idx_matcher_settings_chat_identifier"`
+	Identifier string `gorm:"<-:create;not null;uniqueIndex:idx_matcher_settings_chat_identifier"`
+	Enabled    bool   `gorm:"<-;not null"`
+}
+
+type MatcherSettingRepoInterface interface {
+	SetEnabled(chatID int64, identifier string, enabled bool) error
+	GetDisabledIdentifiers(chatID int64) ([]string, error)
+}
+
+type MatcherSettingsServiceInterface interface {
+	IsEnabled(chatID int64, identifier string) bool
+	SetEnabled(chatID int64, identifier string, enabled bool) error
+}
+
+type ChatAdminCheckerInterface interface {
+	IsAdmin(messageIn telegramclient.WebhookMessageStruct) (bool, error)
+}
@@ -9,6 +9,7 @@ import (
 type MessageStats struct {
 	gorm.Model `exhaustruct:"optional"`
 
+	ChatID int64 `gorm:"<-:create;not null;default:0;index"`
 	UserID int64 `gorm:"<-:create;index"`
 	// UserStats Stats     `gorm:"foreignKey:user_id;references:user_id;constraint:OnDelete:CASCADE"`
 	Time  time.Time `gorm:"<-:create;index"`
@@ -18,11 +19,46 @@ type MessageStats struct {
 type MessageStatsWordCountStruct struct {
 	UserID   int64
 	Username string
+	Messages int
+	Words    int
+}
+
+// MessageStatsBucketStruct is one bar of a histogram, e.g. one hour of the day
+// (0-23) or one day of the week (0 = Sunday).
+type MessageStatsBucketStruct struct {
+	Bucket   int
+	Messages int
+	Words    int
+}
+
+// MessageStatsHeatmapCellStruct is the activity in one hour of one day of the week.
+type MessageStatsHeatmapCellStruct struct {
+	Weekday  time.Weekday
+	Hour     int
+	Messages int
+	Words    int
+}
+
+// MessageStatsDayStruct is the activity on one calendar day, formatted as 2006-01-02.
+type MessageStatsDayStruct struct {
+	Day      string
+	Messages int
 	Words    int
 }
 
 type MessageStatsRepoInterface interface {
-	InsertMessageStats(userID int64, words int) error
-	GetKnownUserIDs() ([]int64, error)
-	GetWordCounts() ([]MessageStatsWordCountStruct, error)
+	InsertMessageStats(chatID int64, userID int64, words int) error
+	GetKnownUserIDs(chatID int64) ([]int64, error)
+	// GetWordCounts returns words and messages per user since the given time, a zero time means all time.
+	GetWordCounts(chatID int64, since time.Time) ([]MessageStatsWordCountStruct, error)
+	// GetHourHistogram returns 24 buckets with the activity per hour of the day in the given location.
+	GetHourHistogram(chatID int64, since time.Time, loc *time.Location) ([]MessageStatsBucketStruct, error)
+	// GetWeekdayHistogram returns 7 buckets with the activity per day of the week in the given location.
+	GetWeekdayHistogram(chatID int64, since time.Time, loc *time.Location) ([]MessageStatsBucketStruct, error)
+	// GetActivityHeatmap returns 7*24 cells ordered by day of the week (starting with Sunday) and hour.
+	// A user ID of 0 includes all users of the chat.
+	GetActivityHeatmap(chatID int64, userID int64, since time.Time, loc *time.Location) ([]MessageStatsHeatmapCellStruct, error)
+	// GetDailyActivity returns the activity per day since the given time, days without messages are left out.
+	// A user ID of 0 includes all users of the chat.
+	GetDailyActivity(chatID int64, userID int64, since time.Time, loc *time.Location) ([]MessageStatsDayStruct, error)
 }
@@ -1,16 +1,177 @@
 package interfaces
 
-import "gorm.io/gorm"
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Plusplus struct {
 	gorm.Model `exhaustruct:"optional"`
 
-	Name  string `gorm:"<-:create;uniqueIndex"`
-	Value int    `gorm:"<-;index"`
+	ChatID int64  `gorm:"<-:create;not null;default:0;uniqueIndex:idx_plusplus_chat_name"`
+	Name   string `gorm:"<-:create;uniqueIndex:idx_plusplus_chat_name"`
+	Value  int    `gorm:"<-;index"`
+}
+
+// PlusplusUserPrefix starts the terms that stand for a chat member, followed
+// by the user ID. Terms typed in messages can't contain spaces, so they never
+// collide with these.
+const PlusplusUserPrefix = "user "
+
+// ErrPlusplusSelfAlias is returned when a term would become an alias of itself.
+var ErrPlusplusSelfAlias = errors.New("a term can't be an alias of itself")
+
+// PlusplusEvent records a single change of a term. Events are only ever
+// appended, the sum of their deltas matches the value in Plusplus for all
+// changes made since the event log exists. Token marks changes made by a ++
+// or -- token in the message, which are revised when the message is edited.
+type PlusplusEvent struct {
+	gorm.Model `exhaustruct:"optional"`
+
+	ChatID      int64  `gorm:"<-:create;not null;index:idx_plusplus_events_chat_name"`
+	GiverUserID int64  `gorm:"<-:create;not null;index"`
+	Name        string `gorm:"<-:create;not null;index:idx_plusplus_events_chat_name"`
+	Delta       int    `gorm:"<-:create;not null"`
+	MessageID   int64  `gorm:"<-:create;not null"`
+	Reason      string `gorm:"<-:create;not null;default:''"`
+	Token       bool   `gorm:"<-:create;not null;default:false"`
+}
+
+// PlusplusAlias maps a term onto a canonical term of the same chat. Aliases
+// always point to a canonical term directly, never to another alias.
+type PlusplusAlias struct {
+	gorm.Model `exhaustruct:"optional"`
+
+	ChatID int64  `gorm:"<-:create;not null;uniqueIndex:idx_plusplus_aliases_chat_alias"`
+	Alias  string `gorm:"<-:create;not null;uniqueIndex:idx_plusplus_aliases_chat_alias"`
+	Name   string `gorm:"<-;not null;index"`
+}
+
+// Actions of plusplus corrections.
+const (
+	PlusplusActionUndo   = "undo"
+	PlusplusActionSet    = "set"
+	PlusplusActionDelete = "delete"
+)
+
+// PlusplusCorrection records who corrected the value of a term by other means
+// than ++ and --, and what the value was before and after.
+type PlusplusCorrection struct {
+	gorm.Model `exhaustruct:"optional"`
+
+	ChatID   int64  `gorm:"<-:create;not null;index"`
+	UserID   int64  `gorm:"<-:create;not null"`
+	Action   string `gorm:"<-:create;not null"`
+	Name     string `gorm:"<-:create;not null"`
+	OldValue int    `gorm:"<-:create;not null"`
+	NewValue int    `gorm:"<-:create;not null"`
+}
+
+// PlusplusGiverStruct sums up the points one user has given to a term.
+type PlusplusGiverStruct struct {
+	UserID   int64
+	Username string
+	Plus     int
+	Minus    int
+}
+
+// PlusplusChangeStruct is a single change of a term together with the name of the giver.
+type PlusplusChangeStruct struct {
+	UserID    int64
+	Username  string
+	Delta     int
+	Reason    string
+	CreatedAt time.Time
+}
+
+// PlusplusPeriodStruct is the net change of a term in a period together with its all-time value.
+type PlusplusPeriodStruct struct {
+	Name  string
+	Delta int
+	Value int
+}
+
+// PlusplusIncrementStruct is the change of a term requested by a token in a message.
+type PlusplusIncrementStruct struct {
+	Name      string
+	Increment int
+	Reason    string
+}
+
+// PlusplusRevisionStruct is the change applied to a term when a message was edited, together with its new value.
+type PlusplusRevisionStruct struct {
+	Name  string
+	Delta int
+	Value int
+}
+
+// PlusplusRevisionRule checks the net change of a canonical term in an edited message that
+// adds votes, given the name the term is shown as, the change of the whole message after
+// the edit and when the user last changed the term. It returns why the change must not be
+// applied, or an empty string if it may be applied.
+type PlusplusRevisionRule func(term string, name string, delta int, total int, lastChange time.Time) string
+
+// PlusplusWeekStruct is the net change of a term in the week starting on the given Monday.
+type PlusplusWeekStruct struct {
+	Week  time.Time
+	Delta int
 }
 
+// PlusplusRepoInterface resolves aliases and @mentions of chat members in all
+// methods taking a term, and counts the values of aliases towards their
+// canonical term. Terms of chat members are returned with their current name.
 type PlusplusRepoInterface interface {
-	Increment(name string, increment int) (int, error)
-	FindTops(limit int) ([]Plusplus, error)
-	FindFlops(limit int) ([]Plusplus, error)
+	// Increment changes the value of a term and records who changed it in which message.
+	// The change isn't tied to a token, so it is kept when the message is edited.
+	Increment(chatID int64, userID int64, messageID int64, name string, increment int) (int, error)
+	// IncrementWithReason changes the value of a term for a ++ or -- token in a message
+	// and stores why the term was changed, the reason may be empty.
+	IncrementWithReason(chatID int64, userID int64, messageID int64, name string, increment int, reason string) (int, error)
+	// ReviseMessage applies the net change between the token changes recorded for an edited
+	// message and the increments of its new text. Terms the message no longer contains are
+	// reverted, changes that add votes must pass the rule. Messages whose changes were undone
+	// or that were counted before tokens were recorded aren't revised. It returns the terms
+	// whose value changed.
+	ReviseMessage(
+		chatID int64,
+		userID int64,
+		messageID int64,
+		increments []PlusplusIncrementStruct,
+		rule PlusplusRevisionRule,
+	) ([]PlusplusRevisionStruct, error)
+	FindTops(chatID int64, limit int) ([]Plusplus, error)
+	FindFlops(chatID int64, limit int) ([]Plusplus, error)
+	// FindTopsInPeriod returns the terms with the highest net change from since (inclusive) to until (exclusive).
+	FindTopsInPeriod(chatID int64, since time.Time, until time.Time, limit int) ([]PlusplusPeriodStruct, error)
+	// FindFlopsInPeriod returns the terms with the lowest net change from since (inclusive) to until (exclusive).
+	FindFlopsInPeriod(chatID int64, since time.Time, until time.Time, limit int) ([]PlusplusPeriodStruct, error)
+	// FindByName returns the counter of a term, gorm.ErrRecordNotFound if it has never been changed.
+	FindByName(chatID int64, name string) (Plusplus, error)
+	// SetAlias maps a term onto another one and returns the canonical term it now points to.
+	SetAlias(chatID int64, alias string, name string) (string, error)
+	// Merge makes a term an alias of another one and moves its value and history over.
+	// It returns the canonical term and its new value.
+	Merge(chatID int64, from string, into string) (string, int, error)
+	// FindAliases returns all aliases of a chat ordered by canonical term.
+	FindAliases(chatID int64) ([]PlusplusAlias, error)
+	// FindTopGivers returns the users who changed a term the most, plus and minus points added up.
+	FindTopGivers(chatID int64, name string, limit int) ([]PlusplusGiverStruct, error)
+	// FindRecentChanges returns the latest changes of a term, newest first.
+	FindRecentChanges(chatID int64, name string, limit int) ([]PlusplusChangeStruct, error)
+	// FindReasons returns the latest changes of a term that came with a reason, newest first.
+	FindReasons(chatID int64, name string, limit int) ([]PlusplusChangeStruct, error)
+	// GetLastIncrementTime returns when a user last changed a term, a zero time if never.
+	GetLastIncrementTime(chatID int64, userID int64, name string) (time.Time, error)
+	// Undo reverts the changes a user made with their latest message since the given time.
+	// It returns one correction per changed term, none if there is nothing to undo.
+	Undo(chatID int64, userID int64, since time.Time) ([]PlusplusCorrection, error)
+	// SetValue sets the value of a term including its aliases, gorm.ErrRecordNotFound if it has never been changed.
+	SetValue(chatID int64, userID int64, messageID int64, name string, value int) (PlusplusCorrection, error)
+	// Delete removes a term together with its aliases and history, gorm.ErrRecordNotFound if it has never been changed.
+	Delete(chatID int64, userID int64, name string) (PlusplusCorrection, error)
+	// GetWeeklyTrend returns the net change of a term in each of the last weeks in the given location,
+	// oldest first and including the current week. Weeks without changes have a delta of 0.
+	GetWeeklyTrend(chatID int64, name string, weeks int, loc *time.Location) ([]PlusplusWeekStruct, error)
 }
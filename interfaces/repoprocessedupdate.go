@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ProcessedUpdate struct {
+	gorm.Model `exhaustruct:"optional"`
+
+	UpdateID int64 `gorm:"<-:create;uniqueIndex"`
+}
+
+type ProcessedUpdateRepoInterface interface {
+	// Insert records an update ID and returns false if it was already known.
+	Insert(updateID int64) (bool, error)
+	DeleteOlderThan(t time.Time) error
+}
+
+type UpdateDeduplicatorInterface interface {
+	IsDuplicate(updateID int64) bool
+}
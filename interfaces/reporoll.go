@@ -1,11 +1,16 @@
 package interfaces
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Roll represents a single dice roll in the database.
 type Roll struct {
 	gorm.Model `exhaustruct:"optional"`
 
+	ChatID          int64  `gorm:"not null;default:0;index"`
 	UserID          int64  `gorm:"not null;index"`
 	DiceCount       int    `gorm:"not null"`
 	DiceSides       int    `gorm:"not null"`
@@ -14,8 +19,35 @@ type Roll struct {
 	Threshold       *int   `gorm:"index"`
 	KeepHighest     bool   `gorm:"not null;default:false"`
 	Success         *bool
-	CriticalHit     bool `gorm:"not null;default:false;index"`
-	CriticalFailure bool `gorm:"not null;default:false;index"`
+	CriticalHit     bool   `gorm:"not null;default:false;index"`
+	CriticalFailure bool   `gorm:"not null;default:false;index"`
+	Notation        string `gorm:"not null;default:''"`           // Dice expression: "1d20+7"
+	Faces           string `gorm:"type:text;not null;default:''"` // JSON object of all faces rolled per die size: {"20":[5,12]}
+	Hidden          bool   `gorm:"not null;default:false"`        // Secret roll of the GM, left out of the history and the stats
+}
+
+// RollMacro is a named roll of a user, like "attack" for "1d20+7 15".
+// Macros belong to the user and can be used in every chat.
+type RollMacro struct {
+	gorm.Model `exhaustruct:"optional"`
+
+	UserID   int64  `gorm:"<-:create;not null;uniqueIndex:idx_roll_macros_user_name"`
+	Name     string `gorm:"<-:create;not null;uniqueIndex:idx_roll_macros_user_name"`
+	Notation string `gorm:"not null"`
+}
+
+// RollHistoryStruct is a roll together with the name of the user.
+type RollHistoryStruct struct {
+	Roll `gorm:"embedded"`
+
+	Username string
+}
+
+// RollFacesStruct counts how often each face of a die size was rolled.
+type RollFacesStruct struct {
+	Sides int
+	// Counts[i] is the number of times face i+1 was rolled.
+	Counts []int
 }
 
 // RollStatsStruct contains aggregated statistics for rolls.
@@ -32,13 +64,52 @@ type RollStatsStruct struct {
 	SuccessRate      float64
 }
 
+// RollStreakStruct contains the longest streaks and the biggest swing of a user.
+type RollStreakStruct struct {
+	UserID   int64
+	Username string
+	// SuccessStreak and FailureStreak count consecutive rolls that met or missed
+	// their threshold. Rolls without a threshold don't interrupt them.
+	SuccessStreak int
+	FailureStreak int
+	// CriticalStreak counts consecutive critical hits.
+	CriticalStreak int
+	// SwingFrom and SwingTo are the totals of the consecutive rolls with the biggest difference.
+	SwingFrom int
+	SwingTo   int
+}
+
 // RollRepoInterface defines the repository interface for roll operations.
 type RollRepoInterface interface {
 	SaveRoll(roll *Roll) error
-	GetOverallStats() (*RollStatsStruct, error)
-	GetUserStats(userID int64) (*RollStatsStruct, error)
-	GetUserIDByUsername(username string) (int64, error)
-	GetLuckiestRoller() (*RollStatsStruct, error)
-	GetUnluckiestRoller() (*RollStatsStruct, error)
-	GetTopRollers(limit int) ([]RollStatsStruct, error)
+	GetOverallStats(chatID int64) (*RollStatsStruct, error)
+	// GetOverallStatsSince returns the overall statistics of the rolls in a chat since the given time.
+	GetOverallStatsSince(chatID int64, since time.Time) (*RollStatsStruct, error)
+	GetUserStats(chatID int64, userID int64) (*RollStatsStruct, error)
+	GetUserIDByUsername(chatID int64, username string) (int64, error)
+	GetLuckiestRoller(chatID int64) (*RollStatsStruct, error)
+	GetUnluckiestRoller(chatID int64) (*RollStatsStruct, error)
+	GetTopRollers(chatID int64, limit int) ([]RollStatsStruct, error)
+	// GetTopRollersSince returns the most active rollers in a chat since the given time.
+	GetTopRollersSince(chatID int64, since time.Time, limit int) ([]RollStatsStruct, error)
+	// GetStreaks returns the streaks of each user in a chat since the given time, ordered by user ID.
+	// The zero time includes all rolls.
+	GetStreaks(chatID int64, since time.Time) ([]RollStreakStruct, error)
+	// GetSessionStart returns the time of the first roll after the last break longer than gap,
+	// or the zero time if there are no rolls in the chat.
+	GetSessionStart(chatID int64, gap time.Duration) (time.Time, error)
+	// GetRecentRolls returns the latest rolls in a chat, newest first. A user ID of 0 returns the rolls of all users.
+	// Secret rolls of the GM are left out.
+	GetRecentRolls(chatID int64, userID int64, limit int) ([]RollHistoryStruct, error)
+	// GetFaceCounts counts the faces rolled in a chat per die size, ordered by size.
+	// A user ID of 0 counts the rolls of all users.
+	GetFaceCounts(chatID int64, userID int64) ([]RollFacesStruct, error)
+	// SaveMacro creates a macro or replaces the notation of an existing one.
+	SaveMacro(userID int64, name string, notation string) error
+	// GetMacro returns a macro of a user, gorm.ErrRecordNotFound if it doesn't exist.
+	GetMacro(userID int64, name string) (*RollMacro, error)
+	// GetMacros returns all macros of a user, ordered by name.
+	GetMacros(userID int64) ([]RollMacro, error)
+	// DeleteMacro deletes a macro and reports whether it existed.
+	DeleteMacro(userID int64, name string) (bool, error)
 }
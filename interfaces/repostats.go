@@ -9,7 +9,8 @@ import (
 type Stats struct {
 	gorm.Model `exhaustruct:"optional"`
 
-	UserID   int64  `gorm:"<-:create;uniqueIndex"`
+	ChatID   int64  `gorm:"<-:create;not null;default:0;uniqueIndex:idx_stats_chat_user"`
+	UserID   int64  `gorm:"<-:create;uniqueIndex:idx_stats_chat_user"`
 	Username string `gorm:"<-"`
 	Posts    uint32 `gorm:"<-"`
 	LastPost time.Time
@@ -23,7 +24,9 @@ type StatsUserStruct struct {
 }
 
 type UserStatsRepoInterface interface {
-	UpdateStats(userID int64, username string) error
-	GetKnownUsers() ([]StatsUserStruct, error)
-	GetTopUsers() ([]StatsUserStruct, error)
+	UpdateStats(chatID int64, userID int64, username string) error
+	GetKnownChatIDs() ([]int64, error)
+	GetKnownUsers(chatID int64) ([]StatsUserStruct, error)
+	GetTopUsers(chatID int64) ([]StatsUserStruct, error)
+	GetUserIDByUsername(chatID int64, username string) (int64, error)
 }
@@ -8,5 +8,5 @@ import (
 
 type StateServiceInterface interface {
 	ProcessMessage(messageIn telegramclient.WebhookMessageStruct)
-	GetLastPost(userID int64) *time.Time
+	GetLastPost(chatID int64, userID int64) *time.Time
 }
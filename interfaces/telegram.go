@@ -0,0 +1,26 @@
+package interfaces
+
+import telegramclient "github.com/br0-space/bot-telegramclient"
+
+type TelegramPhotoSenderInterface interface {
+	// SendPhoto uploads an image and sends it to a chat, optionally as a reply to a message.
+	SendPhoto(chatID int64, replyToMessageID int64, filename string, photo []byte, caption string) error
+}
+
+// MessageEntityStruct mimics a special entity in the text of a message, like a mention.
+// Offset and Length are counted in UTF-16 code units.
+// https://core.telegram.org/bots/api#messageentity
+type MessageEntityStruct struct {
+	Type   string                                   `json:"type"`
+	Offset int                                      `json:"offset"`
+	Length int                                      `json:"length"`
+	User   *telegramclient.WebhookMessageUserStruct `json:"user,omitempty"`
+}
+
+// MessageEntitiesStoreInterface keeps the entities of recent messages, because
+// matchers only get the message without its entities.
+type MessageEntitiesStoreInterface interface {
+	Put(chatID int64, messageID int64, entities []MessageEntityStruct)
+	// Get returns the entities of a message, nil if there are none or the message is unknown.
+	Get(chatID int64, messageID int64) []MessageEntityStruct
+}
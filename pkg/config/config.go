@@ -11,7 +11,9 @@ import (
 )
 
 var envToConfigMap = map[string]string{
+	"bot_mode":             "mode",
 	"listen_addr":          "server.listenAddr",
+	"shutdown_timeout":     "server.shutdownTimeout",
 	"db_driver":            "database.driver",
 	"sqlite_file":          "database.sqlite.file",
 	"postgres_host":        "database.postgresql.host",
@@ -25,12 +27,20 @@ var envToConfigMap = map[string]string{
 	"telegram_api_key":     "telegram.apiKey",
 	"telegram_webhook_url": "telegram.webhookUrl",
 	"telegram_chat_id":     "telegram.chatID",
+	"allowed_chat_ids":     "chats.allowedIDs",
+	"admin_user_ids":       "chats.adminUserIDs",
+	"webhook_secret_token": "webhook.secretToken",
+	"webhook_ip_header":    "webhook.realIPHeader",
+	"webhook_proxies":      "webhook.trustedProxies",
+	"queue_size":           "queue.size",
+	"queue_workers":        "queue.workers",
 }
 
 func Init() {
 	// Add default command line flags
 	pflag.BoolP("verbose", "v", false, "Show verbose output")
 	pflag.BoolP("quiet", "q", false, "Show errors only (overwrites verbose mode)")
+	pflag.String("mode", interfaces.ModeWebhook, "Receive updates via webhook or polling")
 }
 
 func NewConfig() *interfaces.ConfigStruct {
@@ -46,9 +56,16 @@ func NewTestConfig() *interfaces.ConfigStruct {
 	return &interfaces.ConfigStruct{
 		Verbose:  false,
 		Quiet:    false,
+		Mode:     interfaces.ModeWebhook,
 		Server:   interfaces.ServerConfigStruct{},
 		Database: interfaces.DatabaseConfigStruct{},
 		Telegram: telegramclient.ConfigStruct{},
+		Chats:    interfaces.ChatsConfigStruct{},
+		Webhook:  interfaces.WebhookConfigStruct{},
+		Polling:  interfaces.PollingConfigStruct{},
+		Queue:    interfaces.QueueConfigStruct{},
+		Dedup:    interfaces.DeduplicationConfigStruct{},
+		Plusplus: interfaces.PlusplusConfigStruct{},
 	}
 }
 
@@ -0,0 +1,9 @@
+package config
+
+import _ "embed"
+
+// RollDefaults contains the default flavour texts of the roll matcher.
+// They apply to all languages and die sizes that config/roll.yaml doesn't define.
+//
+//go:embed roll.yml
+var RollDefaults []byte
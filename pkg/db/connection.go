@@ -51,3 +51,17 @@ func NewConnection(logger logger.Interface, config interfaces.DatabaseConfigStru
 
 	return db
 }
+
+// CloseConnection closes the connection pool behind the given gorm instance.
+func CloseConnection(db *gorm.DB) error {
+	if db == nil {
+		return nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.Close()
+}
@@ -1,65 +1,178 @@
 package db
 
 import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
 	logger "github.com/br0-space/bot-logger"
 	"github.com/br0-space/bot/interfaces"
+	"gorm.io/gorm"
 )
 
+// Migration is a single numbered schema change. Up and Down run inside a
+// transaction together with the bookkeeping in the schema_migrations table.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration records an applied migration.
+type SchemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
 type DatabaseMigration struct {
-	log              interfaces.LoggerInterface
-	messageStatsRepo interfaces.MessageStatsRepoInterface
-	plusplusRepo     interfaces.PlusplusRepoInterface
-	rollRepo         interfaces.RollRepoInterface
-	userStatsRepo    interfaces.UserStatsRepoInterface
+	log        interfaces.LoggerInterface
+	tx         *gorm.DB
+	migrations []Migration
 }
 
-func MakeDatabaseMigration(
-	messageStatsRepo interfaces.MessageStatsRepoInterface,
-	plusplusRepo interfaces.PlusplusRepoInterface,
-	rollRepo interfaces.RollRepoInterface,
-	userStatsRepo interfaces.UserStatsRepoInterface,
-) DatabaseMigration {
+func MakeDatabaseMigration(tx *gorm.DB, migrations []Migration) DatabaseMigration {
+	migrations = slices.Clone(migrations)
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
 	return DatabaseMigration{
-		log:              logger.New(),
-		messageStatsRepo: messageStatsRepo,
-		plusplusRepo:     plusplusRepo,
-		rollRepo:         rollRepo,
-		userStatsRepo:    userStatsRepo,
+		log:        logger.New(),
+		tx:         tx,
+		migrations: migrations,
 	}
 }
 
 func (m DatabaseMigration) Migrate() error {
-	if repo, ok := m.messageStatsRepo.(interfaces.RepoInterface); ok {
-		m.log.Debug("Migrating table", repo.TableName())
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
 
-		if err := repo.Migrate(); err != nil {
-			return err
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		m.log.Infof("Applying migration %d %s", migration.Version, migration.Name)
+
+		if err := m.tx.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+
+			return tx.Create(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		}); err != nil {
+			return fmt.Errorf("migration %d %s failed: %w", migration.Version, migration.Name, err)
 		}
 	}
 
-	if repo, ok := m.plusplusRepo.(interfaces.RepoInterface); ok {
-		m.log.Debug("Migrating table", repo.TableName())
+	return nil
+}
 
-		if err := repo.Migrate(); err != nil {
-			return err
+func (m DatabaseMigration) Rollback() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range slices.Backward(m.migrations) {
+		if _, ok := applied[migration.Version]; ok {
+			return m.rollback(migration)
 		}
 	}
 
-	if repo, ok := m.rollRepo.(interfaces.RepoInterface); ok {
-		m.log.Debug("Migrating table", repo.TableName())
+	m.log.Info("No migration to roll back")
+
+	return nil
+}
+
+// RollbackTo reverts all applied migrations newer than the given version, newest first.
+func (m DatabaseMigration) RollbackTo(version uint) error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range slices.Backward(m.migrations) {
+		if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+			continue
+		}
 
-		if err := repo.Migrate(); err != nil {
+		if err := m.rollback(migration); err != nil {
 			return err
 		}
 	}
 
-	if repo, ok := m.userStatsRepo.(interfaces.RepoInterface); ok {
-		m.log.Debug("Migrating table", repo.TableName())
+	return nil
+}
+
+func (m DatabaseMigration) Status() ([]interfaces.MigrationStatusStruct, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]interfaces.MigrationStatusStruct, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		var appliedAt *time.Time
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt = &record.AppliedAt
+		}
+
+		status = append(status, interfaces.MigrationStatusStruct{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: appliedAt,
+		})
+	}
 
-		if err := repo.Migrate(); err != nil {
+	return status, nil
+}
+
+// rollback reverts a single migration together with its record.
+func (m DatabaseMigration) rollback(migration Migration) error {
+	m.log.Infof("Rolling back migration %d %s", migration.Version, migration.Name)
+
+	if err := m.tx.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Down(tx); err != nil {
 			return err
 		}
+
+		return tx.Delete(&SchemaMigration{}, migration.Version).Error
+	}); err != nil {
+		return fmt.Errorf("rollback of migration %d %s failed: %w", migration.Version, migration.Name, err)
 	}
 
 	return nil
 }
+
+// applied creates the schema_migrations table if needed and returns its records by version.
+func (m DatabaseMigration) applied() (map[uint]SchemaMigration, error) {
+	if err := m.tx.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var records []SchemaMigration
+	if err := m.tx.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,252 @@
+package db_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	logger "github.com/br0-space/bot-logger"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/db"
+	"github.com/br0-space/bot/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var tables = []string{"plusplus", "stats", "message_stats", "rolls", "processed_updates", "matcher_settings", "plusplus_events", "plusplus_aliases", "plusplus_corrections", "roll_macros"}
+
+// provideDatabase opens a fresh SQLite database in a temporary directory.
+func provideDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	cfg := interfaces.DatabaseConfigStruct{Driver: "sqlite"}
+	cfg.SQLite.File = filepath.Join(t.TempDir(), "test.db")
+
+	conn := db.NewConnection(logger.New(), cfg)
+	require.NotNil(t, conn)
+
+	t.Cleanup(func() {
+		_ = db.CloseConnection(conn)
+	})
+
+	return conn
+}
+
+func TestDatabaseMigration_UpAndDown(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	migrations := db.Migrations(789)
+	migration := db.MakeDatabaseMigration(conn, migrations)
+
+	require.NoError(t, migration.Migrate())
+	require.NoError(t, migration.Migrate())
+
+	status, err := migration.Status()
+	require.NoError(t, err)
+	require.Len(t, status, len(migrations))
+
+	for i, step := range status {
+		assert.Equal(t, uint(i+1), step.Version)
+		assert.NotNil(t, step.AppliedAt, step.Name)
+	}
+
+	for _, table := range tables {
+		assert.True(t, conn.Migrator().HasTable(table), table)
+	}
+
+	plusplusRepo := repo.NewPlusplusRepo(conn)
+
+	_, err = plusplusRepo.Increment(1, 0, 0, "foo", 1)
+	require.NoError(t, err)
+
+	_, err = plusplusRepo.Increment(2, 0, 0, "bar", 1)
+	require.NoError(t, err)
+
+	for range migrations {
+		require.NoError(t, migration.Rollback())
+	}
+
+	require.NoError(t, migration.Rollback())
+
+	for _, table := range tables {
+		assert.False(t, conn.Migrator().HasTable(table), table)
+	}
+
+	status, err = migration.Status()
+	require.NoError(t, err)
+
+	for _, step := range status {
+		assert.Nil(t, step.AppliedAt, step.Name)
+	}
+
+	require.NoError(t, migration.Migrate())
+}
+
+func TestDatabaseMigration_StepwiseDownAndUp(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	migration := db.MakeDatabaseMigration(conn, db.Migrations(789))
+
+	require.NoError(t, migration.Migrate())
+
+	_, err := repo.NewPlusplusRepo(conn).Increment(789, 0, 0, "foo", 3)
+	require.NoError(t, err)
+
+	// Go back to the counters as they were before the chat scope
+	require.NoError(t, migration.RollbackTo(2))
+
+	status, err := migration.Status()
+	require.NoError(t, err)
+
+	for _, step := range status {
+		assert.Equal(t, step.Version <= 2, step.AppliedAt != nil, step.Name)
+	}
+
+	assert.False(t, conn.Migrator().HasColumn("plusplus", "chat_id"))
+	assert.True(t, conn.Migrator().HasIndex("plusplus", "idx_plusplus_name"))
+
+	require.NoError(t, migration.Migrate())
+
+	tops, err := repo.NewPlusplusRepo(conn).FindTops(789, 10)
+	require.NoError(t, err)
+	require.Len(t, tops, 1)
+	assert.Equal(t, 3, tops[0].Value)
+}
+
+func TestDatabaseMigration_DownWithConflictingChats(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	migration := db.MakeDatabaseMigration(conn, db.Migrations(789))
+
+	require.NoError(t, migration.Migrate())
+
+	plusplusRepo := repo.NewPlusplusRepo(conn)
+
+	_, err := plusplusRepo.Increment(1, 0, 0, "foo", 1)
+	require.NoError(t, err)
+
+	_, err = plusplusRepo.Increment(2, 0, 0, "foo", 1)
+	require.NoError(t, err)
+
+	// The same term in two chats can't go back to a table with unique names
+	require.NoError(t, migration.RollbackTo(3))
+	require.Error(t, migration.Rollback())
+	assert.True(t, conn.Migrator().HasColumn("plusplus", "chat_id"))
+
+	status, err := migration.Status()
+	require.NoError(t, err)
+	assert.NotNil(t, status[2].AppliedAt)
+}
+
+func TestDatabaseMigration_PlusplusUserTerms(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	migration := db.MakeDatabaseMigration(conn, db.Migrations(789))
+
+	// Go back to before the user terms
+	require.NoError(t, migration.Migrate())
+	require.NoError(t, migration.RollbackTo(7))
+
+	// Terms of chat members as they were stored before
+	require.NoError(t, conn.Exec("INSERT INTO stats (chat_id, user_id, username, posts) VALUES (789, 42, '@Alice', 1)").Error)
+	require.NoError(t, conn.Exec("INSERT INTO plusplus (chat_id, name, value) VALUES (789, '@alice', 3), (789, '@bob', 2)").Error)
+	require.NoError(t, conn.Exec("INSERT INTO plusplus_events (chat_id, giver_user_id, name, delta, message_id) VALUES (789, 1, '@alice', 3, 1)").Error)
+
+	require.NoError(t, migration.Migrate())
+
+	plusplusRepo := repo.NewPlusplusRepo(conn)
+
+	tops, err := plusplusRepo.FindTops(789, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.Plusplus{
+		{ChatID: 789, Name: "@Alice", Value: 3},
+		{ChatID: 789, Name: "@bob", Value: 2},
+	}, tops)
+
+	changes, err := plusplusRepo.FindRecentChanges(789, "@alice", 10)
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+
+	aliases, err := plusplusRepo.FindAliases(789)
+	require.NoError(t, err)
+	require.Len(t, aliases, 1)
+	assert.Equal(t, "@Alice", aliases[0].Name)
+}
+
+func TestDatabaseMigration_LegacyDatabase(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+
+	// Table layout created by AutoMigrate before versioned migrations existed
+	require.NoError(t, conn.Exec(`CREATE TABLE plusplus (
+		id integer PRIMARY KEY AUTOINCREMENT,
+		created_at datetime,
+		updated_at datetime,
+		deleted_at datetime,
+		name text,
+		value integer
+	)`).Error)
+	require.NoError(t, conn.Exec(`CREATE UNIQUE INDEX idx_plusplus_name ON plusplus(name)`).Error)
+	require.NoError(t, conn.Exec(`INSERT INTO plusplus (name, value) VALUES ('foo', 5)`).Error)
+
+	require.NoError(t, db.MakeDatabaseMigration(conn, db.Migrations(789)).Migrate())
+	assert.False(t, conn.Migrator().HasIndex("plusplus", "idx_plusplus_name"))
+
+	plusplusRepo := repo.NewPlusplusRepo(conn)
+
+	tops, err := plusplusRepo.FindTops(789, 10)
+	require.NoError(t, err)
+	require.Len(t, tops, 1)
+	assert.Equal(t, "foo", tops[0].Name)
+	assert.Equal(t, 5, tops[0].Value)
+
+	value, err := plusplusRepo.Increment(999, 0, 0, "foo", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+}
+
+func TestDatabaseMigration_FailingStep(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	migration := db.MakeDatabaseMigration(conn, []db.Migration{{
+		Version: 2,
+		Name:    "broken",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec(`CREATE TABLE foo (id integer)`).Error; err != nil {
+				return err
+			}
+
+			return errors.New("something went wrong")
+		},
+		Down: func(_ *gorm.DB) error {
+			return nil
+		},
+	}, {
+		Version: 1,
+		Name:    "working",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec(`CREATE TABLE bar (id integer)`).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Exec(`DROP TABLE bar`).Error
+		},
+	}})
+
+	require.Error(t, migration.Migrate())
+	assert.True(t, conn.Migrator().HasTable("bar"))
+	assert.False(t, conn.Migrator().HasTable("foo"))
+
+	status, err := migration.Status()
+	require.NoError(t, err)
+	require.Len(t, status, 2)
+	assert.Equal(t, "working", status[0].Name)
+	assert.NotNil(t, status[0].AppliedAt)
+	assert.Nil(t, status[1].AppliedAt)
+}
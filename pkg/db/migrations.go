@@ -0,0 +1,546 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	logger "github.com/br0-space/bot-logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Migrations returns all schema migrations in the order they have to be applied.
+// Every migration works on its own copy of the models as they looked at that
+// point, so later changes to the models in interfaces don't alter old migrations.
+// chatID is the chat that records from before multi-chat support are assigned to.
+func Migrations(chatID int64) []Migration {
+	return []Migration{
+		migrationInitialSchema(),
+		migrationProcessedUpdates(),
+		migrationChatScope(chatID),
+		migrationMatcherSettings(),
+		migrationPlusplusEvents(),
+		migrationPlusplusAliases(),
+		migrationPlusplusReasons(),
+		migrationPlusplusUserTerms(),
+		migrationPlusplusCorrections(),
+		migrationPlusplusEventTokens(),
+		migrationRollMacros(),
+		migrationRollDetails(),
+		migrationRollHidden(),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type plusplusV1 struct {
+	gorm.Model
+
+	Name  string `gorm:"<-:create;uniqueIndex"`
+	Value int    `gorm:"<-;index"`
+}
+
+func (plusplusV1) TableName() string { return "plusplus" }
+
+type statsV1 struct {
+	gorm.Model
+
+	UserID   int64  `gorm:"<-:create;uniqueIndex"`
+	Username string `gorm:"<-"`
+	Posts    uint32 `gorm:"<-"`
+	LastPost time.Time
+}
+
+func (statsV1) TableName() string { return "stats" }
+
+type messageStatsV1 struct {
+	gorm.Model
+
+	UserID int64     `gorm:"<-:create;index"`
+	Time   time.Time `gorm:"<-:create;index"`
+	Words  int       `gorm:"<-:create"`
+}
+
+func (messageStatsV1) TableName() string { return "message_stats" }
+
+type rollV1 struct {
+	gorm.Model
+
+	UserID          int64  `gorm:"not null;index"`
+	DiceCount       int    `gorm:"not null"`
+	DiceSides       int    `gorm:"not null"`
+	Results         string `gorm:"type:text;not null"`
+	Total           int    `gorm:"not null"`
+	Threshold       *int   `gorm:"index"`
+	KeepHighest     bool   `gorm:"not null;default:false"`
+	Success         *bool
+	CriticalHit     bool `gorm:"not null;default:false;index"`
+	CriticalFailure bool `gorm:"not null;default:false;index"`
+}
+
+func (rollV1) TableName() string { return "rolls" }
+
+// migrationInitialSchema creates the tables as they were managed by AutoMigrate
+// before versioned migrations existed. On such databases it changes nothing.
+func migrationInitialSchema() Migration {
+	return Migration{
+		Version: 1,
+		Name:    "initial_schema",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&plusplusV1{}, &statsV1{}, &messageStatsV1{}, &rollV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&plusplusV1{}, &statsV1{}, &messageStatsV1{}, &rollV1{})
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type processedUpdateV2 struct {
+	gorm.Model
+
+	UpdateID int64 `gorm:"<-:create;uniqueIndex"`
+}
+
+func (processedUpdateV2) TableName() string { return "processed_updates" }
+
+func migrationProcessedUpdates() Migration {
+	return Migration{
+		Version: 2,
+		Name:    "processed_updates",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&processedUpdateV2{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&processedUpdateV2{})
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type plusplusV3 struct {
+	gorm.Model
+
+	ChatID int64  `gorm:"<-:create;not null;default:0;uniqueIndex:idx_plusplus_chat_name"`
+	Name   string `gorm:"<-:create;uniqueIndex:idx_plusplus_chat_name"`
+	Value  int    `gorm:"<-;index"`
+}
+
+func (plusplusV3) TableName() string { return "plusplus" }
+
+type statsV3 struct {
+	gorm.Model
+
+	ChatID   int64  `gorm:"<-:create;not null;default:0;uniqueIndex:idx_stats_chat_user"`
+	UserID   int64  `gorm:"<-:create;uniqueIndex:idx_stats_chat_user"`
+	Username string `gorm:"<-"`
+	Posts    uint32 `gorm:"<-"`
+	LastPost time.Time
+}
+
+func (statsV3) TableName() string { return "stats" }
+
+type messageStatsV3 struct {
+	gorm.Model
+
+	ChatID int64     `gorm:"<-:create;not null;default:0;index"`
+	UserID int64     `gorm:"<-:create;index"`
+	Time   time.Time `gorm:"<-:create;index"`
+	Words  int       `gorm:"<-:create"`
+}
+
+func (messageStatsV3) TableName() string { return "message_stats" }
+
+type rollV3 struct {
+	rollV1
+
+	ChatID int64 `gorm:"not null;default:0;index"`
+}
+
+func (rollV3) TableName() string { return "rolls" }
+
+// migrationChatScope adds a chat ID to all records so several chats can use the
+// bot independently. Unique indexes now include the chat, and existing records
+// are assigned to the configured chat.
+func migrationChatScope(chatID int64) Migration {
+	return Migration{
+		Version: 3,
+		Name:    "chat_scope",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&plusplusV3{}, &statsV3{}, &messageStatsV3{}, &rollV3{}); err != nil {
+				return err
+			}
+
+			if err := dropIndexIfExists(tx, "plusplus", "idx_plusplus_name"); err != nil {
+				return err
+			}
+
+			if err := dropIndexIfExists(tx, "stats", "idx_stats_user_id"); err != nil {
+				return err
+			}
+
+			if chatID == 0 {
+				logger.New().Warning("No chat ID configured, not assigning existing records to a chat")
+
+				return nil
+			}
+
+			for _, table := range []string{"plusplus", "stats", "message_stats", "rolls"} {
+				if err := tx.Table(table).Where("chat_id = ?", 0).Update("chat_id", chatID).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, table := range []struct {
+				model any
+				index string
+			}{
+				{&plusplusV3{}, "idx_plusplus_chat_name"},
+				{&statsV3{}, "idx_stats_chat_user"},
+				{&messageStatsV3{}, "idx_message_stats_chat_id"},
+				{&rollV3{}, "idx_rolls_chat_id"},
+			} {
+				if err := dropIndexIfExists(tx, table.model, table.index); err != nil {
+					return err
+				}
+
+				if err := tx.Migrator().DropColumn(table.model, "ChatID"); err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Migrator().CreateIndex(&plusplusV1{}, "Name"); err != nil {
+				return err
+			}
+
+			return tx.Migrator().CreateIndex(&statsV1{}, "UserID")
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type matcherSettingV4 struct {
+	gorm.Model
+
+	ChatID     int64  `gorm:"<-:create;not null;uniqueIndex:idx_matcher_settings_chat_identifier"`
+	Identifier string `gorm:"<-:create;not null;uniqueIndex:idx_matcher_settings_chat_identifier"`
+	Enabled    bool   `gorm:"<-;not null"`
+}
+
+func (matcherSettingV4) TableName() string { return "matcher_settings" }
+
+func migrationMatcherSettings() Migration {
+	return Migration{
+		Version: 4,
+		Name:    "matcher_settings",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&matcherSettingV4{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&matcherSettingV4{})
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type plusplusEventV5 struct {
+	gorm.Model
+
+	ChatID      int64  `gorm:"<-:create;not null;index:idx_plusplus_events_chat_name"`
+	GiverUserID int64  `gorm:"<-:create;not null;index"`
+	Name        string `gorm:"<-:create;not null;index:idx_plusplus_events_chat_name"`
+	Delta       int    `gorm:"<-:create;not null"`
+	MessageID   int64  `gorm:"<-:create;not null"`
+}
+
+func (plusplusEventV5) TableName() string { return "plusplus_events" }
+
+// migrationPlusplusEvents adds the log of all plusplus changes. Values from
+// before this migration have no events, so the log starts empty.
+func migrationPlusplusEvents() Migration {
+	return Migration{
+		Version: 5,
+		Name:    "plusplus_events",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&plusplusEventV5{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&plusplusEventV5{})
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type plusplusAliasV6 struct {
+	gorm.Model
+
+	ChatID int64  `gorm:"<-:create;not null;uniqueIndex:idx_plusplus_aliases_chat_alias"`
+	Alias  string `gorm:"<-:create;not null;uniqueIndex:idx_plusplus_aliases_chat_alias"`
+	Name   string `gorm:"<-;not null;index"`
+}
+
+func (plusplusAliasV6) TableName() string { return "plusplus_aliases" }
+
+func migrationPlusplusAliases() Migration {
+	return Migration{
+		Version: 6,
+		Name:    "plusplus_aliases",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&plusplusAliasV6{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&plusplusAliasV6{})
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type plusplusEventV7 struct {
+	plusplusEventV5
+
+	Reason string `gorm:"<-:create;not null;default:''"`
+}
+
+func (plusplusEventV7) TableName() string { return "plusplus_events" }
+
+func migrationPlusplusReasons() Migration {
+	return Migration{
+		Version: 7,
+		Name:    "plusplus_reasons",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&plusplusEventV7{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&plusplusEventV7{}, "Reason")
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// migrationPlusplusUserTerms moves terms like "@alice" that were stored as
+// typed onto the term of the chat member with that username, and keeps the
+// old term as an alias. Terms of unknown users are left alone. The terms
+// can't be told apart afterwards, so rolling back keeps them as they are.
+func migrationPlusplusUserTerms() Migration {
+	return Migration{
+		Version: 8,
+		Name:    "plusplus_user_terms",
+		Up: func(tx *gorm.DB) error {
+			var terms []plusplusV3
+			if err := tx.Where("name LIKE ?", "@%").Find(&terms).Error; err != nil {
+				return err
+			}
+
+			for _, term := range terms {
+				if err := moveTermToUser(tx, term); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Down: func(_ *gorm.DB) error {
+			return nil
+		},
+	}
+}
+
+func moveTermToUser(tx *gorm.DB, term plusplusV3) error {
+	username := strings.TrimPrefix(term.Name, "@")
+
+	var user statsV3
+	if err := tx.
+		Where("chat_id = ? AND user_id != 0 AND LOWER(username) IN (LOWER(?), LOWER(?))", term.ChatID, username, "@"+username).
+		Limit(1).
+		Find(&user).
+		Error; err != nil || user.ID == 0 {
+		return err
+	}
+
+	userTerm := fmt.Sprintf("user %d", user.UserID)
+
+	if err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "chat_id"}, {Name: "name"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"value": gorm.Expr("plusplus.value + ?", term.Value),
+		}),
+	}).Create(&plusplusV3{ChatID: term.ChatID, Name: userTerm, Value: term.Value}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Unscoped().Delete(&term).Error; err != nil {
+		return err
+	}
+
+	for _, table := range []string{"plusplus_events", "plusplus_aliases"} {
+		if err := tx.
+			Table(table).
+			Where("chat_id = ? AND name = ?", term.ChatID, term.Name).
+			Update("name", userTerm).
+			Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&plusplusAliasV6{
+		ChatID: term.ChatID,
+		Alias:  term.Name,
+		Name:   userTerm,
+	}).Error
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type plusplusCorrectionV9 struct {
+	gorm.Model
+
+	ChatID   int64  `gorm:"<-:create;not null;index"`
+	UserID   int64  `gorm:"<-:create;not null"`
+	Action   string `gorm:"<-:create;not null"`
+	Name     string `gorm:"<-:create;not null"`
+	OldValue int    `gorm:"<-:create;not null"`
+	NewValue int    `gorm:"<-:create;not null"`
+}
+
+func (plusplusCorrectionV9) TableName() string { return "plusplus_corrections" }
+
+func migrationPlusplusCorrections() Migration {
+	return Migration{
+		Version: 9,
+		Name:    "plusplus_corrections",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&plusplusCorrectionV9{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&plusplusCorrectionV9{})
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type plusplusEventV10 struct {
+	plusplusEventV7
+
+	Token bool `gorm:"<-:create;not null;default:false"`
+}
+
+func (plusplusEventV10) TableName() string { return "plusplus_events" }
+
+// migrationPlusplusEventTokens marks the changes made by ++ and -- tokens, so
+// they can be revised when a message is edited. It isn't known for existing
+// events, so they are kept as they are on edits.
+func migrationPlusplusEventTokens() Migration {
+	return Migration{
+		Version: 10,
+		Name:    "plusplus_event_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&plusplusEventV10{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&plusplusEventV10{}, "Token")
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type rollMacroV11 struct {
+	gorm.Model
+
+	UserID   int64  `gorm:"<-:create;not null;uniqueIndex:idx_roll_macros_user_name"`
+	Name     string `gorm:"<-:create;not null;uniqueIndex:idx_roll_macros_user_name"`
+	Notation string `gorm:"not null"`
+}
+
+func (rollMacroV11) TableName() string { return "roll_macros" }
+
+func migrationRollMacros() Migration {
+	return Migration{
+		Version: 11,
+		Name:    "roll_macros",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rollMacroV11{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&rollMacroV11{})
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type rollV12 struct {
+	rollV3
+
+	Notation string `gorm:"not null;default:''"`
+	Faces    string `gorm:"type:text;not null;default:''"`
+}
+
+func (rollV12) TableName() string { return "rolls" }
+
+// migrationRollDetails stores the dice expression and every face rolled per
+// die size. Older rolls keep empty values, their results are plain NdM rolls.
+func migrationRollDetails() Migration {
+	return Migration{
+		Version: 12,
+		Name:    "roll_details",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rollV12{})
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, column := range []string{"Notation", "Faces"} {
+				if err := tx.Migrator().DropColumn(&rollV12{}, column); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type rollV13 struct {
+	rollV12
+
+	Hidden bool `gorm:"not null;default:false"`
+}
+
+func (rollV13) TableName() string { return "rolls" }
+
+// migrationRollHidden marks secret rolls of the GM, which the history doesn't show.
+func migrationRollHidden() Migration {
+	return Migration{
+		Version: 13,
+		Name:    "roll_hidden",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rollV13{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&rollV13{}, "Hidden")
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func dropIndexIfExists(tx *gorm.DB, table any, name string) error {
+	if !tx.Migrator().HasIndex(table, name) {
+		return nil
+	}
+
+	return tx.Migrator().DropIndex(table, name)
+}
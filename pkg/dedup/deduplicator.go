@@ -0,0 +1,126 @@
+package dedup
+
+import (
+	"sync"
+	"time"
+
+	logger "github.com/br0-space/bot-logger"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const (
+	defaultCacheSize = 1000
+	defaultRetention = 24 * time.Hour
+	pruneInterval    = time.Hour
+)
+
+// Deduplicator remembers the IDs of processed Telegram updates, so that
+// updates delivered more than once (e.g. webhook retries) are only processed
+// once. Recent IDs are kept in memory; the database is used as a fallback so
+// duplicates are also detected across restarts.
+type Deduplicator struct {
+	log       logger.Interface
+	repo      interfaces.ProcessedUpdateRepoInterface
+	retention time.Duration
+	lock      sync.Mutex
+	seen      map[int64]struct{}
+	ring      []int64
+	next      int
+	lastPrune time.Time
+}
+
+func NewDeduplicator(
+	config interfaces.DeduplicationConfigStruct,
+	repo interfaces.ProcessedUpdateRepoInterface,
+) *Deduplicator {
+	cacheSize := int(config.CacheSize)
+	if cacheSize <= 0 {
+		cacheSize = defaultCacheSize
+	}
+
+	retention := config.Retention
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+
+	return &Deduplicator{
+		log:       logger.New(),
+		repo:      repo,
+		retention: retention,
+		lock:      sync.Mutex{},
+		seen:      make(map[int64]struct{}, cacheSize),
+		ring:      make([]int64, 0, cacheSize),
+		next:      0,
+		lastPrune: time.Time{},
+	}
+}
+
+// IsDuplicate records the given update ID and reports whether it was already
+// processed before. If the database is unavailable, the update is treated as
+// new, because processing it twice is better than not processing it at all.
+// Only the in-memory cache is guarded by the lock, the database is written
+// afterwards, so a slow database doesn't hold up other updates.
+func (d *Deduplicator) IsDuplicate(updateID int64) bool {
+	if !d.rememberIfNew(updateID) {
+		return true
+	}
+
+	inserted, err := d.repo.Insert(updateID)
+	if err != nil {
+		d.log.Error("Error while recording processed update in DB:", err)
+
+		return false
+	}
+
+	if d.pruneDue() {
+		d.prune()
+	}
+
+	return !inserted
+}
+
+// rememberIfNew adds an update ID to the in-memory cache, evicting the oldest
+// one if the cache is full. It returns false if the ID is already known.
+func (d *Deduplicator) rememberIfNew(updateID int64) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if _, exists := d.seen[updateID]; exists {
+		return false
+	}
+
+	if len(d.ring) < cap(d.ring) {
+		d.ring = append(d.ring, updateID)
+	} else {
+		delete(d.seen, d.ring[d.next])
+		d.ring[d.next] = updateID
+		d.next = (d.next + 1) % len(d.ring)
+	}
+
+	d.seen[updateID] = struct{}{}
+
+	return true
+}
+
+// pruneDue checks if the last pruning is longer ago than the prune interval.
+// If so, it claims the pruning, so only one caller prunes at a time.
+func (d *Deduplicator) pruneDue() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if time.Since(d.lastPrune) < pruneInterval {
+		return false
+	}
+
+	d.lastPrune = time.Now()
+
+	return true
+}
+
+// prune removes update IDs from the database that are older than the
+// retention period. Telegram does not redeliver updates after 24 hours.
+func (d *Deduplicator) prune() {
+	if err := d.repo.DeleteOlderThan(time.Now().Add(-d.retention)); err != nil {
+		d.log.Error("Error while pruning processed updates in DB:", err)
+	}
+}
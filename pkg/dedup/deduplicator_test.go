@@ -0,0 +1,140 @@
+package dedup_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/dedup"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeProcessedUpdateRepo stores update IDs in memory, mimicking the table.
+type fakeProcessedUpdateRepo struct {
+	ids     map[int64]bool
+	inserts int
+	err     error
+}
+
+func newFakeProcessedUpdateRepo() *fakeProcessedUpdateRepo {
+	return &fakeProcessedUpdateRepo{ids: make(map[int64]bool)}
+}
+
+func (r *fakeProcessedUpdateRepo) Insert(updateID int64) (bool, error) {
+	r.inserts++
+
+	if r.err != nil {
+		return false, r.err
+	}
+
+	if r.ids[updateID] {
+		return false, nil
+	}
+
+	r.ids[updateID] = true
+
+	return true, nil
+}
+
+func (r *fakeProcessedUpdateRepo) DeleteOlderThan(_ time.Time) error {
+	return nil
+}
+
+func provideConfig(cacheSize uint) interfaces.DeduplicationConfigStruct {
+	return interfaces.DeduplicationConfigStruct{
+		CacheSize: cacheSize,
+		Retention: time.Hour,
+	}
+}
+
+func TestDeduplicator_IsDuplicate(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeProcessedUpdateRepo()
+	d := dedup.NewDeduplicator(provideConfig(10), repo)
+
+	assert.False(t, d.IsDuplicate(1))
+	assert.False(t, d.IsDuplicate(2))
+	assert.True(t, d.IsDuplicate(1))
+	assert.True(t, d.IsDuplicate(2))
+
+	// Duplicates are answered from memory without touching the database
+	assert.Equal(t, 2, repo.inserts)
+}
+
+func TestDeduplicator_SurvivesRestart(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeProcessedUpdateRepo()
+
+	assert.False(t, dedup.NewDeduplicator(provideConfig(10), repo).IsDuplicate(1))
+	assert.True(t, dedup.NewDeduplicator(provideConfig(10), repo).IsDuplicate(1))
+}
+
+func TestDeduplicator_EvictsFromMemory(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeProcessedUpdateRepo()
+	d := dedup.NewDeduplicator(provideConfig(2), repo)
+
+	assert.False(t, d.IsDuplicate(1))
+	assert.False(t, d.IsDuplicate(2))
+	assert.False(t, d.IsDuplicate(3))
+
+	// 1 was evicted from memory, but is still known to the database
+	assert.True(t, d.IsDuplicate(1))
+	assert.Equal(t, 4, repo.inserts)
+}
+
+func TestDeduplicator_DatabaseError(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeProcessedUpdateRepo()
+	repo.err = errors.New("database is gone")
+	d := dedup.NewDeduplicator(provideConfig(10), repo)
+
+	assert.False(t, d.IsDuplicate(1))
+	assert.True(t, d.IsDuplicate(1))
+}
+
+// blockingProcessedUpdateRepo holds inserts until it's released.
+type blockingProcessedUpdateRepo struct {
+	started chan int64
+	release chan struct{}
+}
+
+func (r *blockingProcessedUpdateRepo) Insert(updateID int64) (bool, error) {
+	r.started <- updateID
+	<-r.release
+
+	return true, nil
+}
+
+func (r *blockingProcessedUpdateRepo) DeleteOlderThan(_ time.Time) error {
+	return nil
+}
+
+func TestDeduplicator_SlowDatabase(t *testing.T) {
+	t.Parallel()
+
+	repo := &blockingProcessedUpdateRepo{started: make(chan int64, 2), release: make(chan struct{})}
+	d := dedup.NewDeduplicator(provideConfig(10), repo)
+
+	results := make(chan bool, 2)
+
+	go func() { results <- d.IsDuplicate(1) }()
+
+	assert.Equal(t, int64(1), <-repo.started)
+
+	// While the database is busy, a redelivery is known from memory and other updates aren't held up
+	assert.True(t, d.IsDuplicate(1))
+
+	go func() { results <- d.IsDuplicate(2) }()
+
+	assert.Equal(t, int64(2), <-repo.started)
+
+	close(repo.release)
+	assert.False(t, <-results)
+	assert.False(t, <-results)
+}
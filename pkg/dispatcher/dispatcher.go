@@ -0,0 +1,200 @@
+package dispatcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	logger "github.com/br0-space/bot-logger"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const (
+	defaultQueueSize = 100
+	defaultWorkers   = 4
+)
+
+type queuedMessage struct {
+	messageIn  telegramclient.WebhookMessageStruct
+	entities   []interfaces.MessageEntityStruct
+	edited     bool
+	enqueuedAt time.Time
+}
+
+// Dispatcher decouples receiving messages from processing them. Incoming
+// messages are put into a bounded queue and processed by a pool of workers.
+// Every chat is assigned to a fixed worker, so messages of the same chat are
+// processed in the order they were received. On shutdown, the queue is
+// drained instead of dropping replies or database writes. Edited messages
+// share the queues, so an edit is never processed before its message.
+type Dispatcher struct {
+	log       logger.Interface
+	fn        func(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct)
+	editFn    func(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct)
+	queues    []chan queuedMessage
+	lock      sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	pending   sync.WaitGroup
+	workers   sync.WaitGroup
+}
+
+func NewDispatcher(
+	config interfaces.QueueConfigStruct,
+	fn func(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct),
+	editFn func(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct),
+) *Dispatcher {
+	size := int(config.Size)
+	if size <= 0 {
+		size = defaultQueueSize
+	}
+
+	workers := int(config.Workers)
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	// Distribute the capacity evenly, rounded up so no queue ends up empty
+	perWorker := (size + workers - 1) / workers
+
+	d := &Dispatcher{
+		log:       logger.New(),
+		fn:        fn,
+		editFn:    editFn,
+		queues:    make([]chan queuedMessage, workers),
+		lock:      sync.RWMutex{},
+		closed:    false,
+		closeOnce: sync.Once{},
+		pending:   sync.WaitGroup{},
+		workers:   sync.WaitGroup{},
+	}
+
+	for i := range d.queues {
+		d.queues[i] = make(chan queuedMessage, perWorker)
+
+		d.workers.Add(1)
+
+		go d.work(d.queues[i])
+	}
+
+	queueCapacity.Set(float64(perWorker * workers))
+
+	return d
+}
+
+// Dispatch puts a message into the queue of the worker responsible for its
+// chat. If that queue is full, Dispatch blocks until there is free space.
+// Messages arriving after Shutdown was called are dropped.
+func (d *Dispatcher) Dispatch(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct) {
+	d.enqueue(messageIn, entities, false)
+}
+
+// DispatchEdit works like Dispatch for edited messages.
+func (d *Dispatcher) DispatchEdit(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct) {
+	d.enqueue(messageIn, entities, true)
+}
+
+func (d *Dispatcher) enqueue(
+	messageIn telegramclient.WebhookMessageStruct,
+	entities []interfaces.MessageEntityStruct,
+	edited bool,
+) {
+	d.lock.RLock()
+
+	if d.closed {
+		d.lock.RUnlock()
+		d.log.Warningf("Dropping message %d received during shutdown", messageIn.ID)
+		droppedTotal.Inc()
+
+		return
+	}
+
+	d.pending.Add(1)
+	d.lock.RUnlock()
+
+	defer d.pending.Done()
+
+	queue := d.queues[d.shard(messageIn.Chat.ID)]
+	item := queuedMessage{
+		messageIn:  messageIn,
+		entities:   entities,
+		edited:     edited,
+		enqueuedAt: time.Now(),
+	}
+
+	queueLength.Inc()
+
+	select {
+	case queue <- item:
+	default:
+		queueFullTotal.Inc()
+		d.log.Warningf("Queue for chat %d is full, waiting for free space", messageIn.Chat.ID)
+
+		queue <- item
+	}
+}
+
+// Shutdown stops accepting new messages and waits until all queued and
+// in-flight messages are processed or the context expires.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.lock.Lock()
+	d.closed = true
+	d.lock.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		d.pending.Wait()
+		d.closeOnce.Do(func() {
+			for _, queue := range d.queues {
+				close(queue)
+			}
+		})
+		d.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) shard(chatID int64) int {
+	return int(uint64(chatID) % uint64(len(d.queues))) //nolint:gosec
+}
+
+func (d *Dispatcher) work(queue <-chan queuedMessage) {
+	defer d.workers.Done()
+
+	for item := range queue {
+		queueLength.Dec()
+		queueWaitSeconds.Observe(time.Since(item.enqueuedAt).Seconds())
+
+		start := time.Now()
+
+		d.process(item)
+
+		processingSeconds.Observe(time.Since(start).Seconds())
+		processedTotal.Inc()
+	}
+}
+
+// process runs the processing function and recovers from panics, so that a
+// single broken message does not take down the worker.
+func (d *Dispatcher) process(item queuedMessage) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.log.Errorf("Panic while processing message %d: %v", item.messageIn.ID, r)
+		}
+	}()
+
+	if item.edited {
+		d.editFn(item.messageIn, item.entities)
+	} else {
+		d.fn(item.messageIn, item.entities)
+	}
+}
@@ -0,0 +1,273 @@
+package dispatcher_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/dispatcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingClient struct {
+	mu       sync.Mutex
+	messages []telegramclient.MessageStruct
+}
+
+func (c *recordingClient) SendMessage(chatID int64, messageOut telegramclient.MessageStruct) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	messageOut.ChatID = chatID
+	c.messages = append(c.messages, messageOut)
+
+	return nil
+}
+
+func (c *recordingClient) Messages() []telegramclient.MessageStruct {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.messages
+}
+
+func provideQueueConfig(size uint, workers uint) interfaces.QueueConfigStruct {
+	return interfaces.QueueConfigStruct{
+		Size:    size,
+		Workers: workers,
+	}
+}
+
+func newTestMessage(chatID int64, messageID int64) telegramclient.WebhookMessageStruct {
+	messageIn := telegramclient.TestWebhookMessage("foo")
+	messageIn.Chat.ID = chatID
+	messageIn.ID = messageID
+
+	return messageIn
+}
+
+// provideBlockingDispatcher returns a dispatcher whose processing function
+// signals when it starts and only replies after release is closed.
+func provideBlockingDispatcher(client *recordingClient) (*dispatcher.Dispatcher, chan struct{}, chan struct{}) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	d := dispatcher.NewDispatcher(provideQueueConfig(1, 1), func(messageIn telegramclient.WebhookMessageStruct, _ []interfaces.MessageEntityStruct) {
+		started <- struct{}{}
+
+		<-release
+
+		_ = client.SendMessage(messageIn.Chat.ID, telegramclient.Reply("pong", messageIn.ID))
+	}, nil)
+
+	return d, started, release
+}
+
+func TestDispatcher_ShutdownDrainsInFlightMessages(t *testing.T) {
+	t.Parallel()
+
+	client := &recordingClient{}
+	d, started, release := provideBlockingDispatcher(client)
+
+	go d.Dispatch(telegramclient.TestWebhookMessage("/ping"), nil)
+
+	<-started
+
+	shutdownDone := make(chan error)
+
+	go func() {
+		shutdownDone <- d.Shutdown(t.Context())
+	}()
+
+	select {
+	case <-shutdownDone:
+		t.Fatal("shutdown returned while a message was still in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	require.NoError(t, <-shutdownDone)
+	assert.Equal(t, []telegramclient.MessageStruct{
+		telegramclient.ReplyToChat("pong", 123, 789),
+	}, client.Messages())
+}
+
+func TestDispatcher_ShutdownDeadline(t *testing.T) {
+	t.Parallel()
+
+	client := &recordingClient{}
+	d, started, release := provideBlockingDispatcher(client)
+
+	defer close(release)
+
+	go d.Dispatch(telegramclient.TestWebhookMessage("/ping"), nil)
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, d.Shutdown(ctx), context.DeadlineExceeded)
+	assert.Empty(t, client.Messages())
+}
+
+func TestDispatcher_DispatchAfterShutdown(t *testing.T) {
+	t.Parallel()
+
+	processed := false
+	d := dispatcher.NewDispatcher(provideQueueConfig(1, 1), func(_ telegramclient.WebhookMessageStruct, _ []interfaces.MessageEntityStruct) {
+		processed = true
+	}, nil)
+
+	require.NoError(t, d.Shutdown(t.Context()))
+
+	d.Dispatch(telegramclient.TestWebhookMessage("/ping"), nil)
+
+	assert.False(t, processed)
+}
+
+func TestDispatcher_DispatchIsAsynchronous(t *testing.T) {
+	t.Parallel()
+
+	client := &recordingClient{}
+	d, started, release := provideBlockingDispatcher(client)
+
+	d.Dispatch(telegramclient.TestWebhookMessage("/ping"), nil)
+
+	<-started
+
+	assert.Empty(t, client.Messages())
+
+	close(release)
+
+	require.NoError(t, d.Shutdown(t.Context()))
+	assert.Len(t, client.Messages(), 1)
+}
+
+func TestDispatcher_PerChatOrdering(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu        sync.Mutex
+		processed = make(map[int64][]int64)
+	)
+
+	d := dispatcher.NewDispatcher(provideQueueConfig(10, 3), func(messageIn telegramclient.WebhookMessageStruct, _ []interfaces.MessageEntityStruct) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		processed[messageIn.Chat.ID] = append(processed[messageIn.Chat.ID], messageIn.ID)
+	}, nil)
+
+	expected := make(map[int64][]int64)
+
+	for messageID := range int64(50) {
+		for _, chatID := range []int64{-100, 1, 2, 3} {
+			d.Dispatch(newTestMessage(chatID, messageID), nil)
+			expected[chatID] = append(expected[chatID], messageID)
+		}
+	}
+
+	require.NoError(t, d.Shutdown(t.Context()))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, expected, processed)
+}
+
+func TestDispatcher_BackpressureWhenQueueIsFull(t *testing.T) {
+	t.Parallel()
+
+	client := &recordingClient{}
+	d, started, release := provideBlockingDispatcher(client)
+
+	// First message is picked up by the only worker, second one fills the queue
+	d.Dispatch(newTestMessage(789, 1), nil)
+
+	<-started
+
+	d.Dispatch(newTestMessage(789, 2), nil)
+
+	dispatched := make(chan struct{})
+
+	go func() {
+		d.Dispatch(newTestMessage(789, 3), nil)
+		close(dispatched)
+	}()
+
+	select {
+	case <-dispatched:
+		t.Fatal("dispatch did not wait for free queue space")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	go func() {
+		for range started {
+		}
+	}()
+
+	<-dispatched
+
+	require.NoError(t, d.Shutdown(t.Context()))
+	assert.Len(t, client.Messages(), 3)
+}
+
+func TestDispatcher_RecoversFromPanics(t *testing.T) {
+	t.Parallel()
+
+	var processed atomic.Int32
+
+	d := dispatcher.NewDispatcher(provideQueueConfig(10, 1), func(messageIn telegramclient.WebhookMessageStruct, _ []interfaces.MessageEntityStruct) {
+		if messageIn.ID == 1 {
+			panic("boom")
+		}
+
+		processed.Add(1)
+	}, nil)
+
+	d.Dispatch(newTestMessage(789, 1), nil)
+	d.Dispatch(newTestMessage(789, 2), nil)
+
+	require.NoError(t, d.Shutdown(t.Context()))
+	assert.Equal(t, int32(1), processed.Load())
+}
+
+func TestDispatcher_DispatchEdit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu        sync.Mutex
+		processed []string
+	)
+
+	record := func(kind string) func(telegramclient.WebhookMessageStruct, []interfaces.MessageEntityStruct) {
+		return func(messageIn telegramclient.WebhookMessageStruct, entities []interfaces.MessageEntityStruct) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			processed = append(processed, fmt.Sprintf("%s %d with %d entities", kind, messageIn.ID, len(entities)))
+		}
+	}
+
+	d := dispatcher.NewDispatcher(provideQueueConfig(10, 2), record("message"), record("edit"))
+
+	// Every version keeps its own entities, even if the edit arrives before the message is processed
+	mention := interfaces.MessageEntityStruct{Type: "mention", Offset: 0, Length: 5}
+
+	d.Dispatch(newTestMessage(789, 1), []interfaces.MessageEntityStruct{mention, mention})
+	d.DispatchEdit(newTestMessage(789, 1), nil)
+	d.Dispatch(newTestMessage(789, 2), []interfaces.MessageEntityStruct{mention})
+
+	require.NoError(t, d.Shutdown(t.Context()))
+	assert.Equal(t, []string{"message 1 with 2 entities", "edit 1 with 0 entities", "message 2 with 1 entities"}, processed)
+}
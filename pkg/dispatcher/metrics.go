@@ -0,0 +1,58 @@
+package dispatcher
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricsNamespace = "bot"
+	metricsSubsystem = "dispatcher"
+)
+
+var (
+	queueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "queue_length",
+		Help:      "Number of messages waiting in the queue.",
+	})
+	queueCapacity = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "queue_capacity",
+		Help:      "Total number of messages the queue can hold.",
+	})
+	queueFullTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "queue_full_total",
+		Help:      "Number of messages that had to wait for free queue space.",
+	})
+	droppedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "dropped_total",
+		Help:      "Number of messages dropped because the dispatcher was shutting down.",
+	})
+	processedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "processed_total",
+		Help:      "Number of messages processed by the workers.",
+	})
+	queueWaitSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "queue_wait_seconds",
+		Help:      "Time messages spent waiting in the queue.",
+		Buckets:   prometheus.DefBuckets,
+	})
+	processingSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "processing_seconds",
+		Help:      "Time spent processing a message.",
+		Buckets:   prometheus.DefBuckets,
+	})
+)
@@ -0,0 +1,172 @@
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	matcher "github.com/br0-space/bot-matcher"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"gorm.io/gorm"
+)
+
+const (
+	identifier    = "activity"
+	sparklineDays = 28
+	photoFilename = "activity.png"
+)
+
+var pattern = regexp.MustCompile(`(?i)^/(activity)(@\w+)?($| )(.*)$`)
+
+var help = []matcher.HelpStruct{{
+	Command:     `activity`,
+	Description: `Zeigt, zu welchen Wochentagen und Uhrzeiten im Chat (oder von einem User) geschrieben wird.`,
+	Usage:       `/activity <optional: png> <optional: @User>`,
+	Example:     `/activity png @alice`,
+}}
+
+const (
+	template          = "```\n%s\n```"
+	titleChat         = "Aktivität in diesem Chat"
+	titleUser         = "Aktivität von %s"
+	emptyChat         = "Ich habe hier noch keine Nachrichten gesehen."
+	emptyUserTemplate = "Von %s habe ich hier noch keine Nachrichten gesehen."
+	unknownTemplate   = "Einen User namens %s kenne ich hier nicht."
+	heatmapCaption    = "%s nach Wochentag (Zeilen, Mo bis So) und Uhrzeit (Spalten, 0 bis 23 Uhr)"
+	sparklineCaption  = "Nachrichten der letzten %d Tage"
+)
+
+type Matcher struct {
+	matcher.Matcher
+
+	messageStatsRepo interfaces.MessageStatsRepoInterface
+	userStatsRepo    interfaces.UserStatsRepoInterface
+	photoSender      interfaces.TelegramPhotoSenderInterface
+	loc              *time.Location
+}
+
+func MakeMatcher(
+	messageStatsRepo interfaces.MessageStatsRepoInterface,
+	userStatsRepo interfaces.UserStatsRepoInterface,
+	photoSender interfaces.TelegramPhotoSenderInterface,
+) Matcher {
+	return Matcher{
+		Matcher:          matcher.MakeMatcher(identifier, pattern, help),
+		messageStatsRepo: messageStatsRepo,
+		userStatsRepo:    userStatsRepo,
+		photoSender:      photoSender,
+		loc:              time.Local,
+	}
+}
+
+func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	match := m.CommandMatch(messageIn)
+	if match == nil {
+		return nil, errors.New("message does not match")
+	}
+
+	asPNG, username := parseArgs(match[3])
+
+	title := titleChat
+	empty := emptyChat
+	userID := int64(0)
+
+	if username != "" {
+		var err error
+
+		userID, err = m.userStatsRepo.GetUserIDByUsername(messageIn.Chat.ID, username)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		if userID == 0 {
+			return []telegramclient.MessageStruct{
+				telegramclient.Reply(fmt.Sprintf(unknownTemplate, username), messageIn.ID),
+			}, nil
+		}
+
+		title = fmt.Sprintf(titleUser, username)
+		empty = fmt.Sprintf(emptyUserTemplate, username)
+	}
+
+	cells, err := m.messageStatsRepo.GetActivityHeatmap(messageIn.Chat.ID, userID, time.Time{}, m.loc)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxCellMessages(cells) == 0 {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(empty, messageIn.ID),
+		}, nil
+	}
+
+	if asPNG {
+		return m.sendPNG(messageIn, cells, title)
+	}
+
+	now := time.Now().In(m.loc)
+	first := time.Date(now.Year(), now.Month(), now.Day()-sparklineDays+1, 0, 0, 0, 0, m.loc)
+
+	days, err := m.messageStatsRepo.GetDailyActivity(messageIn.Chat.ID, userID, first, m.loc)
+	if err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf(
+		template,
+		strings.Join([]string{
+			telegramclient.EscapeMarkdown(title),
+			renderHeatmap(cells),
+			fmt.Sprintf(sparklineCaption, sparklineDays) + "\n" + renderSparkline(days, first, sparklineDays),
+		}, "\n\n"),
+	)
+
+	return []telegramclient.MessageStruct{
+		telegramclient.MarkdownMessage(text),
+	}, nil
+}
+
+// sendPNG uploads the heatmap as an image, which the registry can't do on its own.
+func (m Matcher) sendPNG(
+	messageIn telegramclient.WebhookMessageStruct,
+	cells []interfaces.MessageStatsHeatmapCellStruct,
+	title string,
+) ([]telegramclient.MessageStruct, error) {
+	photo, err := renderHeatmapPNG(cells)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.photoSender.SendPhoto(
+		messageIn.Chat.ID,
+		messageIn.ID,
+		photoFilename,
+		photo,
+		fmt.Sprintf(heatmapCaption, title),
+	); err != nil {
+		return nil, err
+	}
+
+	return []telegramclient.MessageStruct{}, nil
+}
+
+// parseArgs splits "png @alice" into its parts, both are optional.
+func parseArgs(args string) (bool, string) {
+	asPNG := false
+	username := ""
+
+	for _, arg := range strings.Fields(args) {
+		if strings.EqualFold(arg, "png") {
+			asPNG = true
+
+			continue
+		}
+
+		username = strings.TrimPrefix(arg, "@")
+	}
+
+	return asPNG, username
+}
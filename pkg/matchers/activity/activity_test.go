@@ -0,0 +1,169 @@
+package activity_test
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/activity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeMessageStatsRepo struct {
+	interfaces.MessageStatsRepoInterface
+
+	userID int64
+	empty  bool
+}
+
+func (r *fakeMessageStatsRepo) GetActivityHeatmap(
+	_ int64,
+	userID int64,
+	_ time.Time,
+	_ *time.Location,
+) ([]interfaces.MessageStatsHeatmapCellStruct, error) {
+	r.userID = userID
+
+	cells := make([]interfaces.MessageStatsHeatmapCellStruct, 7*24)
+	for i := range cells {
+		cells[i].Weekday = time.Weekday(i / 24)
+		cells[i].Hour = i % 24
+	}
+
+	if !r.empty {
+		cells[int(time.Monday)*24+0].Messages = 1
+		cells[int(time.Monday)*24+1].Messages = 4
+		cells[int(time.Sunday)*24+23].Messages = 8
+	}
+
+	return cells, nil
+}
+
+func (r *fakeMessageStatsRepo) GetDailyActivity(
+	_ int64,
+	_ int64,
+	since time.Time,
+	_ *time.Location,
+) ([]interfaces.MessageStatsDayStruct, error) {
+	return []interfaces.MessageStatsDayStruct{
+		{Day: since.Format(time.DateOnly), Messages: 2, Words: 10},
+		{Day: since.AddDate(0, 0, 2).Format(time.DateOnly), Messages: 8, Words: 10},
+	}, nil
+}
+
+type fakeUserStatsRepo struct {
+	interfaces.UserStatsRepoInterface
+}
+
+func (r fakeUserStatsRepo) GetUserIDByUsername(_ int64, username string) (int64, error) {
+	switch username {
+	case "alice":
+		return 42, nil
+	case "broken":
+		return 0, errors.New("database is locked")
+	}
+
+	return 0, gorm.ErrRecordNotFound
+}
+
+type fakePhotoSender struct {
+	chatID  int64
+	replyTo int64
+	photo   []byte
+	caption string
+}
+
+func (s *fakePhotoSender) SendPhoto(chatID int64, replyToMessageID int64, _ string, photo []byte, caption string) error {
+	s.chatID = chatID
+	s.replyTo = replyToMessageID
+	s.photo = photo
+	s.caption = caption
+
+	return nil
+}
+
+func provideMatcher(repo *fakeMessageStatsRepo, photoSender *fakePhotoSender) activity.Matcher {
+	return activity.MakeMatcher(repo, fakeUserStatsRepo{}, photoSender)
+}
+
+func TestMatcher_ProcessText(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeMessageStatsRepo{}
+
+	replies, err := provideMatcher(repo, &fakePhotoSender{}).Process(telegramclient.TestWebhookMessage("/activity"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, int64(0), repo.userID)
+
+	text := replies[0].Text
+	assert.True(t, strings.HasPrefix(text, "```\nAktivität in diesem Chat\n\n   0     6     12    18\n"), text)
+	assert.Contains(t, text, "\nMo ░▒······················\n")
+	assert.Contains(t, text, "\nSo ·······················█\n")
+	assert.Contains(t, text, "\nNachrichten der letzten 28 Tage\n▂ █\nmax. 8 Nachrichten am Tag\n```")
+}
+
+func TestMatcher_ProcessUser(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeMessageStatsRepo{}
+
+	replies, err := provideMatcher(repo, &fakePhotoSender{}).Process(telegramclient.TestWebhookMessage("/activity @alice"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, int64(42), repo.userID)
+	assert.True(t, strings.HasPrefix(replies[0].Text, "```\nAktivität von alice\n"))
+
+	replies, err = provideMatcher(repo, &fakePhotoSender{}).Process(telegramclient.TestWebhookMessage("/activity bob"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Einen User namens bob kenne ich hier nicht.", replies[0].Text)
+
+	// Database errors aren't mistaken for unknown users
+	_, err = provideMatcher(repo, &fakePhotoSender{}).Process(telegramclient.TestWebhookMessage("/activity broken"))
+	require.Error(t, err)
+}
+
+func TestMatcher_ProcessPNG(t *testing.T) {
+	t.Parallel()
+
+	photoSender := &fakePhotoSender{}
+
+	replies, err := provideMatcher(&fakeMessageStatsRepo{}, photoSender).Process(telegramclient.TestWebhookMessage("/activity PNG alice"))
+	require.NoError(t, err)
+	assert.Empty(t, replies)
+	assert.Equal(t, int64(789), photoSender.chatID)
+	assert.Equal(t, int64(123), photoSender.replyTo)
+	assert.True(t, strings.HasPrefix(photoSender.caption, "Aktivität von alice nach Wochentag"))
+
+	img, err := png.Decode(bytes.NewReader(photoSender.photo))
+	require.NoError(t, err)
+	assert.Equal(t, 24*22+2, img.Bounds().Dx())
+	assert.Equal(t, 7*22+2, img.Bounds().Dy())
+}
+
+func TestMatcher_ProcessEmpty(t *testing.T) {
+	t.Parallel()
+
+	replies, err := provideMatcher(&fakeMessageStatsRepo{empty: true}, &fakePhotoSender{}).Process(telegramclient.TestWebhookMessage("/activity"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Ich habe hier noch keine Nachrichten gesehen.", replies[0].Text)
+}
+
+func TestMatcher_ProcessNoMatch(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{"", "activity", "/activityx", " /activity"} {
+		replies, err := provideMatcher(&fakeMessageStatsRepo{}, &fakePhotoSender{}).Process(telegramclient.TestWebhookMessage(in))
+		require.Error(t, err, in)
+		assert.Nil(t, replies, in)
+	}
+}
@@ -0,0 +1,162 @@
+package activity
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/br0-space/bot/interfaces"
+)
+
+const (
+	hoursPerDay = 24
+	cellSize    = 20
+	cellGap     = 2
+)
+
+// weekdays in German order, starting with Monday.
+var weekdays = []struct {
+	day   time.Weekday
+	label string
+}{
+	{time.Monday, "Mo"},
+	{time.Tuesday, "Di"},
+	{time.Wednesday, "Mi"},
+	{time.Thursday, "Do"},
+	{time.Friday, "Fr"},
+	{time.Saturday, "Sa"},
+	{time.Sunday, "So"},
+}
+
+var (
+	heatmapShades   = []rune("░▒▓█")
+	sparklineShades = []rune("▁▂▃▄▅▆▇█")
+	colorEmpty      = color.RGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}
+	colorFull       = color.RGBA{R: 0x1a, G: 0x7f, B: 0x37, A: 0xff}
+)
+
+// renderHeatmap draws one row per day of the week and one column per hour.
+func renderHeatmap(cells []interfaces.MessageStatsHeatmapCellStruct) string {
+	maxMessages := maxCellMessages(cells)
+
+	lines := make([]string, 0, len(weekdays)+1)
+	lines = append(lines, "   0     6     12    18")
+
+	for _, weekday := range weekdays {
+		row := make([]rune, 0, hoursPerDay)
+		for hour := range hoursPerDay {
+			messages := cells[int(weekday.day)*hoursPerDay+hour].Messages
+			if messages == 0 {
+				row = append(row, '·')
+
+				continue
+			}
+
+			row = append(row, shade(heatmapShades, messages, maxMessages))
+		}
+
+		lines = append(lines, weekday.label+" "+string(row))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderSparkline draws one character per day, from the given first day until today.
+func renderSparkline(days []interfaces.MessageStatsDayStruct, first time.Time, numDays int) string {
+	messages := make(map[string]int, len(days))
+	maxMessages := 0
+
+	for _, day := range days {
+		messages[day.Day] = day.Messages
+		maxMessages = max(maxMessages, day.Messages)
+	}
+
+	line := make([]rune, 0, numDays)
+	for i := range numDays {
+		count := messages[first.AddDate(0, 0, i).Format(time.DateOnly)]
+		if count == 0 {
+			line = append(line, ' ')
+
+			continue
+		}
+
+		line = append(line, shade(sparklineShades, count, maxMessages))
+	}
+
+	return fmt.Sprintf("%s\nmax. %d Nachrichten am Tag", strings.TrimRight(string(line), " "), maxMessages)
+}
+
+// renderHeatmapPNG draws the heatmap as an image with the same layout as renderHeatmap.
+func renderHeatmapPNG(cells []interfaces.MessageStatsHeatmapCellStruct) ([]byte, error) {
+	maxMessages := maxCellMessages(cells)
+	width := hoursPerDay*(cellSize+cellGap) + cellGap
+	height := len(weekdays)*(cellSize+cellGap) + cellGap
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, color.White)
+		}
+	}
+
+	for row, weekday := range weekdays {
+		for hour := range hoursPerDay {
+			fill := blend(cells[int(weekday.day)*hoursPerDay+hour].Messages, maxMessages)
+			left := cellGap + hour*(cellSize+cellGap)
+			top := cellGap + row*(cellSize+cellGap)
+
+			for y := top; y < top+cellSize; y++ {
+				for x := left; x < left+cellSize; x++ {
+					img.Set(x, y, fill)
+				}
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// shade picks a character for a value between 1 and maxValue.
+func shade(shades []rune, value int, maxValue int) rune {
+	level := (value*len(shades) - 1) / maxValue
+
+	return shades[min(max(level, 0), len(shades)-1)]
+}
+
+func blend(value int, maxValue int) color.RGBA {
+	if value <= 0 || maxValue <= 0 {
+		return colorEmpty
+	}
+
+	// Start at a quarter of the way so that single messages are visible
+	ratio := 0.25 + 0.75*float64(value)/float64(maxValue)
+	mix := func(from, to uint8) uint8 {
+		return uint8(float64(from) + (float64(to)-float64(from))*ratio)
+	}
+
+	return color.RGBA{
+		R: mix(colorEmpty.R, colorFull.R),
+		G: mix(colorEmpty.G, colorFull.G),
+		B: mix(colorEmpty.B, colorFull.B),
+		A: 0xff,
+	}
+}
+
+func maxCellMessages(cells []interfaces.MessageStatsHeatmapCellStruct) int {
+	maxValue := 0
+	for _, cell := range cells {
+		maxValue = max(maxValue, cell.Messages)
+	}
+
+	return maxValue
+}
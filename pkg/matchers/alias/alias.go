@@ -0,0 +1,163 @@
+package alias
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	matcher "github.com/br0-space/bot-matcher"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const identifier = "alias"
+
+var pattern = regexp.MustCompile(`(?i)^/(alias|merge)(@\w+)?($| )(\S+)?\s*(\S+)?`)
+
+var help = []matcher.HelpStruct{{
+	Command:     `alias`,
+	Description: `Lässt einen Begriff für einen anderen zählen. Nur für Admins. Ohne Begriffe werden alle Aliase angezeigt.`,
+	Usage:       `/alias <optional: Alias> <optional: Begriff>`,
+	Example:     `/alias coffee kaffee`,
+}, {
+	Command:     `merge`,
+	Description: `Legt zwei Begriffe samt ihrer Punkte zusammen. Nur für Admins.`,
+	Usage:       `/merge <Begriff> <Zielbegriff>`,
+	Example:     `/merge coffee kaffee`,
+}}
+
+const (
+	listTemplate       = "```\n%s\n```"
+	emptyTemplate      = "In diesem Chat gibt es noch keine Aliase."
+	missingTemplate    = "Bitte gib zwei Begriffe an, z.B. /%s coffee kaffee"
+	selfTemplate       = "%s kann nicht für sich selbst zählen."
+	aliasTemplate      = "%s zählt ab jetzt für %s."
+	mergeTemplate      = "%s wurde mit %s zusammengelegt, %s ist jetzt auf %d."
+	aliasAdminTemplate = "Aliase setzen dürfen nur Admins."
+	mergeAdminTemplate = "Begriffe zusammenlegen dürfen nur Admins."
+)
+
+type Matcher struct {
+	matcher.Matcher
+
+	repo         interfaces.PlusplusRepoInterface
+	adminChecker interfaces.ChatAdminCheckerInterface
+}
+
+func MakeMatcher(
+	repo interfaces.PlusplusRepoInterface,
+	adminChecker interfaces.ChatAdminCheckerInterface,
+) Matcher {
+	return Matcher{
+		Matcher:      matcher.MakeMatcher(identifier, pattern, help),
+		repo:         repo,
+		adminChecker: adminChecker,
+	}
+}
+
+func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	match := m.CommandMatch(messageIn)
+	if match == nil {
+		return nil, errors.New("message does not match")
+	}
+
+	cmd := strings.ToLower(match[0])
+	from := strings.ToLower(match[3])
+	into := strings.ToLower(match[4])
+
+	if cmd == "alias" && from == "" {
+		return m.makeListReplies(messageIn.Chat.ID)
+	}
+
+	if from == "" || into == "" {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(fmt.Sprintf(missingTemplate, cmd), messageIn.ID),
+		}, nil
+	}
+
+	var text string
+
+	var err error
+
+	switch cmd {
+	case "alias":
+		text, err = m.setAlias(messageIn, from, into)
+	case "merge":
+		text, err = m.merge(messageIn, from, into)
+	}
+
+	if errors.Is(err, interfaces.ErrPlusplusSelfAlias) {
+		text, err = fmt.Sprintf(selfTemplate, from), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []telegramclient.MessageStruct{
+		telegramclient.Reply(text, messageIn.ID),
+	}, nil
+}
+
+func (m Matcher) makeListReplies(chatID int64) ([]telegramclient.MessageStruct, error) {
+	aliases, err := m.repo.FindAliases(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(aliases) == 0 {
+		return []telegramclient.MessageStruct{
+			telegramclient.Message(emptyTemplate),
+		}, nil
+	}
+
+	lines := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		lines = append(lines, fmt.Sprintf(
+			"%s → %s",
+			telegramclient.EscapeMarkdown(alias.Alias),
+			telegramclient.EscapeMarkdown(alias.Name),
+		))
+	}
+
+	return []telegramclient.MessageStruct{
+		telegramclient.MarkdownMessage(fmt.Sprintf(listTemplate, strings.Join(lines, "\n"))),
+	}, nil
+}
+
+func (m Matcher) setAlias(messageIn telegramclient.WebhookMessageStruct, alias string, name string) (string, error) {
+	isAdmin, err := m.adminChecker.IsAdmin(messageIn)
+	if err != nil {
+		return "", err
+	}
+
+	if !isAdmin {
+		return aliasAdminTemplate, nil
+	}
+
+	canonical, err := m.repo.SetAlias(messageIn.Chat.ID, alias, name)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(aliasTemplate, alias, canonical), nil
+}
+
+func (m Matcher) merge(messageIn telegramclient.WebhookMessageStruct, from string, into string) (string, error) {
+	isAdmin, err := m.adminChecker.IsAdmin(messageIn)
+	if err != nil {
+		return "", err
+	}
+
+	if !isAdmin {
+		return mergeAdminTemplate, nil
+	}
+
+	canonical, value, err := m.repo.Merge(messageIn.Chat.ID, from, into)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(mergeTemplate, from, canonical, canonical, value), nil
+}
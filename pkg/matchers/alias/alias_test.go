@@ -0,0 +1,77 @@
+package alias_test
+
+import (
+	"testing"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/alias"
+	"github.com/br0-space/bot/pkg/repo/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAdminChecker struct {
+	admins map[int64]bool
+}
+
+func (c fakeAdminChecker) IsAdmin(messageIn telegramclient.WebhookMessageStruct) (bool, error) {
+	return c.admins[messageIn.From.ID], nil
+}
+
+func newTestMessage(text string) telegramclient.WebhookMessageStruct {
+	message := telegramclient.TestWebhookMessage(text)
+	message.Chat.Type = "supergroup"
+
+	return message
+}
+
+func process(t *testing.T, repo *mocks.PlusplusRepoInterface, isAdmin bool, text string) string {
+	t.Helper()
+
+	adminChecker := fakeAdminChecker{admins: map[int64]bool{456: isAdmin}}
+
+	replies, err := alias.MakeMatcher(repo, adminChecker).Process(newTestMessage(text))
+	require.NoError(t, err, text)
+	require.Len(t, replies, 1, text)
+
+	return replies[0].Text
+}
+
+func TestMatcher_ProcessAlias(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().SetAlias(int64(789), "coffee", "kaffee").Return("kaffee", nil)
+	repo.EXPECT().SetAlias(int64(789), "kaffee", "kaffee").Return("", interfaces.ErrPlusplusSelfAlias)
+
+	assert.Equal(t, "Aliase setzen dürfen nur Admins.", process(t, repo, false, "/alias Coffee kaffee"))
+	assert.Equal(t, "coffee zählt ab jetzt für kaffee.", process(t, repo, true, "/alias Coffee kaffee"))
+	assert.Equal(t, "kaffee kann nicht für sich selbst zählen.", process(t, repo, true, "/alias@bot kaffee kaffee"))
+	assert.Equal(t, "Bitte gib zwei Begriffe an, z.B. /alias coffee kaffee", process(t, repo, true, "/alias coffee"))
+}
+
+func TestMatcher_ProcessList(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().FindAliases(int64(789)).Return([]interfaces.PlusplusAlias{
+		{ChatID: 789, Alias: "coffee", Name: "kaffee"},
+		{ChatID: 789, Alias: "☕", Name: "kaffee"},
+	}, nil).Once()
+	repo.EXPECT().FindAliases(int64(789)).Return(nil, nil).Once()
+
+	assert.Equal(t, "```\ncoffee → kaffee\n☕ → kaffee\n```", process(t, repo, false, "/alias"))
+	assert.Equal(t, "In diesem Chat gibt es noch keine Aliase.", process(t, repo, false, "/alias"))
+}
+
+func TestMatcher_ProcessMerge(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().Merge(int64(789), "coffee", "kaffee").Return("kaffee", 42, nil)
+
+	assert.Equal(t, "Begriffe zusammenlegen dürfen nur Admins.", process(t, repo, false, "/merge coffee kaffee"))
+	assert.Equal(t, "coffee wurde mit kaffee zusammengelegt, kaffee ist jetzt auf 42.", process(t, repo, true, "/merge coffee kaffee"))
+	assert.Equal(t, "Bitte gib zwei Begriffe an, z.B. /merge coffee kaffee", process(t, repo, true, "/merge"))
+}
@@ -37,7 +37,7 @@ func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]teleg
 		return nil, nil
 	}
 
-	users, err := m.repo.GetKnownUsers()
+	users, err := m.repo.GetKnownUsers(messageIn.Chat.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +44,7 @@ func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]teleg
 	matches := m.InlineMatches(messageIn)
 	triggers := m.parseTriggers(matches)
 
-	return m.makeRepliesFromTriggers(triggers)
+	return m.makeRepliesFromTriggers(messageIn, triggers)
 }
 
 func (m Matcher) parseTriggers(matches []string) []string {
@@ -61,11 +61,14 @@ func (m Matcher) parseTriggers(matches []string) []string {
 	return triggers
 }
 
-func (m Matcher) makeRepliesFromTriggers(triggers []string) ([]telegramclient.MessageStruct, error) {
+func (m Matcher) makeRepliesFromTriggers(
+	messageIn telegramclient.WebhookMessageStruct,
+	triggers []string,
+) ([]telegramclient.MessageStruct, error) {
 	var replies []telegramclient.MessageStruct
 
 	for _, match := range triggers {
-		triggerReplies, err := m.makeRepliesFromTrigger(match)
+		triggerReplies, err := m.makeRepliesFromTrigger(messageIn, match)
 		if err != nil {
 			return nil, err
 		}
@@ -76,8 +79,11 @@ func (m Matcher) makeRepliesFromTriggers(triggers []string) ([]telegramclient.Me
 	return replies, nil
 }
 
-func (m Matcher) makeRepliesFromTrigger(trigger string) ([]telegramclient.MessageStruct, error) {
-	value, err := m.repo.Increment(trigger, 1)
+func (m Matcher) makeRepliesFromTrigger(
+	messageIn telegramclient.WebhookMessageStruct,
+	trigger string,
+) ([]telegramclient.MessageStruct, error) {
+	value, err := m.repo.Increment(messageIn.Chat.ID, messageIn.From.ID, messageIn.ID, trigger, 1)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +51,7 @@ func (m Matcher) doesMatch(messageIn telegramclient.WebhookMessageStruct) bool {
 		return false
 	}
 
-	lastPost := m.state.GetLastPost(messageIn.From.ID)
+	lastPost := m.state.GetLastPost(messageIn.Chat.ID, messageIn.From.ID)
 
 	if lastPost == nil || now.Sub(*lastPost) > time.Hour*6 {
 		return true
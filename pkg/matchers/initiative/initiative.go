@@ -0,0 +1,260 @@
+package initiative
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	matcher "github.com/br0-space/bot-matcher"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/pkg/matchers/roll"
+)
+
+const identifier = "initiative"
+
+const (
+	defaultDice   = "1d20"
+	maxNameLength = 32
+)
+
+var pattern = regexp.MustCompile(`(?i)^/(init)(@\w+)?($| )(.*)$`)
+
+var help = []matcher.HelpStruct{
+	{
+		Command:     `init`,
+		Description: `Shows the initiative order of the running encounter.`,
+		Usage:       `/init`,
+		Example:     `/init`,
+	},
+	{
+		Command:     `init start`,
+		Description: `Starts an encounter.`,
+		Usage:       `/init start`,
+		Example:     `/init start`,
+	},
+	{
+		Command:     `init add`,
+		Description: `Adds a character or monster with a dice roll or a fixed initiative.`,
+		Usage:       `/init add <name> <dice|initiative>`,
+		Example:     `/init add Goblin 1d20+2`,
+	},
+	{
+		Command:     `init roll`,
+		Description: `Rolls initiative for yourself, 1d20 if no dice are given.`,
+		Usage:       `/init roll [dice]`,
+		Example:     `/init roll 1d20+3`,
+	},
+	{
+		Command:     `init next`,
+		Description: `Passes the turn to the next participant.`,
+		Usage:       `/init next`,
+		Example:     `/init next`,
+	},
+	{
+		Command:     `init end`,
+		Description: `Ends the encounter.`,
+		Usage:       `/init end`,
+		Example:     `/init end`,
+	},
+}
+
+var templates = struct {
+	usage          string
+	addUsage       string
+	started        string
+	running        string
+	noEncounter    string
+	noParticipants string
+	tooMany        string
+	invalidName    string
+	rolled         string
+	fixed          string
+	turn           string
+	ended          string
+	endedAfter     string
+}{
+	usage:          `❌ Unknown command\. Use /init start, add, roll, next or end\.`,
+	addUsage:       `❌ Usage: /init add Goblin 1d20\+2`,
+	started:        `⚔️ Encounter started\! Add participants with /init add or roll with /init roll\.`,
+	running:        `❌ An encounter is already running\. End it with /init end\.`,
+	noEncounter:    `❌ No encounter running\. Start one with /init start\.`,
+	noParticipants: `❌ Nobody has rolled initiative yet\.`,
+	tooMany:        `❌ The encounter can't have more than %d participants\.`,
+	invalidName:    `❌ Names can have up to %d characters\.`,
+	rolled:         `🎲 *%s* rolls %s: *%s*`,
+	fixed:          `🎲 *%s* has initiative *%s*`,
+	turn:           `▶️ *Round %d*: it's *%s*'s turn\!`,
+	ended:          `🏁 Encounter ended\.`,
+	endedAfter:     `🏁 Encounter ended after %d rounds\.`,
+}
+
+type Matcher struct {
+	matcher.Matcher
+
+	tracker *Tracker
+}
+
+func MakeMatcher() Matcher {
+	return Matcher{
+		Matcher: matcher.MakeMatcher(identifier, pattern, help),
+		tracker: NewTracker(),
+	}
+}
+
+func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	match := m.CommandMatch(messageIn)
+	if match == nil {
+		return nil, errors.New("message does not match")
+	}
+
+	args := strings.Fields(match[3])
+	chatID := messageIn.Chat.ID
+
+	if len(args) == 0 {
+		encounter, err := m.tracker.Get(chatID)
+
+		return m.makeReply(formatEncounter(encounter), err, messageIn.ID)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "start":
+		return m.makeReply(templates.started, m.tracker.Start(chatID), messageIn.ID)
+	case "add":
+		if len(args) < 3 {
+			return m.makeReply(templates.addUsage, nil, messageIn.ID)
+		}
+
+		return m.processAdd(messageIn, strings.Join(args[1:len(args)-1], " "), args[len(args)-1])
+	case "roll":
+		dice := defaultDice
+		if len(args) > 1 {
+			dice = args[1]
+		}
+
+		return m.processAdd(messageIn, messageIn.From.UsernameOrName(), dice)
+	case "next":
+		encounter, err := m.tracker.Next(chatID)
+		if err != nil {
+			return m.makeReply("", err, messageIn.ID)
+		}
+
+		current, _ := encounter.Current()
+
+		return m.makeReply(
+			fmt.Sprintf(templates.turn, encounter.Round, telegramclient.EscapeMarkdown(current.Name))+
+				"\n\n"+formatEncounter(encounter),
+			nil,
+			messageIn.ID,
+		)
+	case "end":
+		encounter, err := m.tracker.End(chatID)
+		if err != nil || encounter.Round == 0 {
+			return m.makeReply(templates.ended, err, messageIn.ID)
+		}
+
+		return m.makeReply(fmt.Sprintf(templates.endedAfter, encounter.Round), nil, messageIn.ID)
+	default:
+		return m.makeReply(templates.usage, nil, messageIn.ID)
+	}
+}
+
+// processAdd rolls the initiative of a participant and adds it to the encounter.
+// The initiative can also be given as a fixed number.
+func (m Matcher) processAdd(
+	messageIn telegramclient.WebhookMessageStruct,
+	name string,
+	dice string,
+) ([]telegramclient.MessageStruct, error) {
+	if len([]rune(name)) > maxNameLength {
+		return m.makeReply(fmt.Sprintf(templates.invalidName, maxNameLength), nil, messageIn.ID)
+	}
+
+	var (
+		initiative int
+		text       string
+	)
+
+	if fixed, err := strconv.Atoi(dice); err == nil {
+		initiative = fixed
+		text = fmt.Sprintf(
+			templates.fixed,
+			telegramclient.EscapeMarkdown(name),
+			telegramclient.EscapeMarkdown(strconv.Itoa(initiative)),
+		)
+	} else {
+		expression, err := roll.ParseExpression(dice)
+		if err != nil {
+			return m.makeReply("❌ "+telegramclient.EscapeMarkdown(err.Error()), nil, messageIn.ID)
+		}
+
+		diceRoll := roll.NewExpressionRoll(expression, nil, false)
+		diceRoll.Roll()
+
+		initiative = diceRoll.Sum()
+		text = fmt.Sprintf(
+			templates.rolled,
+			telegramclient.EscapeMarkdown(name),
+			telegramclient.EscapeMarkdown(expression.String()),
+			telegramclient.EscapeMarkdown(strconv.Itoa(initiative)),
+		)
+	}
+
+	encounter, err := m.tracker.Add(messageIn.Chat.ID, Participant{Name: name, Initiative: initiative})
+	if err != nil {
+		return m.makeReply("", err, messageIn.ID)
+	}
+
+	return m.makeReply(text+"\n\n"+formatEncounter(encounter), nil, messageIn.ID)
+}
+
+// formatEncounter lists the participants in initiative order and marks whose turn it is.
+func formatEncounter(encounter Encounter) string {
+	header := "⚔️ *Initiative*"
+	if encounter.Round > 0 {
+		header += fmt.Sprintf(" \\(Round %d\\)", encounter.Round)
+	}
+
+	if len(encounter.Participants) == 0 {
+		return header + "\nNobody has rolled initiative yet\\."
+	}
+
+	lines := []string{header}
+
+	for i, participant := range encounter.Participants {
+		marker := "▫️"
+		if i == encounter.Turn {
+			marker = "▶️"
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"%s %s: %s",
+			marker,
+			telegramclient.EscapeMarkdown(participant.Name),
+			telegramclient.EscapeMarkdown(strconv.Itoa(participant.Initiative)),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// makeReply creates a reply with the text, or with the explanation of a tracker error.
+func (m Matcher) makeReply(text string, err error, messageID int64) ([]telegramclient.MessageStruct, error) {
+	switch {
+	case errors.Is(err, errEncounterRunning):
+		text = templates.running
+	case errors.Is(err, errNoEncounter):
+		text = templates.noEncounter
+	case errors.Is(err, errNoParticipants):
+		text = templates.noParticipants
+	case errors.Is(err, errTooManyParticipants):
+		text = fmt.Sprintf(templates.tooMany, maxParticipants)
+	case err != nil:
+		return nil, err
+	}
+
+	return []telegramclient.MessageStruct{
+		telegramclient.MarkdownReply(text, messageID),
+	}, nil
+}
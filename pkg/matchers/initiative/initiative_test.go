@@ -0,0 +1,67 @@
+package initiative_test
+
+import (
+	"testing"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/pkg/matchers/initiative"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func process(t *testing.T, matcher initiative.Matcher, text string) string {
+	t.Helper()
+
+	replies, err := matcher.Process(telegramclient.TestWebhookMessage(text))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+
+	return replies[0].Text
+}
+
+var doesMatchTests = []struct {
+	in            string
+	expectedMatch bool
+}{
+	{"", false},
+	{"init", false},
+	{"/initiative", false},
+	{"/init", true},
+	{"/init@bot start", true},
+	{"/INIT add Goblin 1d20+2", true},
+}
+
+func TestMatcher_DoesMatch(t *testing.T) {
+	t.Parallel()
+
+	matcher := initiative.MakeMatcher()
+	for _, tt := range doesMatchTests {
+		assert.Equal(t, tt.expectedMatch, matcher.DoesMatch(telegramclient.TestWebhookMessage(tt.in)), tt.in)
+	}
+}
+
+func TestMatcher_ProcessEncounter(t *testing.T) {
+	t.Parallel()
+
+	matcher := initiative.MakeMatcher()
+
+	assert.Contains(t, process(t, matcher, "/init add Goblin 1d20+2"), "No encounter running")
+	assert.Contains(t, process(t, matcher, "/init start"), "Encounter started")
+	assert.Contains(t, process(t, matcher, "/init start"), "already running")
+	assert.Contains(t, process(t, matcher, "/init next"), "Nobody has rolled initiative yet")
+
+	assert.Regexp(t, `^🎲 \*Goblin Boss\* rolls 1d20\\\+2: \*\d+\*\n\n⚔️ \*Initiative\*\n▫️ Goblin Boss: \d+$`,
+		process(t, matcher, "/init add Goblin Boss 1d20+2"))
+	assert.Regexp(t, `^🎲 \*Dragon\* has initiative \*30\*\n\n⚔️ \*Initiative\*\n▫️ Dragon: 30\n▫️ Goblin Boss: \d+$`,
+		process(t, matcher, "/init add Dragon 30"))
+	assert.Regexp(t, `^🎲 \*@Foobar\* rolls 1d20: \*\d+\*`, process(t, matcher, "/init roll"))
+
+	assert.Regexp(t, `^▶️ \*Round 1\*: it's \*Dragon\*'s turn\\!\n\n⚔️ \*Initiative\* \\\(Round 1\\\)\n▶️ Dragon: 30\n`,
+		process(t, matcher, "/init next"))
+	assert.Contains(t, process(t, matcher, "/init"), "▶️ Dragon: 30")
+	assert.Contains(t, process(t, matcher, "/init add Goblin"), "Usage")
+	assert.Contains(t, process(t, matcher, "/init add Goblin 2d"), "dice sides are missing")
+	assert.Contains(t, process(t, matcher, "/init flee"), "Unknown command")
+	assert.Equal(t, `🏁 Encounter ended after 1 rounds\.`, process(t, matcher, "/init end"))
+	assert.Contains(t, process(t, matcher, "/init"), "No encounter running")
+}
@@ -0,0 +1,191 @@
+package initiative
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+const maxParticipants = 50
+
+var (
+	errEncounterRunning    = errors.New("encounter already running")
+	errNoEncounter         = errors.New("no encounter running")
+	errNoParticipants      = errors.New("encounter has no participants")
+	errTooManyParticipants = errors.New("encounter has too many participants")
+)
+
+// Participant is a character or monster in the initiative order.
+type Participant struct {
+	Name       string
+	Initiative int
+}
+
+// Encounter is the initiative order of a chat.
+type Encounter struct {
+	Participants []Participant
+	// Turn is the index of the participant whose turn it is, -1 before the first turn.
+	Turn  int
+	Round int
+}
+
+// Current returns the participant whose turn it is.
+func (e Encounter) Current() (Participant, bool) {
+	if e.Turn < 0 || e.Turn >= len(e.Participants) {
+		return Participant{}, false
+	}
+
+	return e.Participants[e.Turn], true
+}
+
+// Tracker keeps the encounters of all chats in memory. All methods return
+// copies, so the encounters can be formatted without holding the lock.
+type Tracker struct {
+	mutex      sync.Mutex
+	encounters map[int64]*Encounter
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		mutex:      sync.Mutex{},
+		encounters: make(map[int64]*Encounter),
+	}
+}
+
+// Start starts a new encounter in a chat.
+func (t *Tracker) Start(chatID int64) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, exists := t.encounters[chatID]; exists {
+		return errEncounterRunning
+	}
+
+	t.encounters[chatID] = &Encounter{Participants: nil, Turn: -1, Round: 0}
+
+	return nil
+}
+
+// End ends the encounter of a chat and returns its final state.
+func (t *Tracker) End(chatID int64) (Encounter, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	encounter, exists := t.encounters[chatID]
+	if !exists {
+		return Encounter{}, errNoEncounter
+	}
+
+	delete(t.encounters, chatID)
+
+	return encounter.copy(), nil
+}
+
+// Get returns the encounter of a chat.
+func (t *Tracker) Get(chatID int64) (Encounter, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	encounter, exists := t.encounters[chatID]
+	if !exists {
+		return Encounter{}, errNoEncounter
+	}
+
+	return encounter.copy(), nil
+}
+
+// Add adds a participant to the encounter of a chat, replacing a participant
+// with the same name. Participants are ordered by initiative, on ties the one
+// added first goes first. The turn stays with the current participant.
+func (t *Tracker) Add(chatID int64, participant Participant) (Encounter, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	encounter, exists := t.encounters[chatID]
+	if !exists {
+		return Encounter{}, errNoEncounter
+	}
+
+	current := false
+
+	for i, existing := range encounter.Participants {
+		if strings.EqualFold(existing.Name, participant.Name) {
+			current = i == encounter.Turn
+			encounter.remove(i)
+
+			break
+		}
+	}
+
+	if len(encounter.Participants) >= maxParticipants {
+		return Encounter{}, errTooManyParticipants
+	}
+
+	index := len(encounter.Participants)
+
+	for i, existing := range encounter.Participants {
+		if participant.Initiative > existing.Initiative {
+			index = i
+
+			break
+		}
+	}
+
+	encounter.Participants = append(encounter.Participants, Participant{})
+	copy(encounter.Participants[index+1:], encounter.Participants[index:])
+	encounter.Participants[index] = participant
+
+	// A replaced participant keeps their turn at the new position
+	if current {
+		encounter.Turn = index
+	} else if encounter.Turn >= index {
+		encounter.Turn++
+	}
+
+	return encounter.copy(), nil
+}
+
+// Next passes the turn to the next participant and starts a new round after the last one.
+func (t *Tracker) Next(chatID int64) (Encounter, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	encounter, exists := t.encounters[chatID]
+	if !exists {
+		return Encounter{}, errNoEncounter
+	}
+
+	if len(encounter.Participants) == 0 {
+		return Encounter{}, errNoParticipants
+	}
+
+	encounter.Turn++
+	if encounter.Turn >= len(encounter.Participants) {
+		encounter.Turn = 0
+		encounter.Round++
+	}
+
+	if encounter.Round == 0 {
+		encounter.Round = 1
+	}
+
+	return encounter.copy(), nil
+}
+
+// remove removes a participant. If it was their turn, the next participant
+// moves up and gets the turn with the next call of Next.
+func (e *Encounter) remove(index int) {
+	e.Participants = append(e.Participants[:index], e.Participants[index+1:]...)
+
+	if e.Turn >= index {
+		e.Turn--
+	}
+}
+
+func (e *Encounter) copy() Encounter {
+	return Encounter{
+		Participants: append([]Participant(nil), e.Participants...),
+		Turn:         e.Turn,
+		Round:        e.Round,
+	}
+}
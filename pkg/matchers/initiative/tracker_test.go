@@ -0,0 +1,139 @@
+package initiative_test
+
+import (
+	"testing"
+
+	"github.com/br0-space/bot/pkg/matchers/initiative"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func names(encounter initiative.Encounter) []string {
+	result := make([]string, 0, len(encounter.Participants))
+	for _, participant := range encounter.Participants {
+		result = append(result, participant.Name)
+	}
+
+	return result
+}
+
+func TestTracker_Order(t *testing.T) {
+	t.Parallel()
+
+	tracker := initiative.NewTracker()
+	require.NoError(t, tracker.Start(1))
+	require.Error(t, tracker.Start(1))
+
+	_, err := tracker.Add(1, initiative.Participant{Name: "Goblin", Initiative: 12})
+	require.NoError(t, err)
+
+	_, err = tracker.Add(1, initiative.Participant{Name: "Alice", Initiative: 17})
+	require.NoError(t, err)
+
+	// Ties keep the order in which participants were added
+	encounter, err := tracker.Add(1, initiative.Participant{Name: "Orc", Initiative: 12})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Goblin", "Orc"}, names(encounter))
+	assert.Equal(t, -1, encounter.Turn)
+
+	// Adding a participant again replaces them
+	encounter, err = tracker.Add(1, initiative.Participant{Name: "goblin", Initiative: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Orc", "goblin"}, names(encounter))
+
+	// Other chats have their own encounter
+	_, err = tracker.Get(2)
+	require.Error(t, err)
+}
+
+func TestTracker_Turns(t *testing.T) {
+	t.Parallel()
+
+	tracker := initiative.NewTracker()
+	require.NoError(t, tracker.Start(1))
+
+	_, err := tracker.Next(1)
+	require.Error(t, err)
+
+	_, err = tracker.Add(1, initiative.Participant{Name: "Alice", Initiative: 17})
+	require.NoError(t, err)
+
+	_, err = tracker.Add(1, initiative.Participant{Name: "Goblin", Initiative: 12})
+	require.NoError(t, err)
+
+	encounter, err := tracker.Next(1)
+	require.NoError(t, err)
+
+	current, ok := encounter.Current()
+	require.True(t, ok)
+	assert.Equal(t, "Alice", current.Name)
+	assert.Equal(t, 1, encounter.Round)
+
+	// A faster participant joining keeps the turn with the current one
+	encounter, err = tracker.Add(1, initiative.Participant{Name: "Bob", Initiative: 20})
+	require.NoError(t, err)
+
+	current, _ = encounter.Current()
+	assert.Equal(t, "Alice", current.Name)
+
+	encounter, err = tracker.Next(1)
+	require.NoError(t, err)
+
+	current, _ = encounter.Current()
+	assert.Equal(t, "Goblin", current.Name)
+
+	// After the last participant the next round starts
+	encounter, err = tracker.Next(1)
+	require.NoError(t, err)
+
+	current, _ = encounter.Current()
+	assert.Equal(t, "Bob", current.Name)
+	assert.Equal(t, 2, encounter.Round)
+
+	encounter, err = tracker.End(1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, encounter.Round)
+
+	_, err = tracker.End(1)
+	require.Error(t, err)
+}
+
+func TestTracker_ReplaceKeepsTurn(t *testing.T) {
+	t.Parallel()
+
+	tracker := initiative.NewTracker()
+	require.NoError(t, tracker.Start(1))
+
+	for _, participant := range []initiative.Participant{
+		{Name: "Alice", Initiative: 17},
+		{Name: "Goblin", Initiative: 12},
+		{Name: "Orc", Initiative: 8},
+	} {
+		_, err := tracker.Add(1, participant)
+		require.NoError(t, err)
+	}
+
+	_, err := tracker.Next(1)
+	require.NoError(t, err)
+
+	_, err = tracker.Next(1)
+	require.NoError(t, err)
+
+	// The current participant keeps the turn with the same or a new initiative
+	for _, initiativeValue := range []int{12, 20, 3} {
+		encounter, err := tracker.Add(1, initiative.Participant{Name: "goblin", Initiative: initiativeValue})
+		require.NoError(t, err)
+
+		current, ok := encounter.Current()
+		require.True(t, ok)
+		assert.Equal(t, "goblin", current.Name, initiativeValue)
+	}
+
+	// Replacing someone else doesn't move the turn
+	encounter, err := tracker.Add(1, initiative.Participant{Name: "Alice", Initiative: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Orc", "goblin", "Alice"}, names(encounter))
+
+	current, _ := encounter.Current()
+	assert.Equal(t, "goblin", current.Name)
+}
@@ -0,0 +1,284 @@
+package karma
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	matcher "github.com/br0-space/bot-matcher"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"gorm.io/gorm"
+)
+
+const (
+	identifier   = "karma"
+	givers       = 5
+	changes      = 5
+	trendWeeks   = 8
+	maxBarLength = 10
+)
+
+var pattern = regexp.MustCompile(`(?i)^/(karma)(@\w+)?($| )(.*)`)
+
+var help = []matcher.HelpStruct{{
+	Command:     `karma`,
+	Description: `Zeigt an, wer einen Begriff wie oft geplust oder geminust hat, die letzten Änderungen und den Verlauf der letzten Wochen.`,
+	Usage:       `/karma <Begriff>`,
+	Example:     `/karma kaffee`,
+}, {
+	Command:     `karma set`,
+	Description: `Setzt das Karma eines Begriffs auf einen Wert. Nur für Admins.`,
+	Usage:       `/karma set <Begriff> <Wert>`,
+	Example:     `/karma set kaffee 10`,
+}, {
+	Command:     `karma delete`,
+	Description: `Löscht einen Begriff samt seiner Aliase und Änderungen. Nur für Admins.`,
+	Usage:       `/karma delete <Begriff>`,
+	Example:     `/karma delete kaffee`,
+}}
+
+const (
+	template         = "```\n%s\n```"
+	usageTemplate    = "Bitte gib einen Begriff an, z.B. /karma kaffee"
+	unknownTemplate  = "%s hat noch kein Karma."
+	setTemplate      = "%s ist jetzt auf %d (vorher %d)."
+	setUsageTemplate = "Bitte gib einen Begriff und einen Wert an, z.B. /karma set kaffee 10"
+	deleteTemplate   = "%s wurde gelöscht, vorher war es auf %d."
+	notAdminTemplate = "Karma ändern dürfen nur Admins."
+)
+
+type Matcher struct {
+	matcher.Matcher
+
+	repo         interfaces.PlusplusRepoInterface
+	adminChecker interfaces.ChatAdminCheckerInterface
+	loc          *time.Location
+}
+
+func MakeMatcher(
+	repo interfaces.PlusplusRepoInterface,
+	adminChecker interfaces.ChatAdminCheckerInterface,
+) Matcher {
+	return Matcher{
+		Matcher:      matcher.MakeMatcher(identifier, pattern, help),
+		repo:         repo,
+		adminChecker: adminChecker,
+		loc:          time.Local,
+	}
+}
+
+func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	match := m.CommandMatch(messageIn)
+	if match == nil {
+		return nil, errors.New("message does not match")
+	}
+
+	args := strings.Fields(strings.ToLower(match[3]))
+
+	switch {
+	case len(args) == 0:
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(usageTemplate, messageIn.ID),
+		}, nil
+	case args[0] == "set" && len(args) > 1:
+		return m.makeCorrectionReplies(messageIn, args)
+	case args[0] == "delete" && len(args) == 2:
+		return m.makeCorrectionReplies(messageIn, args)
+	}
+
+	return m.makeKarmaReplies(messageIn, strings.Join(args, " "))
+}
+
+func (m Matcher) makeKarmaReplies(
+	messageIn telegramclient.WebhookMessageStruct,
+	name string,
+) ([]telegramclient.MessageStruct, error) {
+	record, err := m.repo.FindByName(messageIn.Chat.ID, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(fmt.Sprintf(unknownTemplate, name), messageIn.ID),
+		}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	topGivers, err := m.repo.FindTopGivers(messageIn.Chat.ID, name, givers)
+	if err != nil {
+		return nil, err
+	}
+
+	recentChanges, err := m.repo.FindRecentChanges(messageIn.Chat.ID, name, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	trend, err := m.repo.GetWeeklyTrend(messageIn.Chat.ID, name, trendWeeks, m.loc)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := []string{
+		fmt.Sprintf("Karma für %s: %d", telegramclient.EscapeMarkdown(record.Name), record.Value),
+	}
+
+	if len(recentChanges) == 0 {
+		sections = append(sections, "Noch keine Änderungen protokolliert.")
+	} else {
+		sections = append(sections,
+			formatGivers(topGivers),
+			formatChanges(recentChanges, m.loc),
+			formatTrend(trend),
+		)
+	}
+
+	return []telegramclient.MessageStruct{
+		telegramclient.MarkdownMessage(fmt.Sprintf(template, strings.Join(sections, "\n\n"))),
+	}, nil
+}
+
+// makeCorrectionReplies sets or deletes a term for admins.
+func (m Matcher) makeCorrectionReplies(
+	messageIn telegramclient.WebhookMessageStruct,
+	args []string,
+) ([]telegramclient.MessageStruct, error) {
+	isAdmin, err := m.adminChecker.IsAdmin(messageIn)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(notAdminTemplate, messageIn.ID),
+		}, nil
+	}
+
+	var text string
+
+	if args[0] == "set" {
+		text, err = m.setValue(messageIn, args[1:])
+	} else {
+		text, err = m.delete(messageIn, args[1])
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		text, err = fmt.Sprintf(unknownTemplate, args[1]), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []telegramclient.MessageStruct{
+		telegramclient.Reply(text, messageIn.ID),
+	}, nil
+}
+
+func (m Matcher) setValue(messageIn telegramclient.WebhookMessageStruct, args []string) (string, error) {
+	if len(args) != 2 {
+		return setUsageTemplate, nil
+	}
+
+	value, err := strconv.Atoi(args[1])
+	if err != nil {
+		return setUsageTemplate, nil //nolint:nilerr
+	}
+
+	correction, err := m.repo.SetValue(messageIn.Chat.ID, messageIn.From.ID, messageIn.ID, args[0], value)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(setTemplate, correction.Name, correction.NewValue, correction.OldValue), nil
+}
+
+func (m Matcher) delete(messageIn telegramclient.WebhookMessageStruct, name string) (string, error) {
+	correction, err := m.repo.Delete(messageIn.Chat.ID, messageIn.From.ID, name)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(deleteTemplate, correction.Name, correction.OldValue), nil
+}
+
+func formatGivers(records []interfaces.PlusplusGiverStruct) string {
+	lines := make([]string, 0, len(records)+1)
+	lines = append(lines, "Top-Geber")
+
+	for _, record := range records {
+		lines = append(lines, fmt.Sprintf(
+			"%+4d %4d %s",
+			record.Plus,
+			-record.Minus,
+			formatUser(record.UserID, record.Username),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatChanges(records []interfaces.PlusplusChangeStruct, loc *time.Location) string {
+	lines := make([]string, 0, len(records)+1)
+	lines = append(lines, "Letzte Änderungen")
+
+	for _, record := range records {
+		lines = append(lines, fmt.Sprintf(
+			"%s %+4d %s",
+			record.CreatedAt.In(loc).Format("02.01. 15:04"),
+			record.Delta,
+			formatUser(record.UserID, record.Username),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatTrend(weeks []interfaces.PlusplusWeekStruct) string {
+	lines := make([]string, 0, len(weeks)+1)
+	lines = append(lines, fmt.Sprintf("Verlauf der letzten %d Wochen", len(weeks)))
+
+	maxDelta := 0
+	for _, week := range weeks {
+		maxDelta = max(maxDelta, week.Delta, -week.Delta)
+	}
+
+	for _, week := range weeks {
+		_, number := week.Week.ISOWeek()
+
+		bar := ""
+		if week.Delta != 0 {
+			length := (abs(week.Delta)*maxBarLength + maxDelta - 1) / maxDelta
+
+			if week.Delta > 0 {
+				bar = strings.Repeat("█", length)
+			} else {
+				bar = strings.Repeat("░", length)
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("KW %02d %-*s %+d", number, maxBarLength, bar, week.Delta))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatUser(userID int64, username string) string {
+	if username == "" {
+		username = fmt.Sprintf("#%d", userID)
+	}
+
+	return telegramclient.EscapeMarkdown(username)
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
@@ -0,0 +1,147 @@
+package karma_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/karma"
+	"github.com/br0-space/bot/pkg/repo/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMatcher_Process(t *testing.T) {
+	t.Parallel()
+
+	week := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().FindByName(int64(789), "kaffee").Return(interfaces.Plusplus{ChatID: 789, Name: "kaffee", Value: 42}, nil)
+	repo.EXPECT().FindTopGivers(int64(789), "kaffee", 5).Return([]interfaces.PlusplusGiverStruct{
+		{UserID: 10, Username: "alice_", Plus: 12, Minus: 1},
+		{UserID: 20, Username: "", Plus: 0, Minus: 3},
+	}, nil)
+	repo.EXPECT().FindRecentChanges(int64(789), "kaffee", 5).Return([]interfaces.PlusplusChangeStruct{
+		{UserID: 10, Username: "alice_", Delta: 2, CreatedAt: time.Date(2026, 1, 7, 10, 30, 0, 0, time.Local)},
+	}, nil)
+	repo.EXPECT().GetWeeklyTrend(int64(789), "kaffee", 8, mock.Anything).Return([]interfaces.PlusplusWeekStruct{
+		{Week: week, Delta: 4},
+		{Week: week.AddDate(0, 0, 7), Delta: -2},
+		{Week: week.AddDate(0, 0, 14), Delta: 0},
+	}, nil)
+
+	replies, err := karma.MakeMatcher(repo, nil).Process(telegramclient.TestWebhookMessage("/karma Kaffee"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+
+	text := replies[0].Text
+	assert.True(t, strings.HasPrefix(text, "```\nKarma für kaffee: 42\n\nTop-Geber\n"))
+	assert.Contains(t, text, " +12   -1 alice\\_\n")
+	assert.Contains(t, text, "  +0   -3 \\#20\n")
+	assert.Contains(t, text, "07.01. 10:30   +2 alice\\_\n")
+	assert.Contains(t, text, "KW 02 ██████████ +4\n")
+	assert.Contains(t, text, "KW 03 ░░░░░      -2\n")
+	assert.Contains(t, text, "KW 04            +0\n")
+}
+
+func TestMatcher_ProcessUnknown(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().FindByName(int64(789), "foo").Return(interfaces.Plusplus{}, gorm.ErrRecordNotFound)
+
+	replies, err := karma.MakeMatcher(repo, nil).Process(telegramclient.TestWebhookMessage("/karma foo"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "foo hat noch kein Karma.", replies[0].Text)
+}
+
+func TestMatcher_ProcessWithoutEvents(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().FindByName(int64(789), "foo").Return(interfaces.Plusplus{ChatID: 789, Name: "foo", Value: 3}, nil)
+	repo.EXPECT().FindTopGivers(int64(789), "foo", 5).Return(nil, nil)
+	repo.EXPECT().FindRecentChanges(int64(789), "foo", 5).Return(nil, nil)
+	repo.EXPECT().GetWeeklyTrend(int64(789), "foo", 8, mock.Anything).Return(nil, nil)
+
+	replies, err := karma.MakeMatcher(repo, nil).Process(telegramclient.TestWebhookMessage("/karma foo"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "```\nKarma für foo: 3\n\nNoch keine Änderungen protokolliert.\n```", replies[0].Text)
+}
+
+func TestMatcher_ProcessWithoutName(t *testing.T) {
+	t.Parallel()
+
+	replies, err := karma.MakeMatcher(mocks.NewPlusplusRepoInterface(t), nil).Process(telegramclient.TestWebhookMessage("/karma"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "/karma kaffee")
+}
+
+type fakeAdminChecker struct {
+	admins map[int64]bool
+}
+
+func (c fakeAdminChecker) IsAdmin(messageIn telegramclient.WebhookMessageStruct) (bool, error) {
+	return c.admins[messageIn.From.ID], nil
+}
+
+func processCorrection(t *testing.T, repo *mocks.PlusplusRepoInterface, isAdmin bool, text string) string {
+	t.Helper()
+
+	messageIn := telegramclient.TestWebhookMessage(text)
+	messageIn.Chat.Type = "supergroup"
+
+	adminChecker := fakeAdminChecker{admins: map[int64]bool{456: isAdmin}}
+
+	replies, err := karma.MakeMatcher(repo, adminChecker).Process(messageIn)
+	require.NoError(t, err, text)
+	require.Len(t, replies, 1, text)
+
+	return replies[0].Text
+}
+
+func TestMatcher_ProcessSet(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().SetValue(int64(789), int64(456), int64(123), "foo", -3).Return(interfaces.PlusplusCorrection{
+		ChatID:   789,
+		UserID:   456,
+		Action:   interfaces.PlusplusActionSet,
+		Name:     "foo",
+		OldValue: -42,
+		NewValue: -3,
+	}, nil)
+	repo.EXPECT().SetValue(int64(789), int64(456), int64(123), "bar", 1).Return(interfaces.PlusplusCorrection{}, gorm.ErrRecordNotFound)
+
+	assert.Equal(t, "foo ist jetzt auf -3 (vorher -42).", processCorrection(t, repo, true, "/karma set Foo -3"))
+	assert.Equal(t, "bar hat noch kein Karma.", processCorrection(t, repo, true, "/karma set bar 1"))
+	assert.Equal(t, "Bitte gib einen Begriff und einen Wert an, z.B. /karma set kaffee 10", processCorrection(t, repo, true, "/karma set foo"))
+	assert.Equal(t, "Bitte gib einen Begriff und einen Wert an, z.B. /karma set kaffee 10", processCorrection(t, repo, true, "/karma set foo viel"))
+	assert.Equal(t, "Karma ändern dürfen nur Admins.", processCorrection(t, repo, false, "/karma set foo 3"))
+}
+
+func TestMatcher_ProcessDelete(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().Delete(int64(789), int64(456), "foo").Return(interfaces.PlusplusCorrection{
+		ChatID:   789,
+		UserID:   456,
+		Action:   interfaces.PlusplusActionDelete,
+		Name:     "foo",
+		OldValue: 7,
+		NewValue: 0,
+	}, nil)
+
+	assert.Equal(t, "foo wurde gelöscht, vorher war es auf 7.", processCorrection(t, repo, true, "/karma delete foo"))
+	assert.Equal(t, "Karma ändern dürfen nur Admins.", processCorrection(t, repo, false, "/karma delete foo"))
+}
@@ -0,0 +1,140 @@
+package matchers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	matcher "github.com/br0-space/bot-matcher"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const identifier = "matchers"
+
+var pattern = regexp.MustCompile(`(?i)^/(matchers|enable|disable)(@\w+)?($| )(\S+)?`)
+
+var help = []matcher.HelpStruct{{
+	Command:     `matchers`,
+	Description: `Zeigt an, welche Matcher in diesem Chat ein- oder ausgeschaltet sind.`,
+	Usage:       `/matchers`,
+	Example:     `/matchers`,
+}, {
+	Command:     `enable`,
+	Description: `Schaltet einen Matcher in diesem Chat ein. Nur für Admins.`,
+	Usage:       `/enable <Matcher>`,
+	Example:     `/enable buzzwords`,
+}, {
+	Command:     `disable`,
+	Description: `Schaltet einen Matcher in diesem Chat aus. Nur für Admins.`,
+	Usage:       `/disable <Matcher>`,
+	Example:     `/disable goodmorning`,
+}}
+
+const (
+	listTemplate     = "```\n%s\n```"
+	missingTemplate  = "Bitte gib den Namen eines Matchers an, z.B. /%s goodmorning"
+	unknownTemplate  = "Einen Matcher namens %s kenne ich nicht. Schau mal in /matchers nach."
+	enabledTemplate  = "%s ist in diesem Chat jetzt eingeschaltet."
+	disabledTemplate = "%s ist in diesem Chat jetzt ausgeschaltet."
+	notAdminTemplate = "Matcher ein- und ausschalten dürfen nur Admins."
+)
+
+type Matcher struct {
+	matcher.Matcher
+
+	identifiers  []string
+	settings     interfaces.MatcherSettingsServiceInterface
+	adminChecker interfaces.ChatAdminCheckerInterface
+}
+
+func MakeMatcher(
+	identifiers []string,
+	settings interfaces.MatcherSettingsServiceInterface,
+	adminChecker interfaces.ChatAdminCheckerInterface,
+) Matcher {
+	identifiers = slices.Clone(identifiers)
+	slices.Sort(identifiers)
+
+	return Matcher{
+		Matcher:      matcher.MakeMatcher(identifier, pattern, help),
+		identifiers:  identifiers,
+		settings:     settings,
+		adminChecker: adminChecker,
+	}
+}
+
+func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	match := m.CommandMatch(messageIn)
+	if match == nil {
+		return nil, errors.New("message does not match")
+	}
+
+	cmd := strings.ToLower(match[0])
+	if cmd == "matchers" {
+		return m.makeListReplies(messageIn.Chat.ID)
+	}
+
+	return m.processToggle(messageIn, cmd, strings.ToLower(match[3]))
+}
+
+func (m Matcher) makeListReplies(chatID int64) ([]telegramclient.MessageStruct, error) {
+	lines := make([]string, 0, len(m.identifiers))
+	for _, identifier := range m.identifiers {
+		status := "an"
+		if !m.settings.IsEnabled(chatID, identifier) {
+			status = "aus"
+		}
+
+		lines = append(lines, fmt.Sprintf("%-12s %s", identifier, status))
+	}
+
+	return []telegramclient.MessageStruct{
+		telegramclient.MarkdownMessage(fmt.Sprintf(listTemplate, strings.Join(lines, "\n"))),
+	}, nil
+}
+
+func (m Matcher) processToggle(
+	messageIn telegramclient.WebhookMessageStruct,
+	cmd string,
+	name string,
+) ([]telegramclient.MessageStruct, error) {
+	if name == "" {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(fmt.Sprintf(missingTemplate, cmd), messageIn.ID),
+		}, nil
+	}
+
+	if !slices.Contains(m.identifiers, name) {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(fmt.Sprintf(unknownTemplate, name), messageIn.ID),
+		}, nil
+	}
+
+	isAdmin, err := m.adminChecker.IsAdmin(messageIn)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(notAdminTemplate, messageIn.ID),
+		}, nil
+	}
+
+	enabled := cmd == "enable"
+	if err := m.settings.SetEnabled(messageIn.Chat.ID, name, enabled); err != nil {
+		return nil, err
+	}
+
+	template := disabledTemplate
+	if enabled {
+		template = enabledTemplate
+	}
+
+	return []telegramclient.MessageStruct{
+		telegramclient.Reply(fmt.Sprintf(template, name), messageIn.ID),
+	}, nil
+}
@@ -0,0 +1,107 @@
+package matchers_test
+
+import (
+	"errors"
+	"testing"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/pkg/matchers/matchers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSettings struct {
+	disabled map[string]bool
+}
+
+func (s *fakeSettings) IsEnabled(_ int64, identifier string) bool {
+	return !s.disabled[identifier]
+}
+
+func (s *fakeSettings) SetEnabled(_ int64, identifier string, enabled bool) error {
+	s.disabled[identifier] = !enabled
+
+	return nil
+}
+
+type fakeAdminChecker struct {
+	admins map[int64]bool
+	err    error
+}
+
+func (c fakeAdminChecker) IsAdmin(messageIn telegramclient.WebhookMessageStruct) (bool, error) {
+	return c.admins[messageIn.From.ID], c.err
+}
+
+func provideMatcher(settings *fakeSettings, adminChecker fakeAdminChecker) matchers.Matcher {
+	return matchers.MakeMatcher([]string{"ping", "goodmorning", "buzzwords"}, settings, adminChecker)
+}
+
+func newTestMessage(text string) telegramclient.WebhookMessageStruct {
+	message := telegramclient.TestWebhookMessage(text)
+	message.Chat.Type = "supergroup"
+
+	return message
+}
+
+var tests = []struct {
+	in          string
+	isAdmin     bool
+	reply       string
+	disabledNow []string
+}{
+	{"/matchers", false, "```\nbuzzwords    an\ngoodmorning  aus\nping         an\n```", []string{"goodmorning"}},
+	{"/disable ping", true, "ping ist in diesem Chat jetzt ausgeschaltet.", []string{"goodmorning", "ping"}},
+	{"/disable@bot Ping", true, "ping ist in diesem Chat jetzt ausgeschaltet.", []string{"goodmorning", "ping"}},
+	{"/enable goodmorning", true, "goodmorning ist in diesem Chat jetzt eingeschaltet.", []string{}},
+	{"/enable goodmorning", false, "Matcher ein- und ausschalten dürfen nur Admins.", []string{"goodmorning"}},
+	{"/disable foo", true, "Einen Matcher namens foo kenne ich nicht. Schau mal in /matchers nach.", []string{"goodmorning"}},
+	{"/disable", true, "Bitte gib den Namen eines Matchers an, z.B. /disable goodmorning", []string{"goodmorning"}},
+}
+
+func TestMatcher_Process(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range tests {
+		settings := &fakeSettings{disabled: map[string]bool{"goodmorning": true}}
+		adminChecker := fakeAdminChecker{admins: map[int64]bool{456: tt.isAdmin}, err: nil}
+
+		replies, err := provideMatcher(settings, adminChecker).Process(newTestMessage(tt.in))
+		require.NoError(t, err, tt.in)
+		require.Len(t, replies, 1, tt.in)
+		assert.Equal(t, tt.reply, replies[0].Text, tt.in)
+
+		disabled := make([]string, 0)
+
+		for _, identifier := range []string{"buzzwords", "goodmorning", "ping"} {
+			if settings.disabled[identifier] {
+				disabled = append(disabled, identifier)
+			}
+		}
+
+		assert.Equal(t, tt.disabledNow, disabled, tt.in)
+	}
+}
+
+func TestMatcher_ProcessAdminCheckFails(t *testing.T) {
+	t.Parallel()
+
+	settings := &fakeSettings{disabled: map[string]bool{}}
+	adminChecker := fakeAdminChecker{admins: map[int64]bool{}, err: errors.New("chat not found")}
+
+	_, err := provideMatcher(settings, adminChecker).Process(newTestMessage("/disable ping"))
+	require.Error(t, err)
+	assert.False(t, settings.disabled["ping"])
+}
+
+func TestMatcher_ProcessNoMatch(t *testing.T) {
+	t.Parallel()
+
+	settings := &fakeSettings{disabled: map[string]bool{}}
+
+	for _, in := range []string{"", "matchers", "/matchersfoo", "/enablex ping"} {
+		replies, err := provideMatcher(settings, fakeAdminChecker{}).Process(newTestMessage(in))
+		require.Error(t, err, in)
+		assert.Nil(t, replies, in)
+	}
+}
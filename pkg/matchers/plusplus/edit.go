@@ -0,0 +1,70 @@
+package plusplus
+
+import (
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+// ProcessEdit compares the tokens of an edited message with the changes the
+// original message made and applies only the difference. Net changes that add
+// votes must pass the rules like the votes of a new message, while the votes the
+// message already counted stay as they are.
+func (m Matcher) ProcessEdit(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	matches := m.InlineMatches(messageIn)
+
+	tokens, err := GetTokens(matches)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons, err := GetTokenReasons(messageIn.TextOrCaption())
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := GetTokenUsers(messageIn.TextOrCaption(), m.entities.Get(messageIn.Chat.ID, messageIn.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	replies := make([]telegramclient.MessageStruct, 0)
+	increments := make([]interfaces.PlusplusIncrementStruct, 0, len(tokens))
+
+	for _, token := range tokens {
+		reason := reasons[token.Name]
+
+		term := token.Name
+		if user, exists := users[token.Name]; exists {
+			term = userTerm(user.ID)
+			token = Token{Name: user.UsernameOrName(), Increment: token.Increment}
+		}
+
+		increments = append(increments, interfaces.PlusplusIncrementStruct{
+			Name:      term,
+			Increment: token.Increment,
+			Reason:    reason,
+		})
+	}
+
+	rule := func(term string, name string, delta int, total int, last time.Time) string {
+		rejection := m.revisionRejectionReason(messageIn, Token{Name: name, Increment: delta}, term, total, last)
+		if rejection != "" {
+			replies = append(replies, telegramclient.Reply(rejection, messageIn.ID))
+		}
+
+		return rejection
+	}
+
+	revisions, err := m.repo.ReviseMessage(messageIn.Chat.ID, messageIn.From.ID, messageIn.ID, increments, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range revisions {
+		replies = append(replies, Token{Name: revision.Name, Increment: revision.Delta}.MakeReply(revision.Value))
+	}
+
+	return replies, nil
+}
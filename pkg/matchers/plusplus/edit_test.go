@@ -0,0 +1,136 @@
+package plusplus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/plusplus"
+	"github.com/br0-space/bot/pkg/repo/mocks"
+	"github.com/br0-space/bot/pkg/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcher_ProcessEdit(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().ReviseMessage(int64(789), int64(456), int64(123), []interfaces.PlusplusIncrementStruct{
+		{Name: "foo", Increment: 2, Reason: "den Fix"},
+		{Name: "bar", Increment: -1, Reason: ""},
+	}, mock.Anything).Return([]interfaces.PlusplusRevisionStruct{
+		{Name: "foo", Delta: 1, Value: 5},
+		{Name: "baz", Delta: -1, Value: 0},
+	}, nil)
+
+	replies, err := plusplus.MakeMatcher(repo, telegram.NewEntitiesStore(), rulesConfig).
+		ProcessEdit(newTestMessage("foo+++ wegen den Fix\nbar--"))
+	require.NoError(t, err)
+	require.Len(t, replies, 2)
+	assert.Equal(t, `\[\+1\] *foo* ist jetzt auf *5*`, replies[0].Text)
+	assert.Equal(t, `\[\-1\] *baz* ist jetzt auf *0*`, replies[1].Text)
+}
+
+func TestMatcher_ProcessEditKeepsCountedVotes(t *testing.T) {
+	t.Parallel()
+
+	// foo++ was counted, foo+++++ goes beyond the maximum delta, so only the new votes are rejected
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().ReviseMessage(int64(789), int64(456), int64(123), []interfaces.PlusplusIncrementStruct{
+		{Name: "foo", Increment: 4, Reason: ""},
+	}, mock.Anything).Run(func(
+		_ int64,
+		_ int64,
+		_ int64,
+		_ []interfaces.PlusplusIncrementStruct,
+		rule interfaces.PlusplusRevisionRule,
+	) {
+		assert.NotEmpty(t, rule("foo", "foo", 3, 4, time.Time{}))
+	}).Return([]interfaces.PlusplusRevisionStruct{}, nil)
+
+	replies, err := plusplus.MakeMatcher(repo, telegram.NewEntitiesStore(), rulesConfig).
+		ProcessEdit(newTestMessage("foo+++++"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "Nicht so stürmisch!")
+}
+
+func TestMatcher_ProcessEditRejectsSelfIncrements(t *testing.T) {
+	t.Parallel()
+
+	// Self increments are passed on, so the repo can keep votes the message already counted
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().ReviseMessage(int64(789), int64(456), int64(123), []interfaces.PlusplusIncrementStruct{
+		{Name: "foobar", Increment: 1, Reason: ""},
+		{Name: "bar", Increment: 1, Reason: ""},
+	}, mock.Anything).Run(func(
+		_ int64,
+		_ int64,
+		_ int64,
+		_ []interfaces.PlusplusIncrementStruct,
+		rule interfaces.PlusplusRevisionRule,
+	) {
+		assert.NotEmpty(t, rule("foobar", "foobar", 1, 1, time.Time{}))
+		assert.Empty(t, rule("bar", "bar", 1, 1, time.Time{}))
+	}).Return([]interfaces.PlusplusRevisionStruct{
+		{Name: "bar", Delta: 1, Value: 1},
+	}, nil)
+
+	replies, err := plusplus.MakeMatcher(repo, telegram.NewEntitiesStore(), rulesConfig).
+		ProcessEdit(newTestMessage("foobar++ bar++"))
+	require.NoError(t, err)
+	require.Len(t, replies, 2)
+	assert.Contains(t, replies[0].Text, "Eigenlob stinkt!")
+	assert.Equal(t, `\[\+1\] *bar* ist jetzt auf *1*`, replies[1].Text)
+}
+
+func TestMatcher_ProcessEditAppliesCooldown(t *testing.T) {
+	t.Parallel()
+
+	// The vote was rejected by the cooldown, so the edit would add it as a new one
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().ReviseMessage(int64(789), int64(456), int64(123), []interfaces.PlusplusIncrementStruct{
+		{Name: "bar", Increment: 1, Reason: ""},
+	}, mock.Anything).Run(func(
+		_ int64,
+		_ int64,
+		_ int64,
+		_ []interfaces.PlusplusIncrementStruct,
+		rule interfaces.PlusplusRevisionRule,
+	) {
+		assert.NotEmpty(t, rule("bar", "bar", 1, 1, time.Now().Add(-10*time.Second)))
+		assert.Empty(t, rule("bar", "bar", 1, 1, time.Now().Add(-time.Hour)))
+	}).Return([]interfaces.PlusplusRevisionStruct{}, nil)
+
+	replies, err := plusplus.MakeMatcher(repo, telegram.NewEntitiesStore(), rulesConfig).
+		ProcessEdit(newTestMessage("bar++"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "Nicht so schnell")
+}
+
+func TestMatcher_ProcessEditAppliesSelfRuleToUserTerms(t *testing.T) {
+	t.Parallel()
+
+	// An alias may resolve to the user term, which only the repo knows
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().ReviseMessage(int64(789), int64(456), int64(123), []interfaces.PlusplusIncrementStruct{
+		{Name: "me", Increment: 1, Reason: ""},
+	}, mock.Anything).Run(func(
+		_ int64,
+		_ int64,
+		_ int64,
+		_ []interfaces.PlusplusIncrementStruct,
+		rule interfaces.PlusplusRevisionRule,
+	) {
+		assert.NotEmpty(t, rule(interfaces.PlusplusUserPrefix+"456", "Foobar", 1, 1, time.Time{}))
+	}).Return([]interfaces.PlusplusRevisionStruct{}, nil)
+
+	replies, err := plusplus.MakeMatcher(repo, telegram.NewEntitiesStore(), rulesConfig).
+		ProcessEdit(newTestMessage("me++"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "Eigenlob stinkt!")
+}
@@ -3,7 +3,9 @@ package plusplus
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode/utf16"
 
 	matcher "github.com/br0-space/bot-matcher"
 	telegramclient "github.com/br0-space/bot-telegramclient"
@@ -19,6 +21,12 @@ const (
 
 var pattern = regexp.MustCompile(fmt.Sprintf(`(^|\s)(%s*(%s+)%s*|%s+)([+]{2,}|[-]{2,}|\+-|—)`, characterPattern, emojiPattern, characterPattern, characterPattern))
 
+// reasonPattern matches a reason directly following a token, like "foo++ for the fix",
+// "foo++ wegen des Fixes" or "foo++ # the fix". It is applied to the text up to the next token.
+var reasonPattern = regexp.MustCompile(`(?i)^(?:\s+(?:for|für|wegen)\s+|\s*#\s*)([^\n]+)`)
+
+const maxReasonLength = 200
+
 var help []matcher.HelpStruct
 
 const template = `\[%s\] *%s* ist jetzt auf *%s*`
@@ -28,17 +36,23 @@ const template = `\[%s\] *%s* ist jetzt auf *%s*`
 type Matcher struct {
 	matcher.Matcher
 
-	repo interfaces.PlusplusRepoInterface
+	repo     interfaces.PlusplusRepoInterface
+	entities interfaces.MessageEntitiesStoreInterface
+	cfg      interfaces.PlusplusConfigStruct
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func MakeMatcher(
 	repo interfaces.PlusplusRepoInterface,
+	entities interfaces.MessageEntitiesStoreInterface,
+	cfg interfaces.PlusplusConfigStruct,
 ) Matcher {
 	return Matcher{
-		Matcher: matcher.MakeMatcher(identifier, pattern, help),
-		repo:    repo,
+		Matcher:  matcher.MakeMatcher(identifier, pattern, help),
+		repo:     repo,
+		entities: entities,
+		cfg:      cfg,
 	}
 }
 
@@ -50,14 +64,38 @@ func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]teleg
 		return nil, err
 	}
 
-	return m.makeRepliesFromTokens(tokens)
+	reasons, err := GetTokenReasons(messageIn.TextOrCaption())
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := GetTokenUsers(messageIn.TextOrCaption(), m.entities.Get(messageIn.Chat.ID, messageIn.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	return m.makeRepliesFromTokens(messageIn, tokens, reasons, users)
 }
 
-func (m Matcher) makeRepliesFromTokens(tokens []Token) ([]telegramclient.MessageStruct, error) {
+func (m Matcher) makeRepliesFromTokens(
+	messageIn telegramclient.WebhookMessageStruct,
+	tokens []Token,
+	reasons map[string]string,
+	users map[string]telegramclient.WebhookMessageUserStruct,
+) ([]telegramclient.MessageStruct, error) {
 	replies := make([]telegramclient.MessageStruct, 0)
 
 	for _, token := range tokens {
-		tokenReplies, err := m.makeRepliesFromToken(token)
+		reason := reasons[token.Name]
+
+		// Mentions of users without a username are stored by user ID and shown with the current name
+		term := token.Name
+		if user, exists := users[token.Name]; exists {
+			term = userTerm(user.ID)
+			token = Token{Name: user.UsernameOrName(), Increment: token.Increment}
+		}
+
+		tokenReplies, err := m.makeRepliesFromToken(messageIn, token, term, reason)
 		if err != nil {
 			return nil, err
 		}
@@ -68,8 +106,29 @@ func (m Matcher) makeRepliesFromTokens(tokens []Token) ([]telegramclient.Message
 	return replies, nil
 }
 
-func (m Matcher) makeRepliesFromToken(token Token) ([]telegramclient.MessageStruct, error) {
-	value, err := m.repo.Increment(token.Name, token.Increment)
+func (m Matcher) makeRepliesFromToken(
+	messageIn telegramclient.WebhookMessageStruct,
+	token Token,
+	term string,
+	reason string,
+) ([]telegramclient.MessageStruct, error) {
+	rejection, err := m.rejectionReason(messageIn, token, term)
+	if err != nil {
+		return nil, err
+	}
+
+	if rejection != "" {
+		return []telegramclient.MessageStruct{telegramclient.Reply(rejection, messageIn.ID)}, nil
+	}
+
+	value, err := m.repo.IncrementWithReason(
+		messageIn.Chat.ID,
+		messageIn.From.ID,
+		messageIn.ID,
+		term,
+		token.Increment,
+		reason,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -100,14 +159,9 @@ func GetTokenIncrements(matches []string) ([]string, map[string]int, error) {
 	increments := make(map[string]int, 0)
 
 	for _, match := range matches {
-		mode := regexp.MustCompile(`(\+{2,}|-{2,}|\+-|-\+|—)$`).FindString(match)
-		if mode == "" {
-			return nil, nil, fmt.Errorf(`unable to find mode in match "%s"`, match)
-		}
-
-		name := strings.ToLower(match[:len(match)-len(mode)])
-		if name == "" {
-			return nil, nil, fmt.Errorf(`unable to find name in match "%s"`, match)
+		name, mode, err := splitMatch(match)
+		if err != nil {
+			return nil, nil, err
 		}
 
 		increment, err := GetTokenIncrement(mode)
@@ -126,6 +180,109 @@ func GetTokenIncrements(matches []string) ([]string, map[string]int, error) {
 	return names, increments, nil
 }
 
+// GetTokenReasons returns the reasons given after the tokens in a text by
+// token name. Several reasons for the same name are joined.
+func GetTokenReasons(text string) (map[string]string, error) {
+	reasons := make(map[string]string)
+	indexes := pattern.FindAllStringIndex(text, -1)
+
+	for i, index := range indexes {
+		end := len(text)
+		if i+1 < len(indexes) {
+			end = indexes[i+1][0]
+		}
+
+		match := reasonPattern.FindStringSubmatch(text[index[1]:end])
+		if match == nil {
+			continue
+		}
+
+		reason := truncate(strings.TrimSpace(match[1]), maxReasonLength)
+		if reason == "" {
+			continue
+		}
+
+		name, _, err := splitMatch(strings.TrimSpace(text[index[0]:index[1]]))
+		if err != nil {
+			return nil, err
+		}
+
+		if reasons[name] != "" {
+			reason = reasons[name] + "; " + reason
+		}
+
+		reasons[name] = reason
+	}
+
+	return reasons, nil
+}
+
+// GetTokenUsers returns the users of text_mention entities by the name of the
+// token they belong to. A token belongs to a mention if its name ends where the
+// mention ends, so "Anna Maria++" refers to the user mentioned as "Anna Maria".
+func GetTokenUsers(
+	text string,
+	entities []interfaces.MessageEntityStruct,
+) (map[string]telegramclient.WebhookMessageUserStruct, error) {
+	users := make(map[string]telegramclient.WebhookMessageUserStruct)
+
+	for _, index := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := utf16Length(text[:index[4]]), utf16Length(text[:index[5]])
+
+		for _, entity := range entities {
+			if entity.Type != "text_mention" || entity.User == nil {
+				continue
+			}
+
+			if start < entity.Offset || end != entity.Offset+entity.Length {
+				continue
+			}
+
+			name, _, err := splitMatch(strings.TrimSpace(text[index[0]:index[1]]))
+			if err != nil {
+				return nil, err
+			}
+
+			users[name] = *entity.User
+		}
+	}
+
+	return users, nil
+}
+
+// utf16Length returns the length of a text in UTF-16 code units, in which Telegram counts entity offsets.
+func utf16Length(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+func userTerm(userID int64) string {
+	return interfaces.PlusplusUserPrefix + strconv.FormatInt(userID, 10)
+}
+
+// splitMatch splits a match into the lowercased name and the mode.
+func splitMatch(match string) (string, string, error) {
+	mode := regexp.MustCompile(`(\+{2,}|-{2,}|\+-|-\+|—)$`).FindString(match)
+	if mode == "" {
+		return "", "", fmt.Errorf(`unable to find mode in match "%s"`, match)
+	}
+
+	name := strings.ToLower(match[:len(match)-len(mode)])
+	if name == "" {
+		return "", "", fmt.Errorf(`unable to find name in match "%s"`, match)
+	}
+
+	return name, mode, nil
+}
+
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+
+	return string(runes[:length-1]) + "…"
+}
+
 func GetTokenIncrement(mode string) (int, error) {
 	switch {
 	case regexp.MustCompile(`^\++$`).MatchString(mode):
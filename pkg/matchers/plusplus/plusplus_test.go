@@ -2,15 +2,20 @@ package plusplus_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
 	"github.com/br0-space/bot/pkg/matchers/plusplus"
+	"github.com/br0-space/bot/pkg/repo/mocks"
+	"github.com/br0-space/bot/pkg/telegram"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func provideMatcher() plusplus.Matcher {
-	return plusplus.MakeMatcher(nil)
+	return plusplus.MakeMatcher(nil, nil, interfaces.PlusplusConfigStruct{})
 }
 
 func newTestMessage(text string) telegramclient.WebhookMessageStruct {
@@ -80,6 +85,35 @@ func TestParseTokens(t *testing.T) {
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+var getTokenReasonsTests = []struct {
+	in       string
+	expected map[string]string
+}{
+	{"foo++", map[string]string{}},
+	{"foo++ for fixing the build", map[string]string{"foo": "fixing the build"}},
+	{"Foo++ FÜR den Kaffee", map[string]string{"foo": "den Kaffee"}},
+	{"foo-- wegen des Wetters", map[string]string{"foo": "des Wetters"}},
+	{"foo++ # the fix", map[string]string{"foo": "the fix"}},
+	{"foo++ #the fix", map[string]string{"foo": "the fix"}},
+	{"foo++ fortunately", map[string]string{}},
+	{"foo++ bar++ for both", map[string]string{"bar": "both"}},
+	{"foo++ for one bar-- for another", map[string]string{"foo": "one", "bar": "another"}},
+	{"foo++ for one\nfoo++ for two", map[string]string{"foo": "one; two"}},
+	{"foo++ for " + strings.Repeat("x", 250), map[string]string{"foo": strings.Repeat("x", 199) + "…"}},
+}
+
+func TestGetTokenReasons(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range getTokenReasonsTests {
+		reasons, err := plusplus.GetTokenReasons(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.expected, reasons, tt.in)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 var getTokenIncrementTests = []struct {
 	in       string
 	expected int
@@ -117,3 +151,76 @@ func TestGetTokenIncrement(t *testing.T) {
 //		nil,
 //	)
 //}
+
+func TestMatcher_ProcessRecordsGiver(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().IncrementWithReason(int64(789), int64(456), int64(123), "foo", 2, "").Return(5, nil)
+	repo.EXPECT().IncrementWithReason(int64(789), int64(456), int64(123), "bar", -1, "").Return(-1, nil)
+
+	replies, err := plusplus.MakeMatcher(repo, telegram.NewEntitiesStore(), interfaces.PlusplusConfigStruct{}).Process(newTestMessage("foo+++ bar--"))
+	require.NoError(t, err)
+	require.Len(t, replies, 2)
+	assert.Equal(t, `\[\+2\] *foo* ist jetzt auf *5*`, replies[0].Text)
+}
+
+func TestMatcher_ProcessRecordsReason(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().IncrementWithReason(int64(789), int64(456), int64(123), "alice", 1, "fixing the build").Return(3, nil)
+
+	replies, err := plusplus.MakeMatcher(repo, telegram.NewEntitiesStore(), interfaces.PlusplusConfigStruct{}).Process(newTestMessage("alice++ for fixing the build"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+}
+
+var getTokenUsersTests = []struct {
+	in       string
+	entities []interfaces.MessageEntityStruct
+	expected []string
+}{
+	{"anna++", nil, []string{}},
+	{"anna++", []interfaces.MessageEntityStruct{{Type: "text_mention", Offset: 0, Length: 4}}, []string{}},
+	{"anna++", []interfaces.MessageEntityStruct{{Type: "mention", Offset: 0, Length: 4, User: &anna}}, []string{}},
+	{"Anna++ bob++", []interfaces.MessageEntityStruct{{Type: "text_mention", Offset: 0, Length: 4, User: &anna}}, []string{"anna"}},
+	{"foo++ Anna Maria++", []interfaces.MessageEntityStruct{{Type: "text_mention", Offset: 6, Length: 10, User: &anna}}, []string{"maria"}},
+	{"😁++ Anna++", []interfaces.MessageEntityStruct{{Type: "text_mention", Offset: 5, Length: 4, User: &anna}}, []string{"anna"}},
+	{"Annabell++", []interfaces.MessageEntityStruct{{Type: "text_mention", Offset: 0, Length: 4, User: &anna}}, []string{}},
+}
+
+var anna = telegramclient.WebhookMessageUserStruct{ID: 42, FirstName: "Anna"}
+
+func TestGetTokenUsers(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range getTokenUsersTests {
+		users, err := plusplus.GetTokenUsers(tt.in, tt.entities)
+		require.NoError(t, err, tt.in)
+
+		names := make([]string, 0, len(users))
+		for name, user := range users {
+			assert.Equal(t, anna, user, tt.in)
+
+			names = append(names, name)
+		}
+
+		assert.Equal(t, tt.expected, names, tt.in)
+	}
+}
+
+func TestMatcher_ProcessStoresTextMentionsByUser(t *testing.T) {
+	t.Parallel()
+
+	entities := telegram.NewEntitiesStore()
+	entities.Put(789, 123, []interfaces.MessageEntityStruct{{Type: "text_mention", Offset: 0, Length: 10, User: &anna}})
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().IncrementWithReason(int64(789), int64(456), int64(123), "user 42", 1, "the review").Return(4, nil)
+
+	replies, err := plusplus.MakeMatcher(repo, entities, interfaces.PlusplusConfigStruct{}).Process(newTestMessage("Anna Maria++ for the review"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, `\[\+1\] *Anna* ist jetzt auf *4*`, replies[0].Text)
+}
@@ -0,0 +1,117 @@
+package plusplus
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+)
+
+const (
+	selfIncrementTemplate = "Eigenlob stinkt! %s kannst du nicht selbst plusen."
+	maxDeltaTemplate      = "Nicht so stürmisch! %s kann pro Nachricht um höchstens %d geändert werden."
+	cooldownTemplate      = "Nicht so schnell! Du kannst %s erst in %s wieder ändern."
+)
+
+// rejectionReason checks a token and the term it is stored as against the
+// configured rules. It returns a reply explaining why the token must not be
+// applied, or an empty string if it may be applied.
+func (m Matcher) rejectionReason(messageIn telegramclient.WebhookMessageStruct, token Token, term string) (string, error) {
+	if rejection := m.tokenRejectionReason(messageIn, token, term); rejection != "" {
+		return rejection, nil
+	}
+
+	if m.cfg.Cooldown <= 0 {
+		return "", nil
+	}
+
+	last, err := m.repo.GetLastIncrementTime(messageIn.Chat.ID, messageIn.From.ID, term)
+	if err != nil {
+		return "", err
+	}
+
+	return m.cooldownRejectionReason(token, last), nil
+}
+
+// tokenRejectionReason checks the rules that only depend on the token itself,
+// not on earlier changes.
+func (m Matcher) tokenRejectionReason(messageIn telegramclient.WebhookMessageStruct, token Token, term string) string {
+	if rejection := m.selfRejectionReason(messageIn, token, term); rejection != "" {
+		return rejection
+	}
+
+	return m.maxDeltaRejectionReason(token)
+}
+
+// revisionRejectionReason checks the net change of a term in an edited message
+// that adds votes. The maximum delta applies to the change of the whole message,
+// given as total.
+func (m Matcher) revisionRejectionReason(
+	messageIn telegramclient.WebhookMessageStruct,
+	token Token,
+	term string,
+	total int,
+	last time.Time,
+) string {
+	if rejection := m.selfRejectionReason(messageIn, token, term); rejection != "" {
+		return rejection
+	}
+
+	if rejection := m.maxDeltaRejectionReason(Token{Name: token.Name, Increment: total}); rejection != "" {
+		return rejection
+	}
+
+	return m.cooldownRejectionReason(token, last)
+}
+
+func (m Matcher) selfRejectionReason(messageIn telegramclient.WebhookMessageStruct, token Token, term string) string {
+	self := isSelf(messageIn.From, token.Name) || term == userTerm(messageIn.From.ID)
+	if !m.cfg.AllowSelfIncrement && token.Increment > 0 && self {
+		return fmt.Sprintf(selfIncrementTemplate, token.Name)
+	}
+
+	return ""
+}
+
+func (m Matcher) maxDeltaRejectionReason(token Token) string {
+	if m.cfg.MaxDelta > 0 && (token.Increment > m.cfg.MaxDelta || token.Increment < -m.cfg.MaxDelta) {
+		return fmt.Sprintf(maxDeltaTemplate, token.Name, m.cfg.MaxDelta)
+	}
+
+	return ""
+}
+
+// cooldownRejectionReason checks if the user changed the term too recently.
+func (m Matcher) cooldownRejectionReason(token Token, last time.Time) string {
+	if m.cfg.Cooldown <= 0 || last.IsZero() {
+		return ""
+	}
+
+	if remaining := m.cfg.Cooldown - time.Since(last); remaining > 0 {
+		return fmt.Sprintf(cooldownTemplate, token.Name, formatDuration(remaining))
+	}
+
+	return ""
+}
+
+// isSelf checks if a term is the username (with or without @) or the first name of a user.
+func isSelf(user telegramclient.WebhookMessageUserStruct, name string) bool {
+	for _, own := range []string{user.Username, "@" + user.Username, user.FirstName} {
+		if strings.Trim(own, "@") != "" && strings.EqualFold(own, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatDuration rounds a duration up to whole seconds or, from one minute on, whole minutes.
+func formatDuration(duration time.Duration) string {
+	if duration <= time.Minute {
+		return fmt.Sprintf("%d Sekunden", int(math.Ceil(duration.Seconds())))
+	}
+
+	return fmt.Sprintf("%d Minuten", int(math.Ceil(duration.Minutes())))
+}
@@ -0,0 +1,117 @@
+package plusplus_test
+
+import (
+	"testing"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/plusplus"
+	"github.com/br0-space/bot/pkg/repo/mocks"
+	"github.com/br0-space/bot/pkg/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var rulesConfig = interfaces.PlusplusConfigStruct{
+	AllowSelfIncrement: false,
+	Cooldown:           time.Minute,
+	MaxDelta:           3,
+}
+
+var rejectionTests = []struct {
+	in       string
+	last     time.Duration
+	expected string
+}{
+	{"foobar++", 0, "Eigenlob stinkt! foobar kannst du nicht selbst plusen."},
+	{"@Foobar++", 0, "Eigenlob stinkt! @foobar kannst du nicht selbst plusen."},
+	{"alice++", 0, "Eigenlob stinkt! alice kannst du nicht selbst plusen."},
+	{"foo+++++", 0, "Nicht so stürmisch! foo kann pro Nachricht um höchstens 3 geändert werden."},
+	{"foo++ foo++ foo++ foo++", 0, "Nicht so stürmisch! foo kann pro Nachricht um höchstens 3 geändert werden."},
+	{"foo-----", 0, "Nicht so stürmisch! foo kann pro Nachricht um höchstens 3 geändert werden."},
+	{"foo++", 20 * time.Second, "Nicht so schnell! Du kannst foo erst in 40 Sekunden wieder ändern."},
+}
+
+func TestMatcher_ProcessRejections(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range rejectionTests {
+		repo := mocks.NewPlusplusRepoInterface(t)
+		if tt.last > 0 {
+			repo.EXPECT().GetLastIncrementTime(int64(789), int64(456), "foo").Return(time.Now().Add(-tt.last), nil)
+		}
+
+		messageIn := newTestMessage(tt.in)
+		messageIn.From.FirstName = "Alice"
+
+		replies, err := plusplus.MakeMatcher(repo, telegram.NewEntitiesStore(), rulesConfig).Process(messageIn)
+		require.NoError(t, err, tt.in)
+		require.Len(t, replies, 1, tt.in)
+		assert.Equal(t, tt.expected, replies[0].Text, tt.in)
+		assert.Equal(t, int64(123), replies[0].ReplyToMessageID, tt.in)
+	}
+}
+
+var acceptanceTests = []struct {
+	in        string
+	cfg       interfaces.PlusplusConfigStruct
+	last      time.Time
+	increment int
+}{
+	{"foo+++", rulesConfig, time.Time{}, 2},
+	{"foo---", rulesConfig, time.Now().Add(-2 * time.Minute), -2},
+	{"foobar--", rulesConfig, time.Time{}, -1},
+	{"foobar++", interfaces.PlusplusConfigStruct{AllowSelfIncrement: true, Cooldown: 0, MaxDelta: 0}, time.Time{}, 1},
+	{"foo++++++++", interfaces.PlusplusConfigStruct{AllowSelfIncrement: false, Cooldown: 0, MaxDelta: 0}, time.Time{}, 7},
+}
+
+func TestMatcher_ProcessAcceptance(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range acceptanceTests {
+		repo := mocks.NewPlusplusRepoInterface(t)
+		if tt.cfg.Cooldown > 0 {
+			repo.EXPECT().GetLastIncrementTime(int64(789), int64(456), mock.Anything).Return(tt.last, nil)
+		}
+
+		repo.EXPECT().IncrementWithReason(int64(789), int64(456), int64(123), mock.Anything, tt.increment, "").Return(tt.increment, nil)
+
+		replies, err := plusplus.MakeMatcher(repo, telegram.NewEntitiesStore(), tt.cfg).Process(newTestMessage(tt.in))
+		require.NoError(t, err, tt.in)
+		require.Len(t, replies, 1, tt.in)
+		assert.Contains(t, replies[0].Text, "ist jetzt auf", tt.in)
+	}
+}
+
+func TestMatcher_ProcessRejectsOnlyOffendingTokens(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().GetLastIncrementTime(int64(789), int64(456), "bar").Return(time.Time{}, nil)
+	repo.EXPECT().IncrementWithReason(int64(789), int64(456), int64(123), "bar", 1, "").Return(1, nil)
+
+	replies, err := plusplus.MakeMatcher(repo, telegram.NewEntitiesStore(), rulesConfig).Process(newTestMessage("foobar++ bar++"))
+	require.NoError(t, err)
+	require.Len(t, replies, 2)
+	assert.Contains(t, replies[0].Text, "Eigenlob stinkt!")
+	assert.Contains(t, replies[1].Text, "ist jetzt auf")
+}
+
+func TestMatcher_ProcessRejectsSelfTextMention(t *testing.T) {
+	t.Parallel()
+
+	entities := telegram.NewEntitiesStore()
+	entities.Put(789, 123, []interfaces.MessageEntityStruct{{
+		Type:   "text_mention",
+		Offset: 0,
+		Length: 3,
+		User:   &telegramclient.WebhookMessageUserStruct{ID: 456, FirstName: "Ich"},
+	}})
+
+	replies, err := plusplus.MakeMatcher(mocks.NewPlusplusRepoInterface(t), entities, rulesConfig).Process(newTestMessage("Ich++"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Eigenlob stinkt! Ich kannst du nicht selbst plusen.", replies[0].Text)
+}
@@ -0,0 +1,161 @@
+package roll
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	matcher "github.com/br0-space/bot-matcher"
+	"github.com/br0-space/bot/pkg/config"
+	"github.com/spf13/viper"
+)
+
+const defaultLanguage = "en"
+
+// configFile is where matcher.LoadMatcherConfig looks for the config of the matcher.
+const configFile = "config/" + identifier + ".yaml"
+
+// FlavourTexts are the messages shown for critical hits and failures.
+type FlavourTexts struct {
+	CriticalHits     []string `mapstructure:"critical_hits"`
+	CriticalFailures []string `mapstructure:"critical_failures"`
+}
+
+// MessageSet contains the flavour texts of a language. The texts in Dice
+// replace the general ones for a die size, like d20 for a natural 20.
+type MessageSet struct {
+	FlavourTexts `mapstructure:",squash"`
+
+	Dice map[string]FlavourTexts `mapstructure:"dice"`
+}
+
+type Config struct {
+	matcher.Config
+
+	Language string                `mapstructure:"language"`
+	Messages map[string]MessageSet `mapstructure:"messages"`
+}
+
+// GetEmbeddedMatcherConfigPtr exposes a pointer to the embedded matcher.Config.
+// This allows the generic matcher.WithCustomConfigType to wire base-level config behaviors like IsEnabled.
+func (c Config) GetEmbeddedMatcherConfigPtr() *matcher.Config {
+	return &c.Config
+}
+
+// LoadDefaultConfig loads the flavour texts shipped with the bot.
+func LoadDefaultConfig() (Config, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	if err := v.ReadConfig(bytes.NewReader(config.RollDefaults)); err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// LoadConfig loads config/roll.yaml on top of the defaults. The file is
+// optional, without it the defaults apply, but a broken file is an error.
+func LoadConfig(defaults Config) (Config, error) {
+	if _, err := os.Stat(configFile); errors.Is(err, fs.ErrNotExist) {
+		return defaults, nil
+	} else if err != nil {
+		return Config{}, err
+	}
+
+	cfgs, err := matcher.LoadMatcherConfig[Config](identifier)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if len(cfgs) == 0 {
+		return defaults, nil
+	}
+
+	return cfgs[0].WithDefaults(defaults), nil
+}
+
+// CriticalHits returns the critical hit messages for dice with the given number of sides.
+func (c Config) CriticalHits(sides int) []string {
+	return c.flavourTexts(sides, func(texts FlavourTexts) []string { return texts.CriticalHits })
+}
+
+// CriticalFailures returns the critical failure messages for dice with the given number of sides.
+func (c Config) CriticalFailures(sides int) []string {
+	return c.flavourTexts(sides, func(texts FlavourTexts) []string { return texts.CriticalFailures })
+}
+
+// flavourTexts picks the messages of the configured language, falling back to
+// English, and prefers the messages for the die size over the general ones.
+func (c Config) flavourTexts(sides int, pick func(texts FlavourTexts) []string) []string {
+	set, exists := c.Messages[strings.ToLower(c.Language)]
+	if !exists {
+		set = c.Messages[defaultLanguage]
+	}
+
+	if dice, exists := set.Dice[fmt.Sprintf("d%d", sides)]; exists && len(pick(dice)) > 0 {
+		return pick(dice)
+	}
+
+	return pick(set.FlavourTexts)
+}
+
+// WithDefaults fills everything the config doesn't define from the defaults,
+// so config/roll.yaml only needs to contain the messages it changes.
+func (c Config) WithDefaults(defaults Config) Config {
+	if c.Language == "" {
+		c.Language = defaults.Language
+	}
+
+	messages := make(map[string]MessageSet, len(defaults.Messages)+len(c.Messages))
+
+	for language, set := range defaults.Messages {
+		messages[language] = set
+	}
+
+	for language, set := range c.Messages {
+		messages[language] = set.withDefaults(defaults.Messages[language])
+	}
+
+	c.Messages = messages
+
+	return c
+}
+
+func (s MessageSet) withDefaults(defaults MessageSet) MessageSet {
+	s.FlavourTexts = s.FlavourTexts.withDefaults(defaults.FlavourTexts)
+
+	dice := make(map[string]FlavourTexts, len(defaults.Dice)+len(s.Dice))
+
+	for size, texts := range defaults.Dice {
+		dice[size] = texts
+	}
+
+	for size, texts := range s.Dice {
+		dice[size] = texts.withDefaults(defaults.Dice[size])
+	}
+
+	s.Dice = dice
+
+	return s
+}
+
+func (t FlavourTexts) withDefaults(defaults FlavourTexts) FlavourTexts {
+	if len(t.CriticalHits) == 0 {
+		t.CriticalHits = defaults.CriticalHits
+	}
+
+	if len(t.CriticalFailures) == 0 {
+		t.CriticalFailures = defaults.CriticalFailures
+	}
+
+	return t
+}
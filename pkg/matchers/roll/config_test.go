@@ -0,0 +1,100 @@
+package roll_test
+
+import (
+	"testing"
+
+	"github.com/br0-space/bot/pkg/matchers/roll"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Config Tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func TestLoadDefaultConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := roll.LoadDefaultConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, "en", cfg.Language)
+	assert.Contains(t, cfg.Messages, "en")
+	assert.Contains(t, cfg.Messages, "de")
+
+	for language, set := range cfg.Messages {
+		assert.NotEmpty(t, set.CriticalHits, language)
+		assert.NotEmpty(t, set.CriticalFailures, language)
+		assert.NotEmpty(t, set.Dice["d20"].CriticalHits, language)
+		assert.NotEmpty(t, set.Dice["d20"].CriticalFailures, language)
+	}
+}
+
+func TestLoadConfig_WithoutFile(t *testing.T) {
+	t.Parallel()
+
+	defaults, err := roll.LoadDefaultConfig()
+	require.NoError(t, err)
+
+	// There is no config/roll.yaml next to the tests
+	cfg, err := roll.LoadConfig(defaults)
+	require.NoError(t, err)
+	assert.Equal(t, defaults, cfg)
+}
+
+func TestConfig_FlavourTexts(t *testing.T) {
+	t.Parallel()
+
+	cfg := roll.Config{
+		Language: "de",
+		Messages: map[string]roll.MessageSet{
+			"en": {FlavourTexts: roll.FlavourTexts{CriticalHits: []string{"hit"}, CriticalFailures: []string{"fail"}}},
+			"de": {
+				FlavourTexts: roll.FlavourTexts{CriticalHits: []string{"Treffer"}, CriticalFailures: []string{"Patzer"}},
+				Dice: map[string]roll.FlavourTexts{
+					"d20": {CriticalHits: []string{"Natürliche 20"}},
+				},
+			},
+		},
+	}
+
+	// Die size specific messages only apply to that die size
+	assert.Equal(t, []string{"Natürliche 20"}, cfg.CriticalHits(20))
+	assert.Equal(t, []string{"Treffer"}, cfg.CriticalHits(6))
+	assert.Equal(t, []string{"Treffer"}, cfg.CriticalHits(0))
+
+	// Missing die size specific messages fall back to the general ones
+	assert.Equal(t, []string{"Patzer"}, cfg.CriticalFailures(20))
+
+	// Unknown languages fall back to English
+	cfg.Language = "fr"
+	assert.Equal(t, []string{"hit"}, cfg.CriticalHits(20))
+	assert.Equal(t, []string{"fail"}, cfg.CriticalFailures(20))
+}
+
+func TestConfig_WithDefaults(t *testing.T) {
+	t.Parallel()
+
+	defaults, err := roll.LoadDefaultConfig()
+	require.NoError(t, err)
+
+	cfg := roll.Config{
+		Language: "de",
+		Messages: map[string]roll.MessageSet{
+			"de": {
+				Dice: map[string]roll.FlavourTexts{
+					"d20": {CriticalHits: []string{"Volltreffer"}},
+				},
+			},
+		},
+	}.WithDefaults(defaults)
+
+	assert.Equal(t, "de", cfg.Language)
+	assert.Equal(t, []string{"Volltreffer"}, cfg.CriticalHits(20))
+	assert.Equal(t, defaults.Messages["de"].Dice["d20"].CriticalFailures, cfg.CriticalFailures(20))
+	assert.Equal(t, defaults.Messages["de"].CriticalHits, cfg.CriticalHits(6))
+	assert.Equal(t, defaults.Messages["en"], cfg.Messages["en"])
+
+	// An empty config is the same as the defaults
+	assert.Equal(t, defaults, roll.Config{}.WithDefaults(defaults))
+}
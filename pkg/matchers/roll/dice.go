@@ -3,49 +3,194 @@ package roll
 import (
 	"crypto/rand"
 	"math/big"
+	"sort"
 )
 
+// maxRolls limits the dice rolled for a whole expression including explosions and rerolls.
+const maxRolls = 1000
+
 // DiceRoll represents a dice roll with its configuration and results.
 type DiceRoll struct {
-	count       int
-	sides       int
+	expression  Expression
+	terms       []TermResult
 	results     []int
 	threshold   *int
 	keepHighest bool
 }
 
+// TermResult is the outcome of a single term of the expression.
+type TermResult struct {
+	Term  Term
+	Dice  []DieResult
+	Total int
+}
+
+// DieResult is the outcome of a single die.
+type DieResult struct {
+	Value int
+	Sides int
+	// Rerolled contains the earlier values of the die that were rerolled.
+	Rerolled []int
+	// Exploded is set if the die showed its maximum and added another die.
+	Exploded bool
+	// Dropped is set if the die isn't kept by kh or kl.
+	Dropped bool
+}
+
 // NewDiceRoll creates a new DiceRoll instance.
 func NewDiceRoll(count, sides int, threshold *int, keepHighest bool) *DiceRoll {
+	return NewExpressionRoll(
+		Expression{Terms: []Term{{Sign: 1, Dice: &DiceTerm{Count: count, Sides: sides}}}},
+		threshold,
+		keepHighest,
+	)
+}
+
+// NewExpressionRoll creates a new DiceRoll instance for a parsed dice expression.
+func NewExpressionRoll(expression Expression, threshold *int, keepHighest bool) *DiceRoll {
 	return &DiceRoll{
-		count:       count,
-		sides:       sides,
+		expression:  expression,
+		terms:       []TermResult{},
 		results:     []int{},
 		threshold:   threshold,
 		keepHighest: keepHighest,
 	}
 }
 
-// Roll performs the dice roll and stores the results.
+// Roll performs the dice roll and stores the results. It returns the values of all kept dice.
 func (d *DiceRoll) Roll() []int {
-	d.results = make([]int, d.count)
-	for i := range d.count {
-		n, _ := rand.Int(rand.Reader, big.NewInt(int64(d.sides)))
-		d.results[i] = int(n.Int64()) + 1
+	budget := maxRolls
+
+	d.terms = make([]TermResult, len(d.expression.Terms))
+	d.results = make([]int, 0, d.expression.DiceCount())
+
+	for i, term := range d.expression.Terms {
+		d.terms[i] = rollTerm(term, &budget)
+
+		for _, die := range d.terms[i].Dice {
+			if !die.Dropped {
+				d.results = append(d.results, die.Value)
+			}
+		}
 	}
 
 	return d.results
 }
 
+// rollTerm rolls the dice of a term and applies its modifiers in the order
+// rerolls, explosions and kept dice. Rerolls and explosions stop when the
+// budget of dice for the whole expression is used up.
+func rollTerm(term Term, budget *int) TermResult {
+	if term.Dice == nil {
+		return TermResult{Term: term, Dice: nil, Total: term.Sign * term.Constant}
+	}
+
+	dice := term.Dice
+	results := make([]DieResult, 0, dice.Count)
+
+	roll := func() DieResult {
+		*budget--
+
+		die := DieResult{Value: rollDie(dice.Sides), Sides: dice.Sides}
+		for die.Value <= dice.Reroll && *budget > 0 {
+			*budget--
+
+			die.Rerolled = append(die.Rerolled, die.Value)
+			die.Value = rollDie(dice.Sides)
+		}
+
+		return die
+	}
+
+	for range dice.Count {
+		die := roll()
+		results = append(results, die)
+
+		for dice.Explode && die.Value == dice.Sides && *budget > 0 {
+			results[len(results)-1].Exploded = true
+
+			die = roll()
+			results = append(results, die)
+		}
+	}
+
+	if dice.Keep > 0 {
+		dropDice(results, dice.Keep, dice.KeepLowest)
+	}
+
+	total := 0
+
+	for _, die := range results {
+		if !die.Dropped {
+			total += die.Value
+		}
+	}
+
+	return TermResult{Term: term, Dice: results, Total: term.Sign * total}
+}
+
+// dropDice marks all dice as dropped except the highest or lowest ones.
+func dropDice(results []DieResult, keep int, keepLowest bool) {
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		if keepLowest {
+			return results[order[a]].Value < results[order[b]].Value
+		}
+
+		return results[order[a]].Value > results[order[b]].Value
+	})
+
+	for _, i := range order[min(keep, len(order)):] {
+		results[i].Dropped = true
+	}
+}
+
+// rollDie rolls a single die.
+func rollDie(sides int) int {
+	n, _ := rand.Int(rand.Reader, big.NewInt(int64(sides)))
+
+	return int(n.Int64()) + 1
+}
+
+// GetExpression returns the dice expression.
+func (d *DiceRoll) GetExpression() Expression {
+	return d.expression
+}
+
+// GetTerms returns the results per term of the expression.
+func (d *DiceRoll) GetTerms() []TermResult {
+	return d.terms
+}
+
+// GetFaces returns every face rolled per die size, including dropped, rerolled
+// and exploded dice. Unlike the results, they are a fair sample of the dice.
+func (d *DiceRoll) GetFaces() map[int][]int {
+	faces := make(map[int][]int)
+
+	for _, term := range d.terms {
+		for _, die := range term.Dice {
+			faces[die.Sides] = append(faces[die.Sides], die.Rerolled...)
+			faces[die.Sides] = append(faces[die.Sides], die.Value)
+		}
+	}
+
+	return faces
+}
+
 // GetResults returns the roll results.
 func (d *DiceRoll) GetResults() []int {
 	return d.results
 }
 
-// Sum returns the sum of all dice results.
+// Sum returns the total of the expression.
 func (d *DiceRoll) Sum() int {
 	sum := 0
-	for _, result := range d.results {
-		sum += result
+	for _, term := range d.terms {
+		sum += term.Total
 	}
 
 	return sum
@@ -67,30 +212,27 @@ func (d *DiceRoll) GetHighest() int {
 	return highest
 }
 
-// IsCriticalHit checks if all dice show maximum value.
+// IsCriticalHit checks if all kept dice show their maximum value.
 func (d *DiceRoll) IsCriticalHit() bool {
-	if len(d.results) == 0 {
-		return false
-	}
-
-	for _, result := range d.results {
-		if result != d.sides {
-			return false
-		}
-	}
-
-	return true
+	return d.allKeptDice(func(die DieResult) bool { return die.Value == die.Sides })
 }
 
-// IsCriticalFailure checks if all dice show minimum value (1).
+// IsCriticalFailure checks if all kept dice show minimum value (1).
 func (d *DiceRoll) IsCriticalFailure() bool {
+	return d.allKeptDice(func(die DieResult) bool { return die.Value == 1 })
+}
+
+// allKeptDice checks if there are kept dice and all of them satisfy the condition.
+func (d *DiceRoll) allKeptDice(condition func(die DieResult) bool) bool {
 	if len(d.results) == 0 {
 		return false
 	}
 
-	for _, result := range d.results {
-		if result != 1 {
-			return false
+	for _, term := range d.terms {
+		for _, die := range term.Dice {
+			if !die.Dropped && !condition(die) {
+				return false
+			}
 		}
 	}
 
@@ -116,14 +258,35 @@ func (d *DiceRoll) IsSuccess() *bool {
 	return &success
 }
 
-// GetCount returns the number of dice.
+// GetCount returns the number of dice in the expression.
 func (d *DiceRoll) GetCount() int {
-	return d.count
+	return d.expression.DiceCount()
 }
 
-// GetSides returns the number of sides per die.
+// GetSides returns the number of sides per die, or of the largest die in mixed expressions.
 func (d *DiceRoll) GetSides() int {
-	return d.sides
+	return d.expression.MaxSides()
+}
+
+// GetKeptSides returns the number of sides of the kept dice, or 0 if they differ in size.
+func (d *DiceRoll) GetKeptSides() int {
+	sides := 0
+
+	for _, term := range d.terms {
+		for _, die := range term.Dice {
+			if die.Dropped {
+				continue
+			}
+
+			if sides != 0 && sides != die.Sides {
+				return 0
+			}
+
+			sides = die.Sides
+		}
+	}
+
+	return sides
 }
 
 // GetThreshold returns the threshold if set.
@@ -379,3 +379,164 @@ func TestDiceRoll_GettersBeforeRoll(t *testing.T) {
 	assert.False(t, dice.IsCriticalHit())
 	assert.False(t, dice.IsCriticalFailure())
 }
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Expression Roll Tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func rollExpression(t *testing.T, input string) *roll.DiceRoll {
+	t.Helper()
+
+	expression, err := roll.ParseExpression(input)
+	require.NoError(t, err)
+
+	dice := roll.NewExpressionRoll(expression, nil, false)
+	dice.Roll()
+
+	return dice
+}
+
+func TestDiceRoll_ExpressionSum(t *testing.T) {
+	t.Parallel()
+
+	for range 100 {
+		dice := rollExpression(t, "1d20+1d4-2")
+
+		terms := dice.GetTerms()
+		require.Len(t, terms, 3)
+		assert.Equal(t, -2, terms[2].Total)
+		assert.Equal(t, terms[0].Total+terms[1].Total-2, dice.Sum())
+		assert.Len(t, dice.GetResults(), 2)
+		assert.Equal(t, 2, dice.GetCount())
+		assert.Equal(t, 20, dice.GetSides())
+	}
+}
+
+func TestDiceRoll_KeepHighestAndLowest(t *testing.T) {
+	t.Parallel()
+
+	for range 100 {
+		dice := rollExpression(t, "4d6kh3")
+
+		dropped := 0
+		lowestKept := 6
+
+		for _, die := range dice.GetTerms()[0].Dice {
+			if !die.Dropped {
+				lowestKept = min(lowestKept, die.Value)
+			}
+		}
+
+		for _, die := range dice.GetTerms()[0].Dice {
+			if die.Dropped {
+				dropped++
+
+				assert.LessOrEqual(t, die.Value, lowestKept)
+			}
+		}
+
+		assert.Equal(t, 1, dropped)
+		assert.Len(t, dice.GetResults(), 3)
+
+		disadvantage := rollExpression(t, "2d20kl1")
+		require.Len(t, disadvantage.GetResults(), 1)
+
+		for _, die := range disadvantage.GetTerms()[0].Dice {
+			assert.GreaterOrEqual(t, die.Value, disadvantage.GetResults()[0])
+		}
+	}
+}
+
+func TestDiceRoll_Exploding(t *testing.T) {
+	t.Parallel()
+
+	for range 100 {
+		dice := rollExpression(t, "3d2!")
+
+		results := dice.GetTerms()[0].Dice
+		assert.GreaterOrEqual(t, len(results), 3)
+
+		for i, die := range results {
+			// Every maximum adds a die, the last one can't have exploded
+			assert.Equal(t, die.Value == 2 && i < len(results)-1, die.Exploded)
+		}
+	}
+}
+
+func TestDiceRoll_ExplodingIsLimited(t *testing.T) {
+	t.Parallel()
+
+	dice := rollExpression(t, "100d2!")
+	assert.LessOrEqual(t, len(dice.GetTerms()[0].Dice), 1000)
+}
+
+func TestDiceRoll_Reroll(t *testing.T) {
+	t.Parallel()
+
+	for range 100 {
+		dice := rollExpression(t, "4d6r2")
+
+		for _, die := range dice.GetTerms()[0].Dice {
+			assert.Greater(t, die.Value, 2)
+
+			for _, rerolled := range die.Rerolled {
+				assert.LessOrEqual(t, rerolled, 2)
+			}
+		}
+	}
+}
+
+func TestDiceRoll_Percentile(t *testing.T) {
+	t.Parallel()
+
+	for range 100 {
+		dice := rollExpression(t, "d%")
+		require.Len(t, dice.GetResults(), 1)
+		assert.GreaterOrEqual(t, dice.Sum(), 1)
+		assert.LessOrEqual(t, dice.Sum(), 100)
+	}
+}
+
+func TestDiceRoll_GetFaces(t *testing.T) {
+	t.Parallel()
+
+	for range 100 {
+		dice := rollExpression(t, "4d6r1kh3+1d20")
+
+		faces := dice.GetFaces()
+		require.Len(t, faces, 2)
+		assert.Len(t, faces[20], 1)
+
+		// Dropped and rerolled dice are faces, too
+		rolled := 0
+		for _, die := range dice.GetTerms()[0].Dice {
+			rolled += 1 + len(die.Rerolled)
+		}
+
+		assert.Len(t, faces[6], rolled)
+	}
+}
+
+func TestDiceRoll_GetKeptSides(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		notation string
+		expected int
+	}{
+		{"1d20", 20},
+		{"1d20+5", 20},
+		{"2d20kh1", 20},
+		{"1d20+1d20", 20},
+		{"d%", 100},
+		{"1d20+1d4", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, rollExpression(t, tt.notation).GetKeptSides())
+		})
+	}
+}
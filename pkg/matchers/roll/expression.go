@@ -0,0 +1,442 @@
+package roll
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	percentileSides = 100
+	maxTerms        = 20
+	maxNumberDigits = 6
+)
+
+// Expression is a parsed dice expression like 2d6+3 or 1d20+1d4-2.
+type Expression struct {
+	Terms []Term
+}
+
+// Term is a part of an expression, either a group of dice or a constant.
+type Term struct {
+	// Sign is 1 for added and -1 for subtracted terms.
+	Sign     int
+	Dice     *DiceTerm
+	Constant int
+}
+
+// DiceTerm is a group of dice of the same size with its modifiers, like 4d6kh3.
+type DiceTerm struct {
+	Count int
+	Sides int
+	// Percentile is set for d%, which is a d100.
+	Percentile bool
+	// Keep is the number of dice kept, 0 keeps all of them.
+	Keep       int
+	KeepLowest bool
+	// Explode rolls another die for every die showing its maximum.
+	Explode bool
+	// Reroll rolls dice showing this value or less again, 0 disables rerolls.
+	Reroll int
+}
+
+// String returns the normalized notation of the expression.
+func (e Expression) String() string {
+	var builder strings.Builder
+
+	for i, term := range e.Terms {
+		if term.Sign < 0 {
+			builder.WriteString("-")
+		} else if i > 0 {
+			builder.WriteString("+")
+		}
+
+		builder.WriteString(term.notation())
+	}
+
+	return builder.String()
+}
+
+// DiceCount returns the number of dice in the expression, without explosions and rerolls.
+func (e Expression) DiceCount() int {
+	count := 0
+
+	for _, term := range e.Terms {
+		if term.Dice != nil {
+			count += term.Dice.Count
+		}
+	}
+
+	return count
+}
+
+// MaxSides returns the number of sides of the largest die in the expression.
+func (e Expression) MaxSides() int {
+	sides := 0
+
+	for _, term := range e.Terms {
+		if term.Dice != nil {
+			sides = max(sides, term.Dice.Sides)
+		}
+	}
+
+	return sides
+}
+
+// IsSimple checks if the expression is a single group of dice without modifiers.
+func (e Expression) IsSimple() bool {
+	if len(e.Terms) != 1 || e.Terms[0].Dice == nil {
+		return false
+	}
+
+	dice := e.Terms[0].Dice
+
+	return !dice.Percentile && dice.Keep == 0 && !dice.Explode && dice.Reroll == 0
+}
+
+// notation returns the notation of a term without its sign.
+func (t Term) notation() string {
+	if t.Dice == nil {
+		return strconv.Itoa(t.Constant)
+	}
+
+	return t.Dice.String()
+}
+
+// String returns the notation of a group of dice, like 4d6kh3 or d%.
+func (d DiceTerm) String() string {
+	var builder strings.Builder
+
+	if d.Percentile {
+		if d.Count != 1 {
+			builder.WriteString(strconv.Itoa(d.Count))
+		}
+
+		builder.WriteString("d%")
+	} else {
+		fmt.Fprintf(&builder, "%dd%d", d.Count, d.Sides)
+	}
+
+	if d.Reroll > 0 {
+		fmt.Fprintf(&builder, "r%d", d.Reroll)
+	}
+
+	if d.Explode {
+		builder.WriteString("!")
+	}
+
+	if d.Keep > 0 {
+		if d.KeepLowest {
+			fmt.Fprintf(&builder, "kl%d", d.Keep)
+		} else {
+			fmt.Fprintf(&builder, "kh%d", d.Keep)
+		}
+	}
+
+	return builder.String()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenDice
+	tokenPercent
+	tokenPlus
+	tokenMinus
+	tokenExplode
+	tokenKeepHighest
+	tokenKeepLowest
+	tokenReroll
+)
+
+// symbolTokens maps the single character tokens to their kind.
+var symbolTokens = map[byte]tokenKind{
+	'd': tokenDice,
+	'%': tokenPercent,
+	'+': tokenPlus,
+	'-': tokenMinus,
+	'!': tokenExplode,
+	'r': tokenReroll,
+}
+
+type token struct {
+	kind  tokenKind
+	value int
+	text  string
+}
+
+// tokenize splits a dice expression into its tokens. It is case-insensitive
+// and doesn't allow whitespace, as everything after the first space is the threshold.
+func tokenize(input string) ([]token, error) {
+	input = strings.ToLower(input)
+	tokens := make([]token, 0, len(input))
+
+	for i := 0; i < len(input); {
+		char := input[i]
+
+		switch {
+		case char >= '0' && char <= '9':
+			start := i
+			for i < len(input) && input[i] >= '0' && input[i] <= '9' {
+				i++
+			}
+
+			if i-start > maxNumberDigits {
+				return nil, fmt.Errorf("number too large: %s", input[start:i])
+			}
+
+			value, _ := strconv.Atoi(input[start:i])
+			tokens = append(tokens, token{kind: tokenNumber, value: value, text: input[start:i]})
+
+			continue
+		case strings.HasPrefix(input[i:], "kh"):
+			tokens = append(tokens, token{kind: tokenKeepHighest, text: "kh"})
+			i += 2
+
+			continue
+		case strings.HasPrefix(input[i:], "kl"):
+			tokens = append(tokens, token{kind: tokenKeepLowest, text: "kl"})
+			i += 2
+
+			continue
+		}
+
+		kind, exists := symbolTokens[char]
+		if !exists {
+			return nil, fmt.Errorf("unexpected character %q in dice notation", char)
+		}
+
+		tokens = append(tokens, token{kind: kind, text: string(char)})
+		i++
+	}
+
+	return append(tokens, token{kind: tokenEOF, text: "end of input"}), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// parser is a recursive descent parser for the grammar
+//
+//	expression = term { ( "+" | "-" ) term }
+//	term       = number | [ number ] "d" ( number | "%" ) { modifier }
+//	modifier   = "!" | "r" number | ( "kh" | "kl" ) [ number ]
+//
+// The count may only be left out for d%, as "d20" is more likely a typo than a roll.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// ParseExpression parses and validates a dice expression. The safety limits
+// for the number of dice and their sides apply to the whole expression.
+func ParseExpression(input string) (Expression, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return Expression{}, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	expression, err := p.parseExpression()
+	if err != nil {
+		return Expression{}, err
+	}
+
+	if err := expression.validate(); err != nil {
+		return Expression{}, err
+	}
+
+	return expression, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) parseExpression() (Expression, error) {
+	var expression Expression
+
+	sign := 1
+
+	for {
+		term, err := p.parseTerm()
+		if err != nil {
+			return Expression{}, err
+		}
+
+		term.Sign = sign
+		expression.Terms = append(expression.Terms, term)
+
+		if len(expression.Terms) > maxTerms {
+			return Expression{}, fmt.Errorf("maximum number of terms is %d", maxTerms)
+		}
+
+		switch t := p.next(); t.kind {
+		case tokenEOF:
+			return expression, nil
+		case tokenPlus:
+			sign = 1
+		case tokenMinus:
+			sign = -1
+		default:
+			return Expression{}, fmt.Errorf("unexpected %s in dice notation", t.text)
+		}
+	}
+}
+
+func (p *parser) parseTerm() (Term, error) {
+	count := 0
+	hasCount := false
+
+	if p.peek().kind == tokenNumber {
+		count = p.next().value
+		hasCount = true
+
+		if p.peek().kind != tokenDice {
+			return Term{Constant: count}, nil
+		}
+	}
+
+	if p.next().kind != tokenDice {
+		return Term{}, errors.New("invalid dice notation! Use format: 2d20 or 2d20 15")
+	}
+
+	dice := &DiceTerm{Count: count}
+
+	switch t := p.next(); t.kind {
+	case tokenPercent:
+		dice.Sides = percentileSides
+		dice.Percentile = true
+
+		if !hasCount {
+			dice.Count = 1
+		}
+	case tokenNumber:
+		if !hasCount {
+			return Term{}, errors.New("dice count is missing, use 1d" + t.text)
+		}
+
+		dice.Sides = t.value
+	default:
+		return Term{}, errors.New("dice sides are missing")
+	}
+
+	if err := p.parseModifiers(dice); err != nil {
+		return Term{}, err
+	}
+
+	return Term{Dice: dice}, nil
+}
+
+func (p *parser) parseModifiers(dice *DiceTerm) error {
+	seen := make(map[tokenKind]bool)
+
+	for {
+		t := p.peek()
+
+		switch t.kind {
+		case tokenExplode, tokenReroll, tokenKeepHighest, tokenKeepLowest:
+		default:
+			return nil
+		}
+
+		p.next()
+
+		kind := t.kind
+		if kind == tokenKeepLowest {
+			kind = tokenKeepHighest
+		}
+
+		if seen[kind] {
+			return fmt.Errorf("modifier %s can only be used once per dice", t.text)
+		}
+
+		seen[kind] = true
+
+		switch t.kind {
+		case tokenExplode:
+			dice.Explode = true
+		case tokenReroll:
+			if p.peek().kind != tokenNumber {
+				return errors.New("reroll needs a value, like 4d6r1")
+			}
+
+			dice.Reroll = p.next().value
+			if dice.Reroll == 0 {
+				return errors.New("reroll value must be greater than 0")
+			}
+		case tokenKeepHighest, tokenKeepLowest:
+			dice.Keep = 1
+			dice.KeepLowest = t.kind == tokenKeepLowest
+
+			if p.peek().kind == tokenNumber {
+				dice.Keep = p.next().value
+			}
+
+			if dice.Keep == 0 {
+				return errors.New("number of kept dice must be greater than 0")
+			}
+		}
+	}
+}
+
+// validate checks the parsed expression against the safety limits.
+func (e Expression) validate() error {
+	hasDice := false
+
+	for _, term := range e.Terms {
+		hasDice = hasDice || term.Dice != nil
+	}
+
+	if !hasDice {
+		return errors.New("invalid dice notation! Use format: 2d20 or 2d20 15")
+	}
+
+	for _, term := range e.Terms {
+		if term.Dice == nil {
+			continue
+		}
+
+		dice := term.Dice
+
+		if dice.Count <= 0 {
+			return errors.New("dice count must be greater than 0")
+		}
+
+		if dice.Sides <= 1 {
+			return errors.New("dice sides must be greater than 1")
+		}
+
+		if dice.Sides > maxSides {
+			return fmt.Errorf("maximum dice sides is %d", maxSides)
+		}
+
+		if dice.Keep > dice.Count {
+			return fmt.Errorf("can only keep 1 to %d dice of %s", dice.Count, dice)
+		}
+
+		if dice.Reroll >= dice.Sides {
+			return fmt.Errorf("reroll value of %s must be lower than %d", dice, dice.Sides)
+		}
+	}
+
+	if e.DiceCount() > maxCount {
+		return fmt.Errorf("maximum dice count is %d", maxCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package roll_test
+
+import (
+	"testing"
+
+	"github.com/br0-space/bot/pkg/matchers/roll"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Expression Tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func dice(sign int, term roll.DiceTerm) roll.Term {
+	return roll.Term{Sign: sign, Dice: &term}
+}
+
+func constant(sign int, value int) roll.Term {
+	return roll.Term{Sign: sign, Constant: value}
+}
+
+var parseExpressionTests = []struct {
+	in       string
+	expected []roll.Term
+	notation string
+}{
+	{"2d20", []roll.Term{dice(1, roll.DiceTerm{Count: 2, Sides: 20})}, "2d20"},
+	{"2D6+3", []roll.Term{dice(1, roll.DiceTerm{Count: 2, Sides: 6}), constant(1, 3)}, "2d6+3"},
+	{"1d20+1d4-2", []roll.Term{
+		dice(1, roll.DiceTerm{Count: 1, Sides: 20}),
+		dice(1, roll.DiceTerm{Count: 1, Sides: 4}),
+		constant(-1, 2),
+	}, "1d20+1d4-2"},
+	{"4d6kh3", []roll.Term{dice(1, roll.DiceTerm{Count: 4, Sides: 6, Keep: 3})}, "4d6kh3"},
+	{"2d20kh", []roll.Term{dice(1, roll.DiceTerm{Count: 2, Sides: 20, Keep: 1})}, "2d20kh1"},
+	{"2d20kl1", []roll.Term{dice(1, roll.DiceTerm{Count: 2, Sides: 20, Keep: 1, KeepLowest: true})}, "2d20kl1"},
+	{"3d6!", []roll.Term{dice(1, roll.DiceTerm{Count: 3, Sides: 6, Explode: true})}, "3d6!"},
+	{"4d6r1", []roll.Term{dice(1, roll.DiceTerm{Count: 4, Sides: 6, Reroll: 1})}, "4d6r1"},
+	{"4d6kh3r1", []roll.Term{dice(1, roll.DiceTerm{Count: 4, Sides: 6, Keep: 3, Reroll: 1})}, "4d6r1kh3"},
+	{"d%", []roll.Term{dice(1, roll.DiceTerm{Count: 1, Sides: 100, Percentile: true})}, "d%"},
+	{"2d%+10", []roll.Term{dice(1, roll.DiceTerm{Count: 2, Sides: 100, Percentile: true}), constant(1, 10)}, "2d%+10"},
+	{"5-1d4", []roll.Term{constant(1, 5), dice(-1, roll.DiceTerm{Count: 1, Sides: 4})}, "5-1d4"},
+}
+
+func TestParseExpression(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range parseExpressionTests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := roll.ParseExpression(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expression.Terms)
+			assert.Equal(t, tt.notation, expression.String())
+		})
+	}
+}
+
+var parseExpressionErrorTests = []struct {
+	in       string
+	expected string
+}{
+	{"", "invalid dice notation"},
+	{"abc", "unexpected character"},
+	{"d20", "dice count is missing"},
+	{"2d", "dice sides are missing"},
+	{"2d20d6", "unexpected d"},
+	{"-2d6", "invalid dice notation"},
+	{"2d6+", "invalid dice notation"},
+	{"2d6++3", "invalid dice notation"},
+	{"5+3", "invalid dice notation"},
+	{"0d6", "dice count must be greater than 0"},
+	{"2d1", "dice sides must be greater than 1"},
+	{"2d1001", "maximum dice sides is 1000"},
+	{"60d6+50d6", "maximum dice count is 100"},
+	{"4d6kh5", "can only keep 1 to 4 dice"},
+	{"4d6kh0", "number of kept dice must be greater than 0"},
+	{"4d6kh3kl1", "can only be used once"},
+	{"3d6!!", "can only be used once"},
+	{"4d6r", "reroll needs a value"},
+	{"4d6r0", "reroll value must be greater than 0"},
+	{"4d6r6", "must be lower than 6"},
+	{"1d20+9999999", "number too large"},
+	{"1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4+1d4", "maximum number of terms"},
+}
+
+func TestParseExpression_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range parseExpressionErrorTests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := roll.ParseExpression(tt.in)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func FuzzParseExpression(f *testing.F) {
+	for _, tt := range parseExpressionTests {
+		f.Add(tt.in)
+	}
+
+	for _, tt := range parseExpressionErrorTests {
+		f.Add(tt.in)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		expression, err := roll.ParseExpression(input)
+		if err != nil {
+			return
+		}
+
+		// Valid expressions stay within the limits and can be rolled
+		assert.LessOrEqual(t, expression.DiceCount(), 100)
+		assert.LessOrEqual(t, expression.MaxSides(), 1000)
+
+		dice := roll.NewExpressionRoll(expression, nil, false)
+		assert.NotEmpty(t, dice.Roll())
+
+		// The normalized notation parses to the same expression
+		reparsed, err := roll.ParseExpression(expression.String())
+		require.NoError(t, err)
+		assert.Equal(t, expression, reparsed)
+	})
+}
@@ -0,0 +1,256 @@
+package roll
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const (
+	// minExpectedPerFace is the number of rolls per face the chi-square test needs to be meaningful.
+	minExpectedPerFace = 5
+	maxHistogramSides  = 20
+	histogramWidth     = 10
+	suspiciousPValue   = 0.05
+	cursedPValue       = 0.01
+	gammaIterations    = 500
+	gammaEpsilon       = 1e-12
+	gammaTiny          = 1e-300
+)
+
+var diePattern = regexp.MustCompile(`^d(\d+|%)$`)
+
+// processFairness handles "/roll fairness [@user] [d20]". It compares the faces
+// rolled per die size with a fair die using a chi-square goodness-of-fit test.
+//
+//nolint:cyclop // Argument parsing and both output formats belong together.
+func (m Matcher) processFairness(messageIn telegramclient.WebhookMessageStruct, args []string) ([]telegramclient.MessageStruct, error) {
+	var (
+		userID   int64
+		username string
+		sides    int
+	)
+
+	for _, arg := range args {
+		if match := diePattern.FindStringSubmatch(strings.ToLower(arg)); match != nil {
+			sides = percentileSides
+			if match[1] != "%" {
+				sides, _ = strconv.Atoi(match[1])
+			}
+
+			continue
+		}
+
+		username = strings.TrimPrefix(arg, "@")
+
+		userID = m.findUserIDByUsername(messageIn.Chat.ID, username)
+		if userID == 0 {
+			return m.makeReply("❌ User not found: "+telegramclient.EscapeMarkdown(username), messageIn.ID)
+		}
+	}
+
+	faces, err := m.repo.GetFaceCounts(messageIn.Chat.ID, userID)
+	if err != nil {
+		return m.makeReply("❌ Error retrieving statistics\\.", messageIn.ID)
+	}
+
+	header := "⚖️ *Dice Fairness*"
+	if username != "" {
+		header += " for @" + telegramclient.EscapeMarkdown(username)
+	}
+
+	if sides == 0 {
+		if len(faces) == 0 {
+			return m.makeReply("No roll data available yet\\. Start rolling\\!", messageIn.ID)
+		}
+
+		parts := []string{header, ""}
+		for _, die := range faces {
+			parts = append(parts, formatFairnessSummary(die))
+		}
+
+		return m.makeReply(strings.Join(parts, "\n"), messageIn.ID)
+	}
+
+	for _, die := range faces {
+		if die.Sides == sides {
+			return m.makeReply(header+"\n\n"+formatFairnessDetails(die), messageIn.ID)
+		}
+	}
+
+	return m.makeReply(fmt.Sprintf("No d%d rolls found\\.", sides), messageIn.ID)
+}
+
+// formatFairnessSummary formats the test result of a die size in a single line.
+func formatFairnessSummary(die interfaces.RollFacesStruct) string {
+	total := sumCounts(die.Counts)
+
+	if needed := minExpectedPerFace * die.Sides; total < needed {
+		return fmt.Sprintf("d%d: %s faces, not enough rolls \\(needs %s\\)", die.Sides, formatNumber(total), formatNumber(needed))
+	}
+
+	statistic, pValue := ChiSquare(die.Counts)
+
+	return fmt.Sprintf(
+		"d%d: %s faces, χ² %s, p %s %s",
+		die.Sides,
+		formatNumber(total),
+		telegramclient.EscapeMarkdown(fmt.Sprintf("= %.1f", statistic)),
+		telegramclient.EscapeMarkdown(fmt.Sprintf("= %.3f", pValue)),
+		verdictEmoji(pValue),
+	)
+}
+
+// formatFairnessDetails formats the distribution of a die size with the test result.
+func formatFairnessDetails(die interfaces.RollFacesStruct) string {
+	total := sumCounts(die.Counts)
+	parts := []string{fmt.Sprintf("*d%d*: %s faces", die.Sides, formatNumber(total))}
+
+	if die.Sides <= maxHistogramSides {
+		highest := 0
+		for _, count := range die.Counts {
+			highest = max(highest, count)
+		}
+
+		for face, count := range die.Counts {
+			bar := ""
+			if highest > 0 {
+				bar = strings.Repeat("█", int(math.Round(float64(count)*histogramWidth/float64(highest))))
+			}
+
+			parts = append(parts, fmt.Sprintf("%2d: %s %d", face+1, bar, count))
+		}
+	}
+
+	parts = append(parts, "")
+
+	if needed := minExpectedPerFace * die.Sides; total < needed {
+		parts = append(parts, fmt.Sprintf("Not enough rolls for a verdict, the test needs at least %s\\.", formatNumber(needed)))
+
+		return strings.Join(parts, "\n")
+	}
+
+	statistic, pValue := ChiSquare(die.Counts)
+
+	parts = append(parts, telegramclient.EscapeMarkdown(fmt.Sprintf(
+		"χ² = %.2f with %d degrees of freedom, p = %.3f",
+		statistic,
+		die.Sides-1,
+		pValue,
+	)))
+
+	switch {
+	case pValue < cursedPValue:
+		parts = append(parts, "🚨 *Cursed\\!* A fair die rolls like this in less than 1% of cases\\.")
+	case pValue < suspiciousPValue:
+		parts = append(parts, "🤨 *Suspicious\\.* A fair die rolls like this in less than 5% of cases\\.")
+	default:
+		parts = append(parts, "✅ *Looks fair\\.* Blame your luck, not your dice\\.")
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+func verdictEmoji(pValue float64) string {
+	switch {
+	case pValue < cursedPValue:
+		return "🚨"
+	case pValue < suspiciousPValue:
+		return "🤨"
+	default:
+		return "✅"
+	}
+}
+
+func sumCounts(counts []int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	return total
+}
+
+// ChiSquare tests the observed counts of the faces of a die against a fair die.
+// It returns the chi-square statistic and the p-value, the probability that a
+// fair die deviates at least as much.
+func ChiSquare(counts []int) (float64, float64) {
+	total := sumCounts(counts)
+	if total == 0 || len(counts) < 2 {
+		return 0, 1
+	}
+
+	expected := float64(total) / float64(len(counts))
+	statistic := 0.0
+
+	for _, count := range counts {
+		deviation := float64(count) - expected
+		statistic += deviation * deviation / expected
+	}
+
+	return statistic, regularizedGammaQ(float64(len(counts)-1)/2, statistic/2)
+}
+
+// regularizedGammaQ computes the upper regularized incomplete gamma function
+// Q(a, x), which is the survival function of the chi-square distribution with
+// 2a degrees of freedom at 2x. It uses the series expansion for small x and the
+// continued fraction otherwise.
+func regularizedGammaQ(a float64, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		sum := 1 / a
+		term := sum
+
+		for n := 1; n < gammaIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+
+			if math.Abs(term) < math.Abs(sum)*gammaEpsilon {
+				break
+			}
+		}
+
+		return math.Max(0, 1-sum*prefix)
+	}
+
+	b := x + 1 - a
+	c := 1 / gammaTiny
+	d := 1 / b
+	h := d
+
+	for i := 1; i < gammaIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+
+		d = an*d + b
+		if math.Abs(d) < gammaTiny {
+			d = gammaTiny
+		}
+
+		c = b + an/c
+		if math.Abs(c) < gammaTiny {
+			c = gammaTiny
+		}
+
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < gammaEpsilon {
+			break
+		}
+	}
+
+	return prefix * h
+}
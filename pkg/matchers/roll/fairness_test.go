@@ -0,0 +1,94 @@
+package roll_test
+
+import (
+	"math"
+	"testing"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/roll"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Fairness Tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func TestChiSquare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		counts    []int
+		statistic float64
+		pValue    float64
+	}{
+		{"no rolls", []int{0, 0, 0, 0, 0, 0}, 0, 1},
+		{"perfectly even", []int{10, 10, 10, 10, 10, 10}, 0, 1},
+		// With one degree of freedom p = erfc(sqrt(statistic / 2))
+		{"coin, small deviation", []int{52, 48}, 0.16, math.Erfc(math.Sqrt(0.08))},
+		{"coin, large deviation", []int{60, 40}, 4, math.Erfc(math.Sqrt2)},
+		// With two degrees of freedom p = exp(-statistic / 2)
+		{"d3", []int{20, 10, 30}, 10, math.Exp(-5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			statistic, pValue := roll.ChiSquare(tt.counts)
+			assert.InDelta(t, tt.statistic, statistic, 1e-9)
+			assert.InDelta(t, tt.pValue, pValue, 1e-9)
+		})
+	}
+}
+
+func TestMatcher_Process_Fairness(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetFaceCounts", testChatID, int64(0)).Return([]interfaces.RollFacesStruct{
+		{Sides: 6, Counts: []int{10, 10, 10, 10, 10, 10}},
+		{Sides: 20, Counts: make([]int, 20)},
+	}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll fairness"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(
+		t,
+		"⚖️ *Dice Fairness*\n\nd6: 60 faces, χ² \\= 0\\.0, p \\= 1\\.000 ✅\nd20: 0 faces, not enough rolls \\(needs 100\\)",
+		replies[0].Text,
+	)
+
+	replies, err = matcher.Process(newTestMessage("/roll fairness D6"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "*d6*: 60 faces\n 1: ██████████ 10\n")
+	assert.Contains(t, replies[0].Text, "χ² \\= 0\\.00 with 5 degrees of freedom, p \\= 1\\.000")
+	assert.Contains(t, replies[0].Text, "Looks fair")
+
+	replies, err = matcher.Process(newTestMessage("/roll fairness d8"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "No d8 rolls found\\.", replies[0].Text)
+}
+
+func TestMatcher_Process_FairnessOfUser(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetUserIDByUsername", testChatID, "alice").Return(int64(42), nil)
+	mockRepo.On("GetFaceCounts", testChatID, int64(42)).Return([]interfaces.RollFacesStruct{
+		{Sides: 2, Counts: []int{80, 20}},
+	}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll fairness d2 @alice"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "⚖️ *Dice Fairness* for @alice")
+	assert.Contains(t, replies[0].Text, "Cursed")
+}
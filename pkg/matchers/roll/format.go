@@ -18,31 +18,36 @@ const (
 )
 
 // formatRollResponse creates a formatted response message for a dice roll.
-func formatRollResponse(roll *DiceRoll) string {
+func formatRollResponse(roll *DiceRoll, cfg Config) string {
 	var parts []string
 
+	notation := telegramclient.EscapeMarkdown(roll.GetExpression().String())
+
 	// Header
 	if roll.GetKeepHighest() {
-		parts = append(parts, fmt.Sprintf("🎲 *Roll: %dd%d \\(Advantage\\)*", roll.GetCount(), roll.GetSides()))
+		parts = append(parts, fmt.Sprintf("🎲 *Roll: %s \\(Advantage\\)*", notation))
 	} else {
-		parts = append(parts, fmt.Sprintf("🎲 *Roll: %dd%d*", roll.GetCount(), roll.GetSides()))
+		parts = append(parts, fmt.Sprintf("🎲 *Roll: %s*", notation))
 	}
 
-	// Results
-	results := roll.GetResults()
+	// Results, broken down by term if there is more than a single group of dice
+	terms := roll.GetTerms()
 
-	resultStrs := make([]string, len(results))
-	for i, r := range results {
-		resultStrs[i] = strconv.Itoa(r)
-	}
+	if len(terms) == 1 {
+		parts = append(parts, "Results: "+formatDice(terms[0].Dice))
+	} else {
+		parts = append(parts, "Results:")
 
-	parts = append(parts, "Results: "+strings.Join(resultStrs, ", "))
+		for i, term := range terms {
+			parts = append(parts, formatTerm(term, i == 0))
+		}
+	}
 
 	// Total or Highest
 	if roll.GetKeepHighest() {
 		parts = append(parts, fmt.Sprintf("Highest: *%d*", roll.GetHighest()))
 	} else {
-		parts = append(parts, fmt.Sprintf("Total: *%d*", roll.Sum()))
+		parts = append(parts, "Total: *"+telegramclient.EscapeMarkdown(strconv.Itoa(roll.Sum()))+"*")
 	}
 
 	// Success/Failure
@@ -57,42 +62,96 @@ func formatRollResponse(roll *DiceRoll) string {
 	// Critical hit/failure messages
 	if roll.IsCriticalHit() {
 		parts = append(parts, "✨ *CRITICAL HIT\\!* ✨")
-		parts = append(parts, "🎉 "+telegramclient.EscapeMarkdown(getRandomCriticalHit()))
+		parts = append(parts, "🎉 "+telegramclient.EscapeMarkdown(randomMessage(cfg.CriticalHits(roll.GetKeptSides()), "Critical hit!")))
 	} else if roll.IsCriticalFailure() {
 		parts = append(parts, "💀 *CRITICAL FAILURE\\!* 💀")
-		parts = append(parts, "😂 "+telegramclient.EscapeMarkdown(getRandomCriticalFailure()))
+		parts = append(parts, "😂 "+telegramclient.EscapeMarkdown(randomMessage(cfg.CriticalFailures(roll.GetKeptSides()), "Critical failure!")))
 	}
 
 	return strings.Join(parts, "\n")
 }
 
-// getRandomCriticalHit returns a random critical hit message.
-func getRandomCriticalHit() string {
-	if len(criticalHitMessages) == 0 {
-		return "Critical hit!"
+// formatTerm formats a line of the breakdown, like "+1d4: 3 (3)" or "-2".
+func formatTerm(term TermResult, first bool) string {
+	sign := "+"
+	if term.Term.Sign < 0 {
+		sign = "-"
+	} else if first {
+		sign = ""
 	}
 
-	n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(criticalHitMessages))))
+	if term.Term.Dice == nil {
+		return telegramclient.EscapeMarkdown(sign + strconv.Itoa(term.Term.Constant))
+	}
 
-	return criticalHitMessages[int(n.Int64())]
+	return fmt.Sprintf(
+		"%s: %s \\(%s\\)",
+		telegramclient.EscapeMarkdown(sign+term.Term.Dice.String()),
+		formatDice(term.Dice),
+		telegramclient.EscapeMarkdown(strconv.Itoa(term.Total)),
+	)
 }
 
-// getRandomCriticalFailure returns a random critical failure message.
-func getRandomCriticalFailure() string {
-	if len(criticalFailureMessages) == 0 {
-		return "Critical failure!"
+// formatDice formats the dice of a term. Rerolled and dropped values are struck
+// through, dice that exploded are marked with an exclamation mark.
+func formatDice(dice []DieResult) string {
+	results := make([]string, len(dice))
+
+	for i, die := range dice {
+		result := strconv.Itoa(die.Value)
+
+		if die.Exploded {
+			result += "\\!"
+		}
+
+		if die.Dropped {
+			result = "~" + result + "~"
+		}
+
+		for j := len(die.Rerolled) - 1; j >= 0; j-- {
+			result = fmt.Sprintf("~%d~ %s", die.Rerolled[j], result)
+		}
+
+		results[i] = result
 	}
 
-	n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(criticalFailureMessages))))
+	return strings.Join(results, ", ")
+}
+
+// randomMessage returns a random message, or the fallback if there are none.
+func randomMessage(messages []string, fallback string) string {
+	if len(messages) == 0 {
+		return fallback
+	}
 
-	return criticalFailureMessages[int(n.Int64())]
+	n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(messages))))
+
+	return messages[int(n.Int64())]
+}
+
+// periodTitles are the titles of the stats of a time period.
+var periodTitles = map[string]string{
+	statsTypeWeek:    "Last 7 Days",
+	statsTypeMonth:   "Last 30 Days",
+	statsTypeSession: "Current Session",
 }
 
 // formatStatsResponse creates a formatted response message for roll statistics.
 //
 //nolint:cyclop,funlen
-func formatStatsResponse(stats *interfaces.RollStatsStruct, topRollers []interfaces.RollStatsStruct, statsType string) string {
+func formatStatsResponse(
+	stats *interfaces.RollStatsStruct,
+	topRollers []interfaces.RollStatsStruct,
+	streaks []interfaces.RollStreakStruct,
+	statsType string,
+) string {
+	title, isPeriod := periodTitles[statsType]
+
 	if stats == nil || stats.TotalRolls == 0 {
+		if isPeriod {
+			return fmt.Sprintf("No rolls in the %s yet\\.", strings.ToLower(title))
+		}
+
 		if statsType == statsTypeUser {
 			return "No roll data found for this user\\."
 		}
@@ -122,6 +181,8 @@ func formatStatsResponse(stats *interfaces.RollStatsStruct, topRollers []interfa
 		parts = append(parts, "💀 *Unluckiest Roller*")
 		parts = append(parts, "")
 		parts = append(parts, "@"+telegramclient.EscapeMarkdown(stats.Username))
+	case statsTypeWeek, statsTypeMonth, statsTypeSession:
+		parts = append(parts, fmt.Sprintf("📊 *Roll Statistics: %s*", title))
 	default:
 		parts = append(parts, "📊 *Roll Statistics*")
 	}
@@ -161,8 +222,13 @@ func formatStatsResponse(stats *interfaces.RollStatsStruct, topRollers []interfa
 		parts = append(parts, fmt.Sprintf("Success Rate: %.1f%%", stats.SuccessRate))
 	}
 
-	// Top rollers for overall stats
-	if statsType == statsTypeOverall && len(topRollers) > 0 {
+	// Streaks of the user
+	if statsType == statsTypeUser && len(streaks) > 0 {
+		parts = append(parts, formatUserStreaks(streaks[0])...)
+	}
+
+	// Top rollers and records for overall stats
+	if (statsType == statsTypeOverall || isPeriod) && len(topRollers) > 0 {
 		parts = append(parts, "")
 
 		parts = append(parts, "🏆 *Top Rollers:*")
@@ -174,6 +240,10 @@ func formatStatsResponse(stats *interfaces.RollStatsStruct, topRollers []interfa
 		}
 	}
 
+	if statsType == statsTypeOverall || isPeriod {
+		parts = append(parts, formatRecords(streaks)...)
+	}
+
 	// Footer message for lucky/unlucky
 	switch statsType {
 	case statsTypeLucky:
@@ -187,6 +257,94 @@ func formatStatsResponse(stats *interfaces.RollStatsStruct, topRollers []interfa
 	return strings.Join(parts, "\n")
 }
 
+// formatUserStreaks formats the longest streaks and the biggest swing of a user.
+func formatUserStreaks(streak interfaces.RollStreakStruct) []string {
+	parts := []string{""}
+
+	if streak.SuccessStreak > 0 {
+		parts = append(parts, fmt.Sprintf("Longest Success Streak: %d", streak.SuccessStreak))
+	}
+
+	if streak.FailureStreak > 0 {
+		parts = append(parts, fmt.Sprintf("Longest Failure Streak: %d", streak.FailureStreak))
+	}
+
+	if streak.CriticalStreak > 0 {
+		parts = append(parts, fmt.Sprintf("Most Consecutive Crits: %d", streak.CriticalStreak))
+	}
+
+	if swing := formatSwing(streak); swing != "" {
+		parts = append(parts, "Biggest Swing: "+swing)
+	}
+
+	if len(parts) == 1 {
+		return nil
+	}
+
+	return parts
+}
+
+// formatRecords formats the record holders of the longest streaks and the biggest swing.
+func formatRecords(streaks []interfaces.RollStreakStruct) []string {
+	records := []struct {
+		title  string
+		value  func(streak interfaces.RollStreakStruct) int
+		format func(streak interfaces.RollStreakStruct) string
+	}{
+		{"Longest Success Streak", func(streak interfaces.RollStreakStruct) int { return streak.SuccessStreak }, nil},
+		{"Longest Failure Streak", func(streak interfaces.RollStreakStruct) int { return streak.FailureStreak }, nil},
+		{"Most Consecutive Crits", func(streak interfaces.RollStreakStruct) int { return streak.CriticalStreak }, nil},
+		{"Biggest Swing", swing, formatSwing},
+	}
+
+	var parts []string
+
+	for _, record := range records {
+		var holder *interfaces.RollStreakStruct
+
+		for i := range streaks {
+			if record.value(streaks[i]) > 0 && (holder == nil || record.value(streaks[i]) > record.value(*holder)) {
+				holder = &streaks[i]
+			}
+		}
+
+		if holder == nil {
+			continue
+		}
+
+		value := strconv.Itoa(record.value(*holder))
+		if record.format != nil {
+			value = record.format(*holder)
+		}
+
+		parts = append(parts, fmt.Sprintf("%s: @%s \\- %s", record.title, telegramclient.EscapeMarkdown(holder.Username), value))
+	}
+
+	if len(parts) == 0 {
+		return nil
+	}
+
+	return append([]string{"", "🏅 *Records:*"}, parts...)
+}
+
+// swing returns the difference between the totals of the biggest swing.
+func swing(streak interfaces.RollStreakStruct) int {
+	if streak.SwingTo < streak.SwingFrom {
+		return streak.SwingFrom - streak.SwingTo
+	}
+
+	return streak.SwingTo - streak.SwingFrom
+}
+
+// formatSwing formats the biggest swing like "19 (1 → 20)", or returns an empty string if there is none.
+func formatSwing(streak interfaces.RollStreakStruct) string {
+	if swing(streak) == 0 {
+		return ""
+	}
+
+	return telegramclient.EscapeMarkdown(fmt.Sprintf("%d (%d → %d)", swing(streak), streak.SwingFrom, streak.SwingTo))
+}
+
 // formatNumber formats a number with thousands separator.
 func formatNumber(n int) string {
 	s := strconv.Itoa(n)
@@ -3,7 +3,9 @@ package roll_test
 import (
 	"strings"
 	"testing"
+	"time"
 
+	telegramclient "github.com/br0-space/bot-telegramclient"
 	"github.com/br0-space/bot/interfaces"
 	"github.com/br0-space/bot/pkg/matchers/roll"
 	"github.com/stretchr/testify/assert"
@@ -20,7 +22,7 @@ func TestFormatRollResponse_Basic(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll 2d20"))
 	require.NoError(t, err)
@@ -40,7 +42,7 @@ func TestFormatRollResponse_WithAdvantage(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll 2d20 15 kh"))
 	require.NoError(t, err)
@@ -60,7 +62,7 @@ func TestFormatRollResponse_WithSuccess(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	// Run multiple times to get both success and failure
 	foundSuccess := false
@@ -96,11 +98,12 @@ func TestFormatStatsResponse_NoData(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetOverallStats").Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetOverallStats", testChatID).Return(&interfaces.RollStatsStruct{
 		TotalRolls: 0,
 	}, nil)
-	mockRepo.On("GetTopRollers", 5).Return([]interfaces.RollStatsStruct{}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	mockRepo.On("GetTopRollers", testChatID, 5).Return([]interfaces.RollStatsStruct{}, nil)
+	mockRepo.On("GetStreaks", testChatID, time.Time{}).Return([]interfaces.RollStreakStruct{}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats"))
 	require.NoError(t, err)
@@ -114,7 +117,7 @@ func TestFormatStatsResponse_Overall(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetOverallStats").Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetOverallStats", testChatID).Return(&interfaces.RollStatsStruct{
 		TotalRolls:       1234,
 		TotalDice:        5678,
 		AverageRoll:      10.5,
@@ -124,12 +127,13 @@ func TestFormatStatsResponse_Overall(t *testing.T) {
 		CriticalFailures: 8,
 		SuccessRate:      67.5,
 	}, nil)
-	mockRepo.On("GetTopRollers", 5).Return([]interfaces.RollStatsStruct{
+	mockRepo.On("GetTopRollers", testChatID, 5).Return([]interfaces.RollStatsStruct{
 		{Username: "user1", TotalRolls: 500},
 		{Username: "user2", TotalRolls: 300},
 		{Username: "user3", TotalRolls: 200},
 	}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	mockRepo.On("GetStreaks", testChatID, time.Time{}).Return([]interfaces.RollStreakStruct{}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats"))
 	require.NoError(t, err)
@@ -160,8 +164,8 @@ func TestFormatStatsResponse_User(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetUserIDByUsername", "testuser").Return(int64(123), nil)
-	mockRepo.On("GetUserStats", int64(123)).Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetUserIDByUsername", testChatID, "testuser").Return(int64(123), nil)
+	mockRepo.On("GetUserStats", testChatID, int64(123)).Return(&interfaces.RollStatsStruct{
 		Username:         "testuser",
 		TotalRolls:       50,
 		TotalDice:        125,
@@ -172,7 +176,8 @@ func TestFormatStatsResponse_User(t *testing.T) {
 		CriticalFailures: 1,
 		SuccessRate:      72.5,
 	}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	mockRepo.On("GetStreaks", testChatID, time.Time{}).Return([]interfaces.RollStreakStruct{}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats testuser"))
 	require.NoError(t, err)
@@ -192,12 +197,12 @@ func TestFormatStatsResponse_Lucky(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetLuckiestRoller").Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetLuckiestRoller", testChatID).Return(&interfaces.RollStatsStruct{
 		Username:    "luckyuser",
 		TotalRolls:  75,
 		AverageRoll: 15.8,
 	}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats lucky"))
 	require.NoError(t, err)
@@ -217,12 +222,12 @@ func TestFormatStatsResponse_Unlucky(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetUnluckiestRoller").Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetUnluckiestRoller", testChatID).Return(&interfaces.RollStatsStruct{
 		Username:    "unluckyuser",
 		TotalRolls:  80,
 		AverageRoll: 4.2,
 	}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats unlucky"))
 	require.NoError(t, err)
@@ -260,12 +265,13 @@ func TestFormatNumber_Variations(t *testing.T) {
 
 			mockRepo := new(MockRollRepo)
 			mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-			mockRepo.On("GetOverallStats").Return(&interfaces.RollStatsStruct{
+			mockRepo.On("GetOverallStats", testChatID).Return(&interfaces.RollStatsStruct{
 				TotalRolls: tt.rolls,
 				TotalDice:  tt.rolls * 2,
 			}, nil)
-			mockRepo.On("GetTopRollers", 5).Return([]interfaces.RollStatsStruct{}, nil)
-			matcher := roll.MakeMatcher(mockRepo)
+			mockRepo.On("GetTopRollers", testChatID, 5).Return([]interfaces.RollStatsStruct{}, nil)
+			mockRepo.On("GetStreaks", testChatID, time.Time{}).Return([]interfaces.RollStreakStruct{}, nil)
+			matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 			replies, err := matcher.Process(newTestMessage("/roll stats"))
 			require.NoError(t, err)
@@ -275,3 +281,39 @@ func TestFormatNumber_Variations(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatRollResponse_Breakdown(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll 1d20+1D4-2"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+
+	text := replies[0].Text
+
+	assert.Contains(t, text, `🎲 *Roll: 1d20\+1d4\-2*`)
+	assert.Regexp(t, `\n1d20: \d+ \\\(\d+\\\)\n`, text)
+	assert.Regexp(t, `\n\\\+1d4: \d \\\(\d\\\)\n`, text)
+	assert.Contains(t, text, "\n\\-2\n")
+	assert.Contains(t, text, "Total:")
+}
+
+func TestFormatRollResponse_DroppedDice(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll 4d6kh3"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+
+	// The lowest die is struck through
+	assert.Regexp(t, `Results: (~\d~|\d)(, (~\d~|\d)){3}\n`, replies[0].Text)
+	assert.Equal(t, 1, strings.Count(replies[0].Text, "~")/2)
+}
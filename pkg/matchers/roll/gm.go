@@ -0,0 +1,33 @@
+package roll
+
+import (
+	telegramclient "github.com/br0-space/bot-telegramclient"
+)
+
+// groll is the command for secret rolls, an alias for "/roll gm".
+const groll = "groll"
+
+// processSecretRoll handles "/roll gm <dice>" and "/groll <dice>". The result is
+// sent to the caller by private message, the chat only learns that the GM rolled.
+func (m Matcher) processSecretRoll(messageIn telegramclient.WebhookMessageStruct, args string) ([]telegramclient.MessageStruct, error) {
+	expression, threshold, keepHighest, err := m.parseRoll(messageIn.From.ID, args)
+	if err != nil {
+		return m.makeReply("❌ "+telegramclient.EscapeMarkdown(err.Error()), messageIn.ID)
+	}
+
+	roll := NewExpressionRoll(expression, threshold, keepHighest)
+	roll.Roll()
+
+	// The private chat with a user has the ID of the user
+	response := "🤫 *Secret roll*\n" + formatRollResponse(roll, m.Config())
+	if err := m.client.SendMessage(messageIn.From.ID, telegramclient.MarkdownMessage(response)); err != nil {
+		return m.makeReply("❌ I can't send you private messages\\. Start a private chat with me first\\.", messageIn.ID)
+	}
+
+	if err := m.saveRoll(messageIn.Chat.ID, messageIn.From.ID, roll, true); err != nil {
+		// The GM already has the result, so don't fail the response
+		m.log.Error("Unable to save secret roll:", err)
+	}
+
+	return m.makeReply("🤫 The GM rolled secretly\\.", messageIn.ID)
+}
@@ -0,0 +1,112 @@
+package roll_test
+
+import (
+	"errors"
+	"testing"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/roll"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeClient records the messages sent outside of the replies.
+type fakeClient struct {
+	chatIDs  []int64
+	messages []telegramclient.MessageStruct
+	err      error
+}
+
+func (c *fakeClient) SendMessage(chatID int64, message telegramclient.MessageStruct) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	c.chatIDs = append(c.chatIDs, chatID)
+	c.messages = append(c.messages, message)
+
+	return nil
+}
+
+func TestMatcher_Process_SecretRoll(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{"/roll gm 1d20+5", "/groll 1d20+5", "/GROLL@bot 1d20+5"} {
+		t.Run(in, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockRollRepo)
+			mockRepo.On("SaveRoll", mock.MatchedBy(func(r *interfaces.Roll) bool {
+				return r.Hidden && r.ChatID == testChatID && r.UserID == testUserID && r.Notation == "1d20+5"
+			})).Return(nil)
+
+			client := &fakeClient{}
+			matcher := roll.MakeMatcher(mockRepo, client)
+
+			replies, err := matcher.Process(newTestMessage(in))
+			require.NoError(t, err)
+			require.Len(t, replies, 1)
+			assert.Equal(t, "🤫 The GM rolled secretly\\.", replies[0].Text)
+
+			// The breakdown goes to the private chat of the caller
+			require.Len(t, client.messages, 1)
+			assert.Equal(t, []int64{testUserID}, client.chatIDs)
+			assert.Contains(t, client.messages[0].Text, "Secret roll")
+			assert.Contains(t, client.messages[0].Text, "1d20\\+5")
+			assert.Contains(t, client.messages[0].Text, "Total:")
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMatcher_Process_SecretRoll_SendFails(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	matcher := roll.MakeMatcher(mockRepo, &fakeClient{err: errors.New("Forbidden: bot can't initiate conversation with a user")})
+
+	replies, err := matcher.Process(newTestMessage("/groll 1d20"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "Start a private chat with me first")
+
+	// A roll the GM never saw isn't saved
+	mockRepo.AssertNotCalled(t, "SaveRoll", mock.Anything)
+}
+
+func TestMatcher_Process_SecretRoll_Invalid(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetMacro", testUserID, "attack").Return(nil, gorm.ErrRecordNotFound)
+
+	client := &fakeClient{}
+	matcher := roll.MakeMatcher(mockRepo, client)
+
+	replies, err := matcher.Process(newTestMessage("/roll gm attack"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "❌")
+	assert.Empty(t, client.messages)
+}
+
+func TestMatcher_Process_SecretRoll_SaveFails(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("SaveRoll", mock.Anything).Return(errors.New("database is locked"))
+
+	client := &fakeClient{}
+	matcher := roll.MakeMatcher(mockRepo, client)
+
+	// The GM already has the result, so the failure is only logged
+	replies, err := matcher.Process(newTestMessage("/groll 1d20"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "🤫 The GM rolled secretly\\.", replies[0].Text)
+	assert.Len(t, client.messages, 1)
+	mockRepo.AssertExpectations(t)
+}
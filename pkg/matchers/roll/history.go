@@ -0,0 +1,104 @@
+package roll
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const (
+	defaultHistoryLimit = 10
+	maxHistoryLimit     = 50
+	historyTimeFormat   = "Jan 02 15:04"
+)
+
+// processHistory handles "/roll history [@user] [n]" and lists the latest rolls.
+func (m Matcher) processHistory(messageIn telegramclient.WebhookMessageStruct, args []string) ([]telegramclient.MessageStruct, error) {
+	var userID int64
+
+	limit := defaultHistoryLimit
+	header := "📜 *Recent Rolls*"
+
+	for _, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			if n < 1 || n > maxHistoryLimit {
+				return m.makeReply(fmt.Sprintf("❌ You can list 1 to %d rolls\\.", maxHistoryLimit), messageIn.ID)
+			}
+
+			limit = n
+
+			continue
+		}
+
+		username := strings.TrimPrefix(arg, "@")
+
+		userID = m.findUserIDByUsername(messageIn.Chat.ID, username)
+		if userID == 0 {
+			return m.makeReply("❌ User not found: "+telegramclient.EscapeMarkdown(username), messageIn.ID)
+		}
+
+		header = "📜 *Recent Rolls of @" + telegramclient.EscapeMarkdown(username) + "*"
+	}
+
+	rolls, err := m.repo.GetRecentRolls(messageIn.Chat.ID, userID, limit)
+	if err != nil {
+		return m.makeReply("❌ Error retrieving rolls\\.", messageIn.ID)
+	}
+
+	if len(rolls) == 0 {
+		return m.makeReply("No roll data available yet\\. Start rolling\\!", messageIn.ID)
+	}
+
+	parts := []string{header, ""}
+	for _, roll := range rolls {
+		parts = append(parts, formatHistoryEntry(roll, userID == 0))
+	}
+
+	return m.makeReply(strings.Join(parts, "\n"), messageIn.ID)
+}
+
+// formatHistoryEntry formats a roll as a single line, with the user if rolls of several users are listed.
+func formatHistoryEntry(roll interfaces.RollHistoryStruct, withUser bool) string {
+	notation := roll.Notation
+	if notation == "" {
+		// Rolls from before dice expressions are plain NdM rolls
+		notation = fmt.Sprintf("%dd%d", roll.DiceCount, roll.DiceSides)
+	}
+
+	line := telegramclient.EscapeMarkdown(roll.CreatedAt.Local().Format(historyTimeFormat)) + " "
+
+	if withUser {
+		username := roll.Username
+		if username == "" {
+			username = "#" + strconv.FormatInt(roll.UserID, 10)
+		}
+
+		line += telegramclient.EscapeMarkdown(username) + ": "
+	}
+
+	line += fmt.Sprintf(
+		"%s \\= *%s*",
+		telegramclient.EscapeMarkdown(notation),
+		telegramclient.EscapeMarkdown(strconv.Itoa(roll.Total)),
+	)
+
+	switch {
+	case roll.CriticalHit:
+		line += " ✨"
+	case roll.CriticalFailure:
+		line += " 💀"
+	}
+
+	if roll.Success != nil {
+		if *roll.Success {
+			line += " ✅"
+		} else {
+			line += " ❌"
+		}
+	}
+
+	return line
+}
@@ -0,0 +1,66 @@
+package roll_test
+
+import (
+	"testing"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/roll"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// History Tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func historyEntry(username string, roll interfaces.Roll) interfaces.RollHistoryStruct {
+	roll.Model = gorm.Model{CreatedAt: time.Date(2026, 3, 14, 20, 15, 0, 0, time.Local)}
+
+	return interfaces.RollHistoryStruct{Roll: roll, Username: username}
+}
+
+func TestMatcher_Process_History(t *testing.T) {
+	t.Parallel()
+
+	success := true
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetRecentRolls", testChatID, int64(0), 10).Return([]interfaces.RollHistoryStruct{
+		historyEntry("@alice", interfaces.Roll{Notation: "1d20+7", Total: 27, CriticalHit: true, Threshold: new(int), Success: &success}),
+		historyEntry("", interfaces.Roll{UserID: 42, DiceCount: 2, DiceSides: 6, Total: 2, CriticalFailure: true}),
+	}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll history"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(
+		t,
+		"📜 *Recent Rolls*\n\nMar 14 20:15 @alice: 1d20\\+7 \\= *27* ✨ ✅\nMar 14 20:15 \\#42: 2d6 \\= *2* 💀",
+		replies[0].Text,
+	)
+}
+
+func TestMatcher_Process_HistoryOfUser(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetUserIDByUsername", testChatID, "alice").Return(int64(42), nil)
+	mockRepo.On("GetRecentRolls", testChatID, int64(42), 3).Return([]interfaces.RollHistoryStruct{
+		historyEntry("@alice", interfaces.Roll{Notation: "d%", Total: -3}),
+	}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll history @alice 3"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "📜 *Recent Rolls of @alice*\n\nMar 14 20:15 d% \\= *\\-3*", replies[0].Text)
+
+	replies, err = matcher.Process(newTestMessage("/roll history 100"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "You can list 1 to 50 rolls")
+}
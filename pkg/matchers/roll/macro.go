@@ -0,0 +1,141 @@
+package roll
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"gorm.io/gorm"
+)
+
+const (
+	commandSave     = "save"
+	commandMacros   = "macros"
+	commandDelete   = "delete"
+	commandHistory  = "history"
+	commandFairness = "fairness"
+	commandGM       = "gm"
+)
+
+// macroNamePattern matches valid macro names. They start with a letter, so most
+// of them can't be confused with a dice expression.
+var macroNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// reservedMacroNames are the subcommands of /roll, which can't be used as macro names.
+var reservedMacroNames = []string{"stats", commandSave, commandMacros, commandDelete, commandHistory, commandFairness, commandGM}
+
+// processSaveMacro handles "/roll save <name> <dice> [threshold] [kh]".
+func (m Matcher) processSaveMacro(messageIn telegramclient.WebhookMessageStruct, args []string) ([]telegramclient.MessageStruct, error) {
+	if len(args) < 2 {
+		return m.makeReply("❌ Usage: /roll save attack 1d20\\+7 15", messageIn.ID)
+	}
+
+	name := strings.ToLower(args[0])
+	if err := validateMacroName(name); err != nil {
+		return m.makeReply("❌ "+telegramclient.EscapeMarkdown(err.Error()), messageIn.ID)
+	}
+
+	notation := strings.Join(args[1:], " ")
+	if _, _, _, err := parseDiceNotation(notation); err != nil {
+		return m.makeReply("❌ "+telegramclient.EscapeMarkdown(err.Error()), messageIn.ID)
+	}
+
+	if err := m.repo.SaveMacro(messageIn.From.ID, name, notation); err != nil {
+		return m.makeReply("❌ Error saving macro\\.", messageIn.ID)
+	}
+
+	return m.makeReply(fmt.Sprintf(
+		"💾 Saved macro *%s*: %s",
+		telegramclient.EscapeMarkdown(name),
+		telegramclient.EscapeMarkdown(notation),
+	), messageIn.ID)
+}
+
+// processMacros handles "/roll macros" and lists the macros of the user.
+func (m Matcher) processMacros(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	macros, err := m.repo.GetMacros(messageIn.From.ID)
+	if err != nil {
+		return m.makeReply("❌ Error retrieving macros\\.", messageIn.ID)
+	}
+
+	if len(macros) == 0 {
+		return m.makeReply("You have no macros yet\\. Save one with /roll save attack 1d20\\+7 15", messageIn.ID)
+	}
+
+	parts := []string{"📜 *Your macros:*"}
+	for _, macro := range macros {
+		parts = append(parts, fmt.Sprintf(
+			"%s: %s",
+			telegramclient.EscapeMarkdown(macro.Name),
+			telegramclient.EscapeMarkdown(macro.Notation),
+		))
+	}
+
+	return m.makeReply(strings.Join(parts, "\n"), messageIn.ID)
+}
+
+// processDeleteMacro handles "/roll delete <name>".
+func (m Matcher) processDeleteMacro(messageIn telegramclient.WebhookMessageStruct, args []string) ([]telegramclient.MessageStruct, error) {
+	if len(args) != 1 {
+		return m.makeReply("❌ Usage: /roll delete attack", messageIn.ID)
+	}
+
+	name := strings.ToLower(args[0])
+
+	deleted, err := m.repo.DeleteMacro(messageIn.From.ID, name)
+	if err != nil {
+		return m.makeReply("❌ Error deleting macro\\.", messageIn.ID)
+	}
+
+	if !deleted {
+		return m.makeReply("❌ Macro not found: "+telegramclient.EscapeMarkdown(name), messageIn.ID)
+	}
+
+	return m.makeReply("🗑 Deleted macro *"+telegramclient.EscapeMarkdown(name)+"*\\.", messageIn.ID)
+}
+
+// resolveMacro replaces a macro name at the start of the arguments with its notation.
+// It reports whether the arguments start with a macro of the user.
+func (m Matcher) resolveMacro(userID int64, args string) (string, bool, error) {
+	parts := strings.Fields(args)
+	if len(parts) == 0 {
+		return "", false, nil
+	}
+
+	name := strings.ToLower(parts[0])
+	if !macroNamePattern.MatchString(name) {
+		return "", false, nil
+	}
+
+	macro, err := m.repo.GetMacro(userID, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return strings.Join(append([]string{macro.Notation}, parts[1:]...), " "), true, nil
+}
+
+// validateMacroName checks that a name can be used for a macro.
+func validateMacroName(name string) error {
+	if !macroNamePattern.MatchString(name) {
+		return errors.New("macro names start with a letter and may contain up to 32 letters, digits, - and _")
+	}
+
+	for _, reserved := range reservedMacroNames {
+		if name == reserved {
+			return fmt.Errorf("%s is a command and can't be used as macro name", name)
+		}
+	}
+
+	if _, err := ParseExpression(name); err == nil {
+		return fmt.Errorf("%s is a dice expression and can't be used as macro name", name)
+	}
+
+	return nil
+}
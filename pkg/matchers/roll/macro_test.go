@@ -0,0 +1,131 @@
+package roll_test
+
+import (
+	"testing"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/roll"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// testUserID is the user ID of telegramclient.TestWebhookMessage.
+const testUserID = int64(456)
+
+func TestMatcher_Process_SaveMacro(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("SaveMacro", testUserID, "attack", "1d20+7 15").Return(nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll save Attack 1d20+7 15"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, `💾 Saved macro *attack*: 1d20\+7 15`, replies[0].Text)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMatcher_Process_SaveMacroInvalid(t *testing.T) {
+	t.Parallel()
+
+	matcher := roll.MakeMatcher(new(MockRollRepo), telegramclient.NewMockClient())
+
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{"/roll save", "Usage"},
+		{"/roll save attack", "Usage"},
+		{"/roll save 1attack 1d20", "macro names start with a letter"},
+		{"/roll save stats 1d20", "stats is a command"},
+		{"/roll save gm 1d20", "gm is a command"},
+		{"/roll save d% 1d20", "macro names start with a letter"},
+		{"/roll save attack 1d20+", "invalid dice notation"},
+		{"/roll save attack 1d20 kh", "invalid threshold"},
+	}
+
+	for _, tt := range tests {
+		replies, err := matcher.Process(newTestMessage(tt.in))
+		require.NoError(t, err, tt.in)
+		require.Len(t, replies, 1, tt.in)
+		assert.Contains(t, replies[0].Text, "❌", tt.in)
+		assert.Contains(t, replies[0].Text, tt.expected, tt.in)
+	}
+}
+
+func TestMatcher_Process_RollMacro(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetMacro", testUserID, "attack").Return(&interfaces.RollMacro{Name: "attack", Notation: "1d20+7 15"}, nil)
+	mockRepo.On("SaveRoll", mock.MatchedBy(func(r *interfaces.Roll) bool {
+		return r.UserID == testUserID && r.DiceSides == 20 && r.Threshold != nil && *r.Threshold == 15
+	})).Return(nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll Attack"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, `1d20\+7`)
+	assert.Contains(t, replies[0].Text, "Threshold: 15")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMatcher_Process_RollUnknownMacro(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetMacro", testUserID, "attack").Return(nil, gorm.ErrRecordNotFound)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll attack"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "❌")
+	assert.Contains(t, replies[0].Text, "unexpected character")
+}
+
+func TestMatcher_Process_Macros(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetMacros", testUserID).Return([]interfaces.RollMacro{
+		{Name: "attack", Notation: "1d20+7 15"},
+		{Name: "damage", Notation: "2d6+3"},
+	}, nil).Once()
+	mockRepo.On("GetMacros", testUserID).Return([]interfaces.RollMacro{}, nil).Once()
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll macros"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "📜 *Your macros:*\nattack: 1d20\\+7 15\ndamage: 2d6\\+3", replies[0].Text)
+
+	replies, err = matcher.Process(newTestMessage("/roll macros"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "You have no macros yet")
+}
+
+func TestMatcher_Process_DeleteMacro(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("DeleteMacro", testUserID, "attack").Return(true, nil)
+	mockRepo.On("DeleteMacro", testUserID, "damage").Return(false, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll delete attack"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "🗑 Deleted macro *attack*\\.", replies[0].Text)
+
+	replies, err = matcher.Process(newTestMessage("/roll delete damage"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "❌ Macro not found: damage", replies[0].Text)
+}
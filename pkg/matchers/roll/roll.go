@@ -7,7 +7,9 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	logger "github.com/br0-space/bot-logger"
 	matcher "github.com/br0-space/bot-matcher"
 	telegramclient "github.com/br0-space/bot-telegramclient"
 	"github.com/br0-space/bot/interfaces"
@@ -27,6 +29,16 @@ const (
 	statsTypeLucky   = "lucky"
 	statsTypeUnlucky = "unlucky"
 	statsTypeUser    = "user"
+	statsTypeWeek    = "week"
+	statsTypeMonth   = "month"
+	statsTypeSession = "session"
+)
+
+const (
+	statsWeekDays  = 7
+	statsMonthDays = 30
+	// sessionGap is the break between two rolls after which a new session starts.
+	sessionGap = 3 * time.Hour
 )
 
 const (
@@ -35,59 +47,85 @@ const (
 	maxPartsLength    = 3
 )
 
-var (
-	criticalHitMessages = []string{
-		"You cleaved the dragon in twain with a single mighty blow!",
-		"The gods themselves smile upon your roll!",
-		"Your enemies flee in terror at your prowess!",
-		"Natural 20! The dice love you today!",
-		"Perfection! Even the DM is impressed!",
-		"You've peaked! It's all downhill from here... just kidding!",
-		"The prophecy is true! You are the chosen one!",
-		"Your grandmother would be so proud!",
-	}
-
-	criticalFailureMessages = []string{
-		"You tripped over your own feet and face-planted into the mud!",
-		"You shot yourself in the foot. Literally.",
-		"The dice gods have forsaken you!",
-		"Natural 1! Time to update your character sheet... in memoriam.",
-		"You somehow managed to critical fail at rolling dice. Impressive!",
-		"Your weapon flies out of your hand and hits your ally!",
-		"You sneezed at the wrong moment. Catastrophically.",
-		"The DM is laughing. Everyone is laughing. You are not laughing.",
-	}
-)
-
-var pattern = regexp.MustCompile(`(?i)^/(roll)(@\w+)?($| )(.*)$`)
+var pattern = regexp.MustCompile(`(?i)^/(roll|groll)(@\w+)?($| )(.*)$`)
 
 var help = []matcher.HelpStruct{
 	{
 		Command:     `roll`,
-		Description: `Rolls dice in D&D notation with optional threshold and advantage mechanics.`,
+		Description: `Rolls dice in D&D notation like 2d6+3, 1d20+1d4-2, 4d6kh3, 2d20kl1, 3d6!, 4d6r1 or d% with optional threshold and advantage mechanics.`,
 		Usage:       `/roll [dice] [threshold] [kh]`,
-		Example:     `/roll 2d20 15 kh`,
+		Example:     `/roll 1d20+1d4-2 15`,
+	},
+	{
+		Command:     `roll gm`,
+		Description: `Rolls secretly for the GM. The result is sent by private message, the chat only sees that the GM rolled.`,
+		Usage:       `/roll gm <dice> [threshold] [kh] or /groll <dice> [threshold] [kh]`,
+		Example:     `/groll 1d20+5`,
+	},
+	{
+		Command:     `roll save`,
+		Description: `Saves a roll as macro, which you can roll with /roll <name>.`,
+		Usage:       `/roll save <name> <dice> [threshold] [kh]`,
+		Example:     `/roll save attack 1d20+7 15`,
+	},
+	{
+		Command:     `roll macros`,
+		Description: `Lists your macros.`,
+		Usage:       `/roll macros`,
+		Example:     `/roll macros`,
+	},
+	{
+		Command:     `roll delete`,
+		Description: `Deletes one of your macros.`,
+		Usage:       `/roll delete <name>`,
+		Example:     `/roll delete attack`,
+	},
+	{
+		Command:     `roll history`,
+		Description: `Lists the latest rolls in the chat or of a user.`,
+		Usage:       `/roll history [@user] [n]`,
+		Example:     `/roll history @username 20`,
+	},
+	{
+		Command:     `roll fairness`,
+		Description: `Tests with a chi-square test whether the dice of the chat or of a user roll fair.`,
+		Usage:       `/roll fairness [@user] [d20]`,
+		Example:     `/roll fairness @username d20`,
 	},
 	{
 		Command:     `roll stats`,
-		Description: `Shows roll statistics.`,
-		Usage:       `/roll stats [user|lucky|unlucky]`,
-		Example:     `/roll stats @username`,
+		Description: `Shows roll statistics of all time, the last 7 or 30 days or the current session, including streaks and records.`,
+		Usage:       `/roll stats [user|lucky|unlucky|week|month|session]`,
+		Example:     `/roll stats week`,
 	},
 }
 
 // Matcher implements the roll command matcher.
 type Matcher struct {
-	matcher.Matcher
+	matcher.WithCustomConfigType[Config]
 
-	repo interfaces.RollRepoInterface
+	log    logger.Interface
+	repo   interfaces.RollRepoInterface
+	client telegramclient.ClientInterface
 }
 
-// MakeMatcher creates a new roll matcher instance.
-func MakeMatcher(repo interfaces.RollRepoInterface) Matcher {
+// MakeMatcher creates a new roll matcher instance. The client sends secret rolls by private message.
+func MakeMatcher(repo interfaces.RollRepoInterface, client telegramclient.ClientInterface) Matcher {
+	defaults, err := LoadDefaultConfig()
+	if err != nil {
+		panic(fmt.Sprintf("failed to load default config for %s: %v", identifier, err))
+	}
+
+	cfg, err := LoadConfig(defaults)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load matcher config for %s: %v", identifier, err))
+	}
+
 	return Matcher{
-		Matcher: matcher.MakeMatcher(identifier, pattern, help),
-		repo:    repo,
+		WithCustomConfigType: matcher.MakeMatcherWithCustomConfigType(identifier, pattern, help, cfg),
+		log:                  logger.New(),
+		repo:                 repo,
+		client:               client,
 	}
 }
 
@@ -101,39 +139,78 @@ func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]teleg
 	// Extract and trim arguments
 	args := strings.TrimSpace(match[3])
 
+	if strings.EqualFold(match[0], groll) {
+		return m.processSecretRoll(messageIn, args)
+	}
+
 	// Check if it's a stats command
 	if strings.HasPrefix(strings.ToLower(args), "stats") {
 		return m.processStats(messageIn, args)
 	}
 
+	// Check if it's a macro, history or fairness command
+	if fields := strings.Fields(args); len(fields) > 0 {
+		switch strings.ToLower(fields[0]) {
+		case commandSave:
+			return m.processSaveMacro(messageIn, fields[1:])
+		case commandMacros:
+			return m.processMacros(messageIn)
+		case commandDelete:
+			return m.processDeleteMacro(messageIn, fields[1:])
+		case commandHistory:
+			return m.processHistory(messageIn, fields[1:])
+		case commandFairness:
+			return m.processFairness(messageIn, fields[1:])
+		case commandGM:
+			return m.processSecretRoll(messageIn, strings.Join(fields[1:], " "))
+		}
+	}
+
 	return m.processRoll(messageIn, args)
 }
 
 // processRoll handles the dice rolling command.
 func (m Matcher) processRoll(messageIn telegramclient.WebhookMessageStruct, args string) ([]telegramclient.MessageStruct, error) {
-	// Parse dice notation
-	count, sides, threshold, keepHighest, err := parseDiceNotation(args)
+	expression, threshold, keepHighest, err := m.parseRoll(messageIn.From.ID, args)
 	if err != nil {
 		return m.makeReply("❌ "+telegramclient.EscapeMarkdown(err.Error()), messageIn.ID)
 	}
 
 	// Create and perform roll
-	roll := NewDiceRoll(count, sides, threshold, keepHighest)
+	roll := NewExpressionRoll(expression, threshold, keepHighest)
 	roll.Roll()
 
 	// Save to database
-	if err := m.saveRoll(messageIn.From.ID, roll); err != nil {
-		// Log error but don't fail the response
-		// In production, you might want to log this properly
-		_ = err
+	if err := m.saveRoll(messageIn.Chat.ID, messageIn.From.ID, roll, false); err != nil {
+		// The roll is still valid, so don't fail the response
+		m.log.Error("Unable to save roll:", err)
 	}
 
 	// Format response
-	response := formatRollResponse(roll)
+	response := formatRollResponse(roll, m.Config())
 
 	return m.makeReply(response, messageIn.ID)
 }
 
+// parseRoll parses the arguments of a roll, falling back to a macro of the user.
+func (m Matcher) parseRoll(userID int64, args string) (Expression, *int, bool, error) {
+	expression, threshold, keepHighest, err := parseDiceNotation(args)
+	if err == nil {
+		return expression, threshold, keepHighest, nil
+	}
+
+	macroArgs, found, macroErr := m.resolveMacro(userID, args)
+	if macroErr != nil {
+		return Expression{}, nil, false, errors.New("error retrieving macro")
+	}
+
+	if !found {
+		return Expression{}, nil, false, err
+	}
+
+	return parseDiceNotation(macroArgs)
+}
+
 // processStats handles the stats command.
 //
 //nolint:cyclop // Complexity is acceptable for stats routing logic.
@@ -141,9 +218,15 @@ func (m Matcher) processStats(messageIn telegramclient.WebhookMessageStruct, arg
 	// Remove "stats" prefix and trim
 	args = strings.TrimSpace(strings.TrimPrefix(strings.ToLower(args), "stats"))
 
+	switch args {
+	case statsTypeWeek, statsTypeMonth, statsTypeSession:
+		return m.processPeriodStats(messageIn, args)
+	}
+
 	var (
 		stats      *interfaces.RollStatsStruct
 		topRollers []interfaces.RollStatsStruct
+		streaks    []interfaces.RollStreakStruct
 	)
 
 	statsType := statsTypeOverall
@@ -153,15 +236,23 @@ func (m Matcher) processStats(messageIn telegramclient.WebhookMessageStruct, arg
 	switch args {
 	case "":
 		// Overall stats
-		stats, err = m.repo.GetOverallStats()
+		stats, err = m.repo.GetOverallStats(messageIn.Chat.ID)
 		if err != nil {
 			return m.makeReply("❌ Error retrieving statistics\\.", messageIn.ID)
 		}
 
-		topRollers, _ = m.repo.GetTopRollers(topRollersLimit)
+		topRollers, err = m.repo.GetTopRollers(messageIn.Chat.ID, topRollersLimit)
+		if err != nil {
+			return m.makeReply("❌ Error retrieving statistics\\.", messageIn.ID)
+		}
+
+		streaks, err = m.repo.GetStreaks(messageIn.Chat.ID, time.Time{})
+		if err != nil {
+			return m.makeReply("❌ Error retrieving statistics\\.", messageIn.ID)
+		}
 	case statsTypeLucky:
 		// Luckiest roller
-		stats, err = m.repo.GetLuckiestRoller()
+		stats, err = m.repo.GetLuckiestRoller(messageIn.Chat.ID)
 		if err != nil || stats == nil || stats.TotalRolls == 0 {
 			return m.makeReply("No lucky roller found \\(minimum 10 rolls required\\)\\.", messageIn.ID)
 		}
@@ -169,7 +260,7 @@ func (m Matcher) processStats(messageIn telegramclient.WebhookMessageStruct, arg
 		statsType = statsTypeLucky
 	case statsTypeUnlucky:
 		// Unluckiest roller
-		stats, err = m.repo.GetUnluckiestRoller()
+		stats, err = m.repo.GetUnluckiestRoller(messageIn.Chat.ID)
 		if err != nil || stats == nil || stats.TotalRolls == 0 {
 			return m.makeReply("No unlucky roller found \\(minimum 10 rolls required\\)\\.", messageIn.ID)
 		}
@@ -181,12 +272,17 @@ func (m Matcher) processStats(messageIn telegramclient.WebhookMessageStruct, arg
 		username := strings.TrimPrefix(args, "@")
 
 		// Look up user by username
-		userID := m.findUserIDByUsername(username)
+		userID := m.findUserIDByUsername(messageIn.Chat.ID, username)
 		if userID == 0 {
 			return m.makeReply("❌ User not found: "+telegramclient.EscapeMarkdown(username), messageIn.ID)
 		}
 
-		stats, err = m.repo.GetUserStats(userID)
+		stats, err = m.repo.GetUserStats(messageIn.Chat.ID, userID)
+		if err != nil {
+			return m.makeReply("❌ Error retrieving statistics\\.", messageIn.ID)
+		}
+
+		streaks, err = m.findUserStreaks(messageIn.Chat.ID, userID)
 		if err != nil {
 			return m.makeReply("❌ Error retrieving statistics\\.", messageIn.ID)
 		}
@@ -195,21 +291,83 @@ func (m Matcher) processStats(messageIn telegramclient.WebhookMessageStruct, arg
 	}
 
 	// Format response
-	response := formatStatsResponse(stats, topRollers, statsType)
+	response := formatStatsResponse(stats, topRollers, streaks, statsType)
 
 	return m.makeReply(response, messageIn.ID)
 }
 
-// saveRoll saves a roll to the database.
-func (m Matcher) saveRoll(userID int64, roll *DiceRoll) error {
+// processPeriodStats handles the stats of the last week, month or the current session.
+func (m Matcher) processPeriodStats(messageIn telegramclient.WebhookMessageStruct, period string) ([]telegramclient.MessageStruct, error) {
+	var since time.Time
+
+	switch period {
+	case statsTypeWeek:
+		since = time.Now().AddDate(0, 0, -statsWeekDays)
+	case statsTypeMonth:
+		since = time.Now().AddDate(0, 0, -statsMonthDays)
+	case statsTypeSession:
+		start, err := m.repo.GetSessionStart(messageIn.Chat.ID, sessionGap)
+		if err != nil {
+			return m.makeReply("❌ Error retrieving statistics\\.", messageIn.ID)
+		}
+
+		if start.IsZero() {
+			return m.makeReply(formatStatsResponse(nil, nil, nil, period), messageIn.ID)
+		}
+
+		since = start
+	}
+
+	stats, err := m.repo.GetOverallStatsSince(messageIn.Chat.ID, since)
+	if err != nil {
+		return m.makeReply("❌ Error retrieving statistics\\.", messageIn.ID)
+	}
+
+	topRollers, err := m.repo.GetTopRollersSince(messageIn.Chat.ID, since, topRollersLimit)
+	if err != nil {
+		return m.makeReply("❌ Error retrieving statistics\\.", messageIn.ID)
+	}
+
+	streaks, err := m.repo.GetStreaks(messageIn.Chat.ID, since)
+	if err != nil {
+		return m.makeReply("❌ Error retrieving statistics\\.", messageIn.ID)
+	}
+
+	return m.makeReply(formatStatsResponse(stats, topRollers, streaks, period), messageIn.ID)
+}
+
+// findUserStreaks returns the streaks of a user.
+func (m Matcher) findUserStreaks(chatID int64, userID int64) ([]interfaces.RollStreakStruct, error) {
+	streaks, err := m.repo.GetStreaks(chatID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, streak := range streaks {
+		if streak.UserID == userID {
+			return []interfaces.RollStreakStruct{streak}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// saveRoll saves a roll to the database. Hidden rolls are secret rolls of the GM.
+func (m Matcher) saveRoll(chatID int64, userID int64, roll *DiceRoll, hidden bool) error {
 	// Convert results to JSON
 	resultsJSON, err := json.Marshal(roll.GetResults())
 	if err != nil {
 		return err
 	}
 
+	facesJSON, err := json.Marshal(roll.GetFaces())
+	if err != nil {
+		return err
+	}
+
 	// Create Roll struct
 	dbRoll := &interfaces.Roll{
+		ChatID:          chatID,
 		UserID:          userID,
 		DiceCount:       roll.GetCount(),
 		DiceSides:       roll.GetSides(),
@@ -220,14 +378,17 @@ func (m Matcher) saveRoll(userID int64, roll *DiceRoll) error {
 		Success:         roll.IsSuccess(),
 		CriticalHit:     roll.IsCriticalHit(),
 		CriticalFailure: roll.IsCriticalFailure(),
+		Notation:        roll.GetExpression().String(),
+		Faces:           string(facesJSON),
+		Hidden:          hidden,
 	}
 
 	return m.repo.SaveRoll(dbRoll)
 }
 
 // findUserIDByUsername looks up a user ID by username.
-func (m Matcher) findUserIDByUsername(username string) int64 {
-	userID, err := m.repo.GetUserIDByUsername(username)
+func (m Matcher) findUserIDByUsername(chatID int64, username string) int64 {
+	userID, err := m.repo.GetUserIDByUsername(chatID, username)
 	if err != nil {
 		return 0
 	}
@@ -235,46 +396,24 @@ func (m Matcher) findUserIDByUsername(username string) int64 {
 	return userID
 }
 
-// parseDiceNotation parses dice notation string and returns count, sides, threshold, keepHighest, and error.
+// parseDiceNotation parses dice notation string and returns the expression, threshold, keepHighest, and error.
 //
-//nolint:funlen,cyclop,mnd // Dice notation parsing requires extensive validation
-func parseDiceNotation(input string) (int, int, *int, bool, error) {
+//nolint:cyclop,mnd // Dice notation parsing requires extensive validation
+func parseDiceNotation(input string) (Expression, *int, bool, error) {
 	input = strings.TrimSpace(input)
 
 	// Default values
 	if input == "" {
-		return defaultCount, defaultSides, nil, false, nil
+		return Expression{Terms: []Term{{Sign: 1, Dice: &DiceTerm{Count: defaultCount, Sides: defaultSides}}}}, nil, false, nil
 	}
 
 	// Split by spaces
 	parts := strings.Fields(input)
 
-	// Parse dice notation (first part)
-	diceRegex := regexp.MustCompile(`^(\d+)d(\d+)$`)
-
-	matches := diceRegex.FindStringSubmatch(strings.ToLower(parts[0]))
-	if matches == nil {
-		return 0, 0, nil, false, errors.New("invalid dice notation! Use format: 2d20 or 2d20 15")
-	}
-
-	count, _ := strconv.Atoi(matches[1])
-	sides, _ := strconv.Atoi(matches[2])
-
-	// Validate dice values
-	if count <= 0 {
-		return 0, 0, nil, false, errors.New("dice count must be greater than 0")
-	}
-
-	if sides <= 1 {
-		return 0, 0, nil, false, errors.New("dice sides must be greater than 1")
-	}
-
-	if count > maxCount {
-		return 0, 0, nil, false, fmt.Errorf("maximum dice count is %d", maxCount)
-	}
-
-	if sides > maxSides {
-		return 0, 0, nil, false, fmt.Errorf("maximum dice sides is %d", maxSides)
+	// Parse dice expression (first part)
+	expression, err := ParseExpression(parts[0])
+	if err != nil {
+		return Expression{}, nil, false, err
 	}
 
 	// Parse threshold (second part, optional)
@@ -285,11 +424,11 @@ func parseDiceNotation(input string) (int, int, *int, bool, error) {
 	if len(parts) >= 2 {
 		t, err := strconv.Atoi(parts[1])
 		if err != nil {
-			return 0, 0, nil, false, errors.New("invalid threshold value")
+			return Expression{}, nil, false, errors.New("invalid threshold value")
 		}
 
 		if t < minThresholdValue {
-			return 0, 0, nil, false, errors.New("threshold must be non-negative")
+			return Expression{}, nil, false, errors.New("threshold must be non-negative")
 		}
 
 		threshold = &t
@@ -298,22 +437,22 @@ func parseDiceNotation(input string) (int, int, *int, bool, error) {
 	// Parse keep highest flag (third part, optional)
 	if len(parts) >= 3 {
 		if threshold == nil {
-			return 0, 0, nil, false, errors.New("kh modifier requires a threshold")
+			return Expression{}, nil, false, errors.New("kh modifier requires a threshold")
 		}
 
 		if strings.ToLower(parts[2]) == "kh" {
 			keepHighest = true
 		} else {
-			return 0, 0, nil, false, fmt.Errorf("unknown modifier: %s (use 'kh' for advantage)", parts[2])
+			return Expression{}, nil, false, fmt.Errorf("unknown modifier: %s (use 'kh' for advantage)", parts[2])
 		}
 	}
 
 	// Check for extra parameters
 	if len(parts) > maxPartsLength {
-		return 0, 0, nil, false, errors.New("too many parameters")
+		return Expression{}, nil, false, errors.New("too many parameters")
 	}
 
-	return count, sides, threshold, keepHighest, nil
+	return expression, threshold, keepHighest, nil
 }
 
 // makeReply creates a reply message.
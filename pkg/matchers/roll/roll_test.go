@@ -1,8 +1,10 @@
 package roll_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	telegramclient "github.com/br0-space/bot-telegramclient"
 	"github.com/br0-space/bot/interfaces"
@@ -10,8 +12,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
+// testChatID is the chat ID of telegramclient.TestWebhookMessage.
+const testChatID = int64(789)
+
 // MockRollRepo is a mock implementation of RollRepoInterface.
 type MockRollRepo struct {
 	mock.Mock
@@ -23,8 +29,8 @@ func (m *MockRollRepo) SaveRoll(r *interfaces.Roll) error {
 	return args.Error(0)
 }
 
-func (m *MockRollRepo) GetOverallStats() (*interfaces.RollStatsStruct, error) {
-	args := m.Called()
+func (m *MockRollRepo) GetOverallStats(chatID int64) (*interfaces.RollStatsStruct, error) {
+	args := m.Called(chatID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -37,8 +43,8 @@ func (m *MockRollRepo) GetOverallStats() (*interfaces.RollStatsStruct, error) {
 	return stats, args.Error(1)
 }
 
-func (m *MockRollRepo) GetUserStats(userID int64) (*interfaces.RollStatsStruct, error) {
-	args := m.Called(userID)
+func (m *MockRollRepo) GetUserStats(chatID int64, userID int64) (*interfaces.RollStatsStruct, error) {
+	args := m.Called(chatID, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -52,8 +58,22 @@ func (m *MockRollRepo) GetUserStats(userID int64) (*interfaces.RollStatsStruct,
 	return stats, args.Error(1)
 }
 
-func (m *MockRollRepo) GetLuckiestRoller() (*interfaces.RollStatsStruct, error) {
-	args := m.Called()
+func (m *MockRollRepo) GetLuckiestRoller(chatID int64) (*interfaces.RollStatsStruct, error) {
+	args := m.Called(chatID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	stats, ok := args.Get(0).(*interfaces.RollStatsStruct)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return stats, args.Error(1)
+}
+
+func (m *MockRollRepo) GetUnluckiestRoller(chatID int64) (*interfaces.RollStatsStruct, error) {
+	args := m.Called(chatID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -66,8 +86,22 @@ func (m *MockRollRepo) GetLuckiestRoller() (*interfaces.RollStatsStruct, error)
 	return stats, args.Error(1)
 }
 
-func (m *MockRollRepo) GetUnluckiestRoller() (*interfaces.RollStatsStruct, error) {
-	args := m.Called()
+func (m *MockRollRepo) GetTopRollers(chatID int64, limit int) ([]interfaces.RollStatsStruct, error) {
+	args := m.Called(chatID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	rollers, ok := args.Get(0).([]interfaces.RollStatsStruct)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return rollers, args.Error(1)
+}
+
+func (m *MockRollRepo) GetOverallStatsSince(chatID int64, since time.Time) (*interfaces.RollStatsStruct, error) {
+	args := m.Called(chatID, since)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -80,8 +114,8 @@ func (m *MockRollRepo) GetUnluckiestRoller() (*interfaces.RollStatsStruct, error
 	return stats, args.Error(1)
 }
 
-func (m *MockRollRepo) GetTopRollers(limit int) ([]interfaces.RollStatsStruct, error) {
-	args := m.Called(limit)
+func (m *MockRollRepo) GetTopRollersSince(chatID int64, since time.Time, limit int) ([]interfaces.RollStatsStruct, error) {
+	args := m.Called(chatID, since, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -94,8 +128,33 @@ func (m *MockRollRepo) GetTopRollers(limit int) ([]interfaces.RollStatsStruct, e
 	return rollers, args.Error(1)
 }
 
-func (m *MockRollRepo) GetUserIDByUsername(username string) (int64, error) {
-	args := m.Called(username)
+func (m *MockRollRepo) GetStreaks(chatID int64, since time.Time) ([]interfaces.RollStreakStruct, error) {
+	args := m.Called(chatID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	streaks, ok := args.Get(0).([]interfaces.RollStreakStruct)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return streaks, args.Error(1)
+}
+
+func (m *MockRollRepo) GetSessionStart(chatID int64, gap time.Duration) (time.Time, error) {
+	args := m.Called(chatID, gap)
+
+	start, ok := args.Get(0).(time.Time)
+	if !ok {
+		return time.Time{}, args.Error(1)
+	}
+
+	return start, args.Error(1)
+}
+
+func (m *MockRollRepo) GetUserIDByUsername(chatID int64, username string) (int64, error) {
+	args := m.Called(chatID, username)
 
 	userID, ok := args.Get(0).(int64)
 	if !ok {
@@ -105,12 +164,80 @@ func (m *MockRollRepo) GetUserIDByUsername(username string) (int64, error) {
 	return userID, args.Error(1)
 }
 
+func (m *MockRollRepo) GetRecentRolls(chatID int64, userID int64, limit int) ([]interfaces.RollHistoryStruct, error) {
+	args := m.Called(chatID, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	rolls, ok := args.Get(0).([]interfaces.RollHistoryStruct)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return rolls, args.Error(1)
+}
+
+func (m *MockRollRepo) GetFaceCounts(chatID int64, userID int64) ([]interfaces.RollFacesStruct, error) {
+	args := m.Called(chatID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	faces, ok := args.Get(0).([]interfaces.RollFacesStruct)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return faces, args.Error(1)
+}
+
+func (m *MockRollRepo) SaveMacro(userID int64, name string, notation string) error {
+	args := m.Called(userID, name, notation)
+
+	return args.Error(0)
+}
+
+func (m *MockRollRepo) GetMacro(userID int64, name string) (*interfaces.RollMacro, error) {
+	args := m.Called(userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	macro, ok := args.Get(0).(*interfaces.RollMacro)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return macro, args.Error(1)
+}
+
+func (m *MockRollRepo) GetMacros(userID int64) ([]interfaces.RollMacro, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	macros, ok := args.Get(0).([]interfaces.RollMacro)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return macros, args.Error(1)
+}
+
+func (m *MockRollRepo) DeleteMacro(userID int64, name string) (bool, error) {
+	args := m.Called(userID, name)
+
+	return args.Bool(0), args.Error(1)
+}
+
 func provideMatcher() roll.Matcher {
 	mockRepo := new(MockRollRepo)
 	// Setup default mock behavior - save rolls successfully
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
 
-	return roll.MakeMatcher(mockRepo)
+	return roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 }
 
 func newTestMessage(text string) telegramclient.WebhookMessageStruct {
@@ -130,6 +257,7 @@ var doesMatchTests = []struct {
 	{"foobar", false},
 	{"roll", false},
 	{"/rollx", false},
+	{"/grollx", false},
 	{" /roll", false},
 	{"hello /roll", false},
 
@@ -140,6 +268,8 @@ var doesMatchTests = []struct {
 	{"/roll@bot ", true},
 	{"/ROLL", true},
 	{"/Roll", true},
+	{"/groll", true},
+	{"/groll@bot 1d20", true},
 
 	// Roll with arguments
 	{"/roll 2d20", true},
@@ -186,7 +316,7 @@ func TestMatcher_Process_DefaultRoll(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll"))
 	require.NoError(t, err)
@@ -205,7 +335,7 @@ func TestMatcher_Process_CustomDiceRoll(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll 2d20"))
 	require.NoError(t, err)
@@ -224,7 +354,7 @@ func TestMatcher_Process_WithThreshold(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll 2d20 15"))
 	require.NoError(t, err)
@@ -245,7 +375,7 @@ func TestMatcher_Process_WithKeepHighest(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll 2d20 15 kh"))
 	require.NoError(t, err)
@@ -270,7 +400,8 @@ func TestMatcher_Process_InvalidDiceNotation(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	mockRepo.On("GetMacro", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	invalidInputs := []string{
 		"/roll d20",      // No count
@@ -301,7 +432,7 @@ func TestMatcher_Process_KeepHighestWithoutThreshold(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll 2d20 kh"))
 	require.NoError(t, err)
@@ -315,7 +446,7 @@ func TestMatcher_Process_InvalidModifier(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll 2d20 15 invalid"))
 	require.NoError(t, err)
@@ -329,7 +460,7 @@ func TestMatcher_Process_TooManyParameters(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll 2d20 15 kh extra"))
 	require.NoError(t, err)
@@ -347,11 +478,12 @@ func TestMatcher_Process_StatsOverall_NoData(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetOverallStats").Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetOverallStats", testChatID).Return(&interfaces.RollStatsStruct{
 		TotalRolls: 0,
 	}, nil)
-	mockRepo.On("GetTopRollers", 5).Return([]interfaces.RollStatsStruct{}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	mockRepo.On("GetTopRollers", testChatID, 5).Return([]interfaces.RollStatsStruct{}, nil)
+	mockRepo.On("GetStreaks", testChatID, time.Time{}).Return([]interfaces.RollStreakStruct{}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats"))
 	require.NoError(t, err)
@@ -367,7 +499,7 @@ func TestMatcher_Process_StatsOverall_WithData(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetOverallStats").Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetOverallStats", testChatID).Return(&interfaces.RollStatsStruct{
 		TotalRolls:       100,
 		TotalDice:        250,
 		AverageRoll:      10.5,
@@ -377,11 +509,12 @@ func TestMatcher_Process_StatsOverall_WithData(t *testing.T) {
 		CriticalFailures: 3,
 		SuccessRate:      65.0,
 	}, nil)
-	mockRepo.On("GetTopRollers", 5).Return([]interfaces.RollStatsStruct{
+	mockRepo.On("GetTopRollers", testChatID, 5).Return([]interfaces.RollStatsStruct{
 		{Username: "user1", TotalRolls: 50},
 		{Username: "user2", TotalRolls: 30},
 	}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	mockRepo.On("GetStreaks", testChatID, time.Time{}).Return([]interfaces.RollStreakStruct{}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats"))
 	require.NoError(t, err)
@@ -399,12 +532,12 @@ func TestMatcher_Process_StatsLucky(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetLuckiestRoller").Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetLuckiestRoller", testChatID).Return(&interfaces.RollStatsStruct{
 		Username:    "luckyuser",
 		TotalRolls:  50,
 		AverageRoll: 15.2,
 	}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats lucky"))
 	require.NoError(t, err)
@@ -421,12 +554,12 @@ func TestMatcher_Process_StatsUnlucky(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetUnluckiestRoller").Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetUnluckiestRoller", testChatID).Return(&interfaces.RollStatsStruct{
 		Username:    "unluckyuser",
 		TotalRolls:  50,
 		AverageRoll: 5.2,
 	}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats unlucky"))
 	require.NoError(t, err)
@@ -443,13 +576,14 @@ func TestMatcher_Process_StatsUser(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetUserIDByUsername", "testuser").Return(int64(123), nil)
-	mockRepo.On("GetUserStats", int64(123)).Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetUserIDByUsername", testChatID, "testuser").Return(int64(123), nil)
+	mockRepo.On("GetUserStats", testChatID, int64(123)).Return(&interfaces.RollStatsStruct{
 		Username:    "testuser",
 		TotalRolls:  25,
 		AverageRoll: 11.5,
 	}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	mockRepo.On("GetStreaks", testChatID, time.Time{}).Return([]interfaces.RollStreakStruct{}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	// Test with @ prefix
 	replies, err := matcher.Process(newTestMessage("/roll stats @testuser"))
@@ -472,8 +606,8 @@ func TestMatcher_Process_StatsUser_NotFound(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetUserIDByUsername", "nonexistent").Return(int64(0), nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	mockRepo.On("GetUserIDByUsername", testChatID, "nonexistent").Return(int64(0), nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats nonexistent"))
 	require.NoError(t, err)
@@ -483,15 +617,144 @@ func TestMatcher_Process_StatsUser_NotFound(t *testing.T) {
 	assert.Contains(t, replies[0].Text, "User not found")
 }
 
+func TestMatcher_Process_StatsWeek(t *testing.T) {
+	t.Parallel()
+
+	sinceWeek := mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) > 7*24*time.Hour-time.Minute && time.Since(since) < 7*24*time.Hour+time.Minute
+	})
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetOverallStatsSince", testChatID, sinceWeek).Return(&interfaces.RollStatsStruct{
+		TotalRolls:  12,
+		TotalDice:   20,
+		AverageRoll: 9.5,
+	}, nil)
+	mockRepo.On("GetTopRollersSince", testChatID, sinceWeek, 5).Return([]interfaces.RollStatsStruct{
+		{Username: "alice", TotalRolls: 12},
+	}, nil)
+	mockRepo.On("GetStreaks", testChatID, sinceWeek).Return([]interfaces.RollStreakStruct{
+		{Username: "alice", SuccessStreak: 4, FailureStreak: 1, SwingFrom: 20, SwingTo: 1},
+		{Username: "bob", SuccessStreak: 2, FailureStreak: 3, CriticalStreak: 2, SwingFrom: 3, SwingTo: 5},
+	}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll stats week"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+
+	text := replies[0].Text
+	assert.Contains(t, text, "Roll Statistics: Last 7 Days")
+	assert.Contains(t, text, "Total Rolls: 12")
+	assert.Contains(t, text, "Top Rollers")
+	assert.Contains(t, text, "Records")
+	assert.Contains(t, text, "Longest Success Streak: @alice \\- 4")
+	assert.Contains(t, text, "Longest Failure Streak: @bob \\- 3")
+	assert.Contains(t, text, "Most Consecutive Crits: @bob \\- 2")
+	assert.Contains(t, text, "Biggest Swing: @alice \\- 19 \\(20 → 1\\)")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMatcher_Process_StatsWeek_Error(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetOverallStatsSince", testChatID, mock.Anything).Return(&interfaces.RollStatsStruct{TotalRolls: 1}, nil)
+	mockRepo.On("GetTopRollersSince", testChatID, mock.Anything, 5).Return(nil, errors.New("database is locked"))
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	// A failing section isn't shown as empty
+	replies, err := matcher.Process(newTestMessage("/roll stats week"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "❌ Error retrieving statistics\\.", replies[0].Text)
+	mockRepo.AssertNotCalled(t, "GetStreaks", mock.Anything, mock.Anything)
+}
+
+func TestMatcher_Process_StatsMonth_NoData(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetOverallStatsSince", testChatID, mock.Anything).Return(&interfaces.RollStatsStruct{}, nil)
+	mockRepo.On("GetTopRollersSince", testChatID, mock.Anything, 5).Return([]interfaces.RollStatsStruct{}, nil)
+	mockRepo.On("GetStreaks", testChatID, mock.Anything).Return([]interfaces.RollStreakStruct{}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll stats month"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "No rolls in the last 30 days yet\\.", replies[0].Text)
+}
+
+func TestMatcher_Process_StatsSession(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now().Add(-time.Hour)
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetSessionStart", testChatID, 3*time.Hour).Return(start, nil)
+	mockRepo.On("GetOverallStatsSince", testChatID, start).Return(&interfaces.RollStatsStruct{TotalRolls: 3, TotalDice: 3}, nil)
+	mockRepo.On("GetTopRollersSince", testChatID, start, 5).Return([]interfaces.RollStatsStruct{}, nil)
+	mockRepo.On("GetStreaks", testChatID, start).Return([]interfaces.RollStreakStruct{}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll stats session"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0].Text, "Roll Statistics: Current Session")
+	assert.NotContains(t, replies[0].Text, "Records")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMatcher_Process_StatsSession_NoRolls(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetSessionStart", testChatID, 3*time.Hour).Return(time.Time{}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll stats session"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "No rolls in the current session yet\\.", replies[0].Text)
+}
+
+func TestMatcher_Process_StatsUser_Streaks(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockRollRepo)
+	mockRepo.On("GetUserIDByUsername", testChatID, "testuser").Return(int64(123), nil)
+	mockRepo.On("GetUserStats", testChatID, int64(123)).Return(&interfaces.RollStatsStruct{
+		Username:   "testuser",
+		TotalRolls: 25,
+	}, nil)
+	mockRepo.On("GetStreaks", testChatID, time.Time{}).Return([]interfaces.RollStreakStruct{
+		{UserID: 42, Username: "other", SuccessStreak: 9},
+		{UserID: 123, Username: "testuser", SuccessStreak: 5, CriticalStreak: 2, SwingFrom: 2, SwingTo: 18},
+	}, nil)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
+
+	replies, err := matcher.Process(newTestMessage("/roll stats @testuser"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+
+	text := replies[0].Text
+	assert.Contains(t, text, "Longest Success Streak: 5")
+	assert.NotContains(t, text, "Longest Failure Streak")
+	assert.Contains(t, text, "Most Consecutive Crits: 2")
+	assert.Contains(t, text, "Biggest Swing: 16 \\(2 → 18\\)")
+	assert.NotContains(t, text, "Records")
+}
+
 func TestMatcher_Process_StatsLucky_NoData(t *testing.T) {
 	t.Parallel()
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetLuckiestRoller").Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetLuckiestRoller", testChatID).Return(&interfaces.RollStatsStruct{
 		TotalRolls: 0,
 	}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats lucky"))
 	require.NoError(t, err)
@@ -505,10 +768,10 @@ func TestMatcher_Process_StatsUnlucky_NoData(t *testing.T) {
 
 	mockRepo := new(MockRollRepo)
 	mockRepo.On("SaveRoll", mock.Anything).Return(nil)
-	mockRepo.On("GetUnluckiestRoller").Return(&interfaces.RollStatsStruct{
+	mockRepo.On("GetUnluckiestRoller", testChatID).Return(&interfaces.RollStatsStruct{
 		TotalRolls: 0,
 	}, nil)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("/roll stats unlucky"))
 	require.NoError(t, err)
@@ -525,7 +788,7 @@ func TestMatcher_Process_NonMatch(t *testing.T) {
 	t.Parallel()
 
 	mockRepo := new(MockRollRepo)
-	matcher := roll.MakeMatcher(mockRepo)
+	matcher := roll.MakeMatcher(mockRepo, telegramclient.NewMockClient())
 
 	replies, err := matcher.Process(newTestMessage("just some text"))
 	require.Error(t, err)
@@ -44,7 +44,7 @@ func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]teleg
 		return nil, errors.New("message does not match")
 	}
 
-	users, err := m.repo.GetTopUsers()
+	users, err := m.repo.GetTopUsers(messageIn.Chat.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	matcher "github.com/br0-space/bot-matcher"
 	telegramclient "github.com/br0-space/bot-telegramclient"
@@ -17,26 +18,52 @@ const (
 	defaultLimit = 10
 )
 
-var pattern = regexp.MustCompile(`(?i)^/(top|flop)(@\w+)?($| )(\d+)?`)
+var pattern = regexp.MustCompile(`(?i)^/(top|flop)(@\w+)?($| )(.*)`)
 
 var help = []matcher.HelpStruct{{
 	Command:     `top`,
-	Description: `Zeigt eine Liste der am meisten geplusten Begriffe an.`,
-	Usage:       `/top <optional: Anzahl der Einträge>`,
-	Example:     `/top 10`,
+	Description: `Zeigt eine Liste der am meisten geplusten Begriffe an, insgesamt oder in einem Zeitraum.`,
+	Usage:       `/top <optional: Anzahl der Einträge> <optional: day, week, month, Jahr oder since JJJJ-MM-TT>`,
+	Example:     `/top 20 since 2026-01-01`,
 }, {
 	Command:     `flop`,
-	Description: `Zeigt eine Liste der am meisten geminusten Begriffe an.`,
-	Usage:       `/flop <optional: Anzahl der Einträge>`,
-	Example:     `/flop 10`,
+	Description: `Zeigt eine Liste der am meisten geminusten Begriffe an, insgesamt oder in einem Zeitraum.`,
+	Usage:       `/flop <optional: Anzahl der Einträge> <optional: day, week, month, Jahr oder since JJJJ-MM-TT>`,
+	Example:     `/flop week`,
 }}
 
-const template = "```\n%s\n```"
+const (
+	template      = "```\n%s\n```"
+	usageTemplate = "Das habe ich nicht verstanden. Versuch es mal mit /%s 10, /%s week, /%s 2026 oder /%s since 2026-01-01"
+	emptyTemplate = "In diesem Zeitraum (%s) wurde nichts geplust oder geminust."
+)
+
+var errInvalidArguments = errors.New("invalid arguments")
+
+var rollingPeriods = map[string]struct {
+	title    string
+	duration time.Duration
+}{
+	"day":   {"letzte 24 Stunden", 24 * time.Hour},
+	"week":  {"letzte 7 Tage", 7 * 24 * time.Hour},
+	"month": {"letzte 30 Tage", 30 * 24 * time.Hour},
+}
+
+// yearPattern matches years from 2000 on, smaller numbers are limits.
+var yearPattern = regexp.MustCompile(`^20\d{2}$`)
+
+// period is a time range from since (inclusive) to until (exclusive).
+type period struct {
+	title string
+	since time.Time
+	until time.Time
+}
 
 type Matcher struct {
 	matcher.Matcher
 
 	repo interfaces.PlusplusRepoInterface
+	loc  *time.Location
 }
 
 func MakeMatcher(
@@ -45,6 +72,7 @@ func MakeMatcher(
 	return Matcher{
 		Matcher: matcher.MakeMatcher(identifier, pattern, help),
 		repo:    repo,
+		loc:     time.Local,
 	}
 }
 
@@ -54,34 +82,82 @@ func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]teleg
 		return nil, errors.New("message does not match")
 	}
 
-	cmd := match[0]
-	limit := defaultLimit
+	cmd := strings.ToLower(match[0])
 
-	if match[3] != "" {
-		res, err := strconv.ParseInt(match[3], 10, 0)
-		if err != nil {
-			return nil, err
-		}
+	limit, p, err := parseArguments(match[3], time.Now().In(m.loc))
+	if errors.Is(err, errInvalidArguments) {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(fmt.Sprintf(usageTemplate, cmd, cmd, cmd, cmd), messageIn.ID),
+		}, nil
+	}
 
-		limit = int(res)
+	if err != nil {
+		return nil, err
+	}
+
+	if p != nil {
+		return m.makePeriodReplies(messageIn, cmd, limit, *p)
 	}
 
 	var records []interfaces.Plusplus
 
+	switch cmd {
+	case "top":
+		records, err = m.repo.FindTops(messageIn.Chat.ID, limit)
+	case "flop":
+		records, err = m.repo.FindFlops(messageIn.Chat.ID, limit)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return makeReplies(records, messageIn.ID)
+}
+
+func (m Matcher) makePeriodReplies(
+	messageIn telegramclient.WebhookMessageStruct,
+	cmd string,
+	limit int,
+	p period,
+) ([]telegramclient.MessageStruct, error) {
+	var records []interfaces.PlusplusPeriodStruct
+
 	var err error
 
 	switch cmd {
 	case "top":
-		records, err = m.repo.FindTops(limit)
+		records, err = m.repo.FindTopsInPeriod(messageIn.Chat.ID, p.since, p.until, limit)
 	case "flop":
-		records, err = m.repo.FindFlops(limit)
+		records, err = m.repo.FindFlopsInPeriod(messageIn.Chat.ID, p.since, p.until, limit)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return makeReplies(records, messageIn.ID)
+	if len(records) == 0 {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(fmt.Sprintf(emptyTemplate, p.title), messageIn.ID),
+		}, nil
+	}
+
+	lines := make([]string, 0, len(records)+2)
+	lines = append(lines, fmt.Sprintf("%s %d, %s", strings.ToUpper(cmd[:1])+cmd[1:], limit, p.title))
+	lines = append(lines, fmt.Sprintf("%5s %6s | %s", "+/-", "gesamt", "Begriff"))
+
+	for _, record := range records {
+		lines = append(lines, fmt.Sprintf(
+			"%+5d %6d | %s",
+			record.Delta,
+			record.Value,
+			telegramclient.EscapeMarkdown(record.Name),
+		))
+	}
+
+	return []telegramclient.MessageStruct{
+		telegramclient.MarkdownReply(fmt.Sprintf(template, strings.Join(lines, "\n")), messageIn.ID),
+	}, nil
 }
 
 func makeReplies(records []interfaces.Plusplus, messageID int64) ([]telegramclient.MessageStruct, error) {
@@ -103,3 +179,71 @@ func makeReplies(records []interfaces.Plusplus, messageID int64) ([]telegramclie
 		telegramclient.MarkdownReply(text, messageID),
 	}, nil
 }
+
+// parseArguments reads an optional limit and an optional period in any order.
+// Without a period, the returned period is nil and the all-time values are meant.
+func parseArguments(args string, now time.Time) (int, *period, error) {
+	limit := defaultLimit
+
+	var p *period
+
+	fields := strings.Fields(strings.ToLower(args))
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		switch {
+		case field == "all":
+			p = nil
+		case rollingPeriods[field].duration > 0:
+			p = &period{
+				title: rollingPeriods[field].title,
+				since: now.Add(-rollingPeriods[field].duration),
+				until: now,
+			}
+		case yearPattern.MatchString(field):
+			year, _ := strconv.Atoi(field)
+			since := time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location())
+			p = &period{
+				title: field,
+				since: since,
+				until: since.AddDate(1, 0, 0),
+			}
+		case field == "since" || field == "seit":
+			if i+1 >= len(fields) {
+				return 0, nil, errInvalidArguments
+			}
+
+			i++
+
+			since, err := parseDate(fields[i], now.Location())
+			if err != nil {
+				return 0, nil, errInvalidArguments
+			}
+
+			p = &period{
+				title: "seit " + since.Format("02.01.2006"),
+				since: since,
+				until: now,
+			}
+		default:
+			value, err := strconv.Atoi(field)
+			if err != nil || value <= 0 {
+				return 0, nil, errInvalidArguments
+			}
+
+			limit = value
+		}
+	}
+
+	return limit, p, nil
+}
+
+func parseDate(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02.01.2006"} {
+		if date, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse date %q", value)
+}
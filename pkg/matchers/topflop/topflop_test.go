@@ -0,0 +1,96 @@
+package topflop_test
+
+import (
+	"testing"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/topflop"
+	"github.com/br0-space/bot/pkg/repo/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcher_ProcessAllTime(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().FindTops(int64(789), 10).Return([]interfaces.Plusplus{{ChatID: 789, Name: "kaffee", Value: 42}}, nil)
+	repo.EXPECT().FindFlops(int64(789), 5).Return([]interfaces.Plusplus{{ChatID: 789, Name: "tee_", Value: -3}}, nil)
+
+	replies, err := topflop.MakeMatcher(repo).Process(telegramclient.TestWebhookMessage("/top"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "```\n   42 | kaffee\n```", replies[0].Text)
+
+	replies, err = topflop.MakeMatcher(repo).Process(telegramclient.TestWebhookMessage("/FLOP@bot 5 all"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "```\n   -3 | tee\\_\n```", replies[0].Text)
+}
+
+var periodTests = []struct {
+	in    string
+	limit int
+	since time.Time
+	until time.Time
+}{
+	{"/top week", 10, time.Now().Add(-7 * 24 * time.Hour), time.Now()},
+	{"/top month 3", 3, time.Now().Add(-30 * 24 * time.Hour), time.Now()},
+	{"/top 2026", 10, time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local), time.Date(2027, 1, 1, 0, 0, 0, 0, time.Local)},
+	{"/top 20 since 2026-01-01", 20, time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local), time.Now()},
+	{"/top seit 15.03.2026 5", 5, time.Date(2026, 3, 15, 0, 0, 0, 0, time.Local), time.Now()},
+}
+
+func TestMatcher_ProcessPeriods(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range periodTests {
+		repo := mocks.NewPlusplusRepoInterface(t)
+		repo.EXPECT().FindTopsInPeriod(
+			int64(789),
+			mock.MatchedBy(func(since time.Time) bool { return since.Sub(tt.since).Abs() < time.Minute }),
+			mock.MatchedBy(func(until time.Time) bool { return until.Sub(tt.until).Abs() < time.Minute }),
+			tt.limit,
+		).Return([]interfaces.PlusplusPeriodStruct{{Name: "kaffee", Delta: 5, Value: 42}}, nil)
+
+		replies, err := topflop.MakeMatcher(repo).Process(telegramclient.TestWebhookMessage(tt.in))
+		require.NoError(t, err, tt.in)
+		require.Len(t, replies, 1, tt.in)
+		assert.Contains(t, replies[0].Text, "\n  +/- gesamt | Begriff\n   +5     42 | kaffee\n", tt.in)
+	}
+}
+
+func TestMatcher_ProcessFlopPeriod(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().FindFlopsInPeriod(int64(789), mock.Anything, mock.Anything, 10).Return([]interfaces.PlusplusPeriodStruct{
+		{Name: "montag", Delta: -4, Value: -20},
+		{Name: "regen", Delta: -1, Value: 3},
+	}, nil).Once()
+	repo.EXPECT().FindFlopsInPeriod(int64(789), mock.Anything, mock.Anything, 10).Return(nil, nil).Once()
+
+	replies, err := topflop.MakeMatcher(repo).Process(telegramclient.TestWebhookMessage("/flop 2026"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "```\nFlop 10, 2026\n  +/- gesamt | Begriff\n   -4    -20 | montag\n   -1      3 | regen\n```", replies[0].Text)
+
+	replies, err = topflop.MakeMatcher(repo).Process(telegramclient.TestWebhookMessage("/flop day"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "In diesem Zeitraum (letzte 24 Stunden) wurde nichts geplust oder geminust.", replies[0].Text)
+}
+
+func TestMatcher_ProcessInvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{"/top foo", "/top since", "/top since gestern", "/top 0", "/top -5"} {
+		replies, err := topflop.MakeMatcher(mocks.NewPlusplusRepoInterface(t)).Process(telegramclient.TestWebhookMessage(in))
+		require.NoError(t, err, in)
+		require.Len(t, replies, 1, in)
+		assert.Contains(t, replies[0].Text, "Das habe ich nicht verstanden.", in)
+	}
+}
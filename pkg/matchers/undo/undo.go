@@ -0,0 +1,81 @@
+package undo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	matcher "github.com/br0-space/bot-matcher"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const identifier = "undo"
+
+var pattern = regexp.MustCompile(`(?i)^/(undo)(@\w+)?$`)
+
+var help = []matcher.HelpStruct{{
+	Command:     `undo`,
+	Description: `Macht deine letzte Nachricht mit ++ oder -- rückgängig, solange sie nicht zu lange her ist.`,
+	Usage:       `/undo`,
+	Example:     `/undo`,
+}}
+
+const (
+	disabledTemplate = "Rückgängig machen ist in diesem Chat nicht möglich."
+	nothingTemplate  = "Du hast in den letzten %d Minuten nichts geplust oder geminust."
+	undoneTemplate   = "Rückgängig gemacht: %s ist wieder auf %d."
+)
+
+type Matcher struct {
+	matcher.Matcher
+
+	repo   interfaces.PlusplusRepoInterface
+	window time.Duration
+}
+
+func MakeMatcher(
+	repo interfaces.PlusplusRepoInterface,
+	cfg interfaces.PlusplusConfigStruct,
+) Matcher {
+	return Matcher{
+		Matcher: matcher.MakeMatcher(identifier, pattern, help),
+		repo:    repo,
+		window:  cfg.UndoWindow,
+	}
+}
+
+func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	if match := m.CommandMatch(messageIn); match == nil {
+		return nil, errors.New("message does not match")
+	}
+
+	if m.window <= 0 {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(disabledTemplate, messageIn.ID),
+		}, nil
+	}
+
+	corrections, err := m.repo.Undo(messageIn.Chat.ID, messageIn.From.ID, time.Now().Add(-m.window))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(corrections) == 0 {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(fmt.Sprintf(nothingTemplate, int(math.Ceil(m.window.Minutes()))), messageIn.ID),
+		}, nil
+	}
+
+	lines := make([]string, 0, len(corrections))
+	for _, correction := range corrections {
+		lines = append(lines, fmt.Sprintf(undoneTemplate, correction.Name, correction.NewValue))
+	}
+
+	return []telegramclient.MessageStruct{
+		telegramclient.Reply(strings.Join(lines, "\n"), messageIn.ID),
+	}, nil
+}
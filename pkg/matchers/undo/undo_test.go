@@ -0,0 +1,58 @@
+package undo_test
+
+import (
+	"testing"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/undo"
+	"github.com/br0-space/bot/pkg/repo/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var cfg = interfaces.PlusplusConfigStruct{AllowSelfIncrement: false, Cooldown: 0, MaxDelta: 0, UndoWindow: 10 * time.Minute}
+
+func TestMatcher_Process(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().
+		Undo(int64(789), int64(456), mock.MatchedBy(func(since time.Time) bool {
+			return time.Since(since) >= 10*time.Minute && time.Since(since) < 11*time.Minute
+		})).
+		Return([]interfaces.PlusplusCorrection{
+			{ChatID: 789, UserID: 456, Action: interfaces.PlusplusActionUndo, Name: "foo", OldValue: -5, NewValue: 2},
+			{ChatID: 789, UserID: 456, Action: interfaces.PlusplusActionUndo, Name: "bar", OldValue: 1, NewValue: 0},
+		}, nil)
+
+	replies, err := undo.MakeMatcher(repo, cfg).Process(telegramclient.TestWebhookMessage("/undo"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Rückgängig gemacht: foo ist wieder auf 2.\nRückgängig gemacht: bar ist wieder auf 0.", replies[0].Text)
+	assert.Equal(t, int64(123), replies[0].ReplyToMessageID)
+}
+
+func TestMatcher_ProcessWithoutChanges(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().Undo(int64(789), int64(456), mock.Anything).Return([]interfaces.PlusplusCorrection{}, nil)
+
+	replies, err := undo.MakeMatcher(repo, cfg).Process(telegramclient.TestWebhookMessage("/undo"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Du hast in den letzten 10 Minuten nichts geplust oder geminust.", replies[0].Text)
+}
+
+func TestMatcher_ProcessDisabled(t *testing.T) {
+	t.Parallel()
+
+	replies, err := undo.MakeMatcher(mocks.NewPlusplusRepoInterface(t), interfaces.PlusplusConfigStruct{}).
+		Process(telegramclient.TestWebhookMessage("/undo"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Rückgängig machen ist in diesem Chat nicht möglich.", replies[0].Text)
+}
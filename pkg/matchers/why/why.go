@@ -0,0 +1,97 @@
+package why
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	matcher "github.com/br0-space/bot-matcher"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const (
+	identifier = "why"
+	limit      = 10
+)
+
+var pattern = regexp.MustCompile(`(?i)^/(why)(@\w+)?($| )(.*)`)
+
+var help = []matcher.HelpStruct{{
+	Command:     `why`,
+	Description: `Zeigt die letzten Gründe an, aus denen ein Begriff geplust oder geminust wurde.`,
+	Usage:       `/why <Begriff>`,
+	Example:     `/why alice`,
+}}
+
+const (
+	template      = "```\n%s\n```"
+	usageTemplate = "Bitte gib einen Begriff an, z.B. /why alice"
+	emptyTemplate = "Für %s wurde noch kein Grund angegeben."
+)
+
+type Matcher struct {
+	matcher.Matcher
+
+	repo interfaces.PlusplusRepoInterface
+	loc  *time.Location
+}
+
+func MakeMatcher(
+	repo interfaces.PlusplusRepoInterface,
+) Matcher {
+	return Matcher{
+		Matcher: matcher.MakeMatcher(identifier, pattern, help),
+		repo:    repo,
+		loc:     time.Local,
+	}
+}
+
+func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	match := m.CommandMatch(messageIn)
+	if match == nil {
+		return nil, errors.New("message does not match")
+	}
+
+	name := strings.ToLower(strings.TrimSpace(match[3]))
+	if name == "" {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(usageTemplate, messageIn.ID),
+		}, nil
+	}
+
+	reasons, err := m.repo.FindReasons(messageIn.Chat.ID, name, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reasons) == 0 {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(fmt.Sprintf(emptyTemplate, name), messageIn.ID),
+		}, nil
+	}
+
+	lines := make([]string, 0, len(reasons)+1)
+	lines = append(lines, "Gründe für "+telegramclient.EscapeMarkdown(name))
+
+	for _, reason := range reasons {
+		username := reason.Username
+		if username == "" {
+			username = fmt.Sprintf("#%d", reason.UserID)
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"%s %+d %s: %s",
+			reason.CreatedAt.In(m.loc).Format("02.01. 15:04"),
+			reason.Delta,
+			telegramclient.EscapeMarkdown(username),
+			telegramclient.EscapeMarkdown(reason.Reason),
+		))
+	}
+
+	return []telegramclient.MessageStruct{
+		telegramclient.MarkdownMessage(fmt.Sprintf(template, strings.Join(lines, "\n"))),
+	}, nil
+}
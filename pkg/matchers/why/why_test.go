@@ -0,0 +1,49 @@
+package why_test
+
+import (
+	"testing"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/why"
+	"github.com/br0-space/bot/pkg/repo/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcher_Process(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().FindReasons(int64(789), "alice", 10).Return([]interfaces.PlusplusChangeStruct{
+		{UserID: 10, Username: "bob", Delta: 1, Reason: "fixing the build", CreatedAt: time.Date(2026, 1, 7, 10, 30, 0, 0, time.Local)},
+		{UserID: 20, Username: "", Delta: -2, Reason: "breaking it (again)", CreatedAt: time.Date(2026, 1, 6, 9, 0, 0, 0, time.Local)},
+	}, nil)
+
+	replies, err := why.MakeMatcher(repo).Process(telegramclient.TestWebhookMessage("/why Alice"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(
+		t,
+		"```\nGründe für alice\n07.01. 10:30 +1 bob: fixing the build\n06.01. 09:00 -2 \\#20: breaking it \\(again\\)\n```",
+		replies[0].Text,
+	)
+}
+
+func TestMatcher_ProcessWithoutReasons(t *testing.T) {
+	t.Parallel()
+
+	repo := mocks.NewPlusplusRepoInterface(t)
+	repo.EXPECT().FindReasons(int64(789), "alice", 10).Return(nil, nil)
+
+	replies, err := why.MakeMatcher(repo).Process(telegramclient.TestWebhookMessage("/why alice"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Für alice wurde noch kein Grund angegeben.", replies[0].Text)
+
+	replies, err = why.MakeMatcher(repo).Process(telegramclient.TestWebhookMessage("/why"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Bitte gib einen Begriff an, z.B. /why alice", replies[0].Text)
+}
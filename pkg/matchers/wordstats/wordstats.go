@@ -0,0 +1,191 @@
+package wordstats
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	matcher "github.com/br0-space/bot-matcher"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const (
+	identifier    = "wordstats"
+	defaultPeriod = "week"
+	maxBarLength  = 16
+	hoursPerDay   = 24
+)
+
+var pattern = regexp.MustCompile(`(?i)^/(wordstats)(@\w+)?($| )(day|week|month|all)?`)
+
+var help = []matcher.HelpStruct{{
+	Command:     `wordstats`,
+	Description: `Zeigt an, wer wie viel schreibt und zu welchen Zeiten im Chat am meisten los ist.`,
+	Usage:       `/wordstats <optional: day, week, month oder all>`,
+	Example:     `/wordstats month`,
+}}
+
+var periods = map[string]struct {
+	title    string
+	duration time.Duration
+}{
+	"day":   {"letzte 24 Stunden", 24 * time.Hour},
+	"week":  {"letzte 7 Tage", 7 * 24 * time.Hour},
+	"month": {"letzte 30 Tage", 30 * 24 * time.Hour},
+	"all":   {"gesamt", 0},
+}
+
+// weekdays in German order, starting with Monday.
+var weekdays = []struct {
+	day   time.Weekday
+	label string
+}{
+	{time.Monday, "Mo"},
+	{time.Tuesday, "Di"},
+	{time.Wednesday, "Mi"},
+	{time.Thursday, "Do"},
+	{time.Friday, "Fr"},
+	{time.Saturday, "Sa"},
+	{time.Sunday, "So"},
+}
+
+const (
+	template      = "```\n%s\n```"
+	emptyTemplate = "In diesem Zeitraum (%s) wurde hier nichts geschrieben."
+)
+
+type Matcher struct {
+	matcher.Matcher
+
+	repo interfaces.MessageStatsRepoInterface
+	loc  *time.Location
+}
+
+func MakeMatcher(
+	repo interfaces.MessageStatsRepoInterface,
+) Matcher {
+	return Matcher{
+		Matcher: matcher.MakeMatcher(identifier, pattern, help),
+		repo:    repo,
+		loc:     time.Local,
+	}
+}
+
+func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	match := m.CommandMatch(messageIn)
+	if match == nil {
+		return nil, errors.New("message does not match")
+	}
+
+	period := strings.ToLower(match[3])
+	if period == "" {
+		period = defaultPeriod
+	}
+
+	var since time.Time
+	if periods[period].duration > 0 {
+		since = time.Now().Add(-periods[period].duration)
+	}
+
+	counts, err := m.repo.GetWordCounts(messageIn.Chat.ID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(counts) == 0 {
+		return []telegramclient.MessageStruct{
+			telegramclient.Reply(fmt.Sprintf(emptyTemplate, periods[period].title), messageIn.ID),
+		}, nil
+	}
+
+	hours, err := m.repo.GetHourHistogram(messageIn.Chat.ID, since, m.loc)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := m.repo.GetWeekdayHistogram(messageIn.Chat.ID, since, m.loc)
+	if err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf(
+		template,
+		strings.Join([]string{
+			"Wortstatistik, " + periods[period].title,
+			formatCounts(counts),
+			formatHours(hours),
+			formatWeekdays(days),
+		}, "\n\n"),
+	)
+
+	return []telegramclient.MessageStruct{
+		telegramclient.MarkdownMessage(text),
+	}, nil
+}
+
+func formatCounts(counts []interfaces.MessageStatsWordCountStruct) string {
+	lines := make([]string, 0, len(counts)+1)
+	lines = append(lines, "Wörter Nachr.    Ø User")
+
+	for _, count := range counts {
+		username := count.Username
+		if username == "" {
+			username = fmt.Sprintf("#%d", count.UserID)
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"%6d %6d %4.1f %s",
+			count.Words,
+			count.Messages,
+			float64(count.Words)/float64(max(count.Messages, 1)),
+			telegramclient.EscapeMarkdown(username),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatHours(hours []interfaces.MessageStatsBucketStruct) string {
+	lines := make([]string, 0, hoursPerDay+1)
+	lines = append(lines, "Nachrichten nach Uhrzeit")
+
+	maxMessages := maxMessages(hours)
+	for _, hour := range hours {
+		lines = append(lines, formatBar(fmt.Sprintf("%02d", hour.Bucket), hour.Messages, maxMessages))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatWeekdays(days []interfaces.MessageStatsBucketStruct) string {
+	lines := make([]string, 0, len(weekdays)+1)
+	lines = append(lines, "Nachrichten nach Wochentag")
+
+	maxMessages := maxMessages(days)
+	for _, weekday := range weekdays {
+		lines = append(lines, formatBar(weekday.label, days[weekday.day].Messages, maxMessages))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatBar(label string, value int, maxValue int) string {
+	length := 0
+	if maxValue > 0 {
+		length = (value*maxBarLength + maxValue - 1) / maxValue
+	}
+
+	return fmt.Sprintf("%s %-*s %d", label, maxBarLength, strings.Repeat("█", length), value)
+}
+
+func maxMessages(buckets []interfaces.MessageStatsBucketStruct) int {
+	maxValue := 0
+	for _, bucket := range buckets {
+		maxValue = max(maxValue, bucket.Messages)
+	}
+
+	return maxValue
+}
@@ -0,0 +1,146 @@
+package wordstats_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/matchers/wordstats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	counts []interfaces.MessageStatsWordCountStruct
+	since  *time.Time
+}
+
+func (r *fakeRepo) InsertMessageStats(_ int64, _ int64, _ int) error {
+	return nil
+}
+
+func (r *fakeRepo) GetKnownUserIDs(_ int64) ([]int64, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) GetWordCounts(_ int64, since time.Time) ([]interfaces.MessageStatsWordCountStruct, error) {
+	r.since = &since
+
+	return r.counts, nil
+}
+
+func (r *fakeRepo) GetHourHistogram(_ int64, _ time.Time, _ *time.Location) ([]interfaces.MessageStatsBucketStruct, error) {
+	hours := make([]interfaces.MessageStatsBucketStruct, 24)
+	for i := range hours {
+		hours[i].Bucket = i
+	}
+
+	hours[9].Messages = 2
+	hours[21].Messages = 4
+
+	return hours, nil
+}
+
+func (r *fakeRepo) GetWeekdayHistogram(_ int64, _ time.Time, _ *time.Location) ([]interfaces.MessageStatsBucketStruct, error) {
+	days := make([]interfaces.MessageStatsBucketStruct, 7)
+	for i := range days {
+		days[i].Bucket = i
+	}
+
+	days[time.Sunday].Messages = 6
+
+	return days, nil
+}
+
+func (r *fakeRepo) GetActivityHeatmap(
+	_ int64,
+	_ int64,
+	_ time.Time,
+	_ *time.Location,
+) ([]interfaces.MessageStatsHeatmapCellStruct, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) GetDailyActivity(_ int64, _ int64, _ time.Time, _ *time.Location) ([]interfaces.MessageStatsDayStruct, error) {
+	return nil, nil
+}
+
+func provideRepo() *fakeRepo {
+	return &fakeRepo{
+		counts: []interfaces.MessageStatsWordCountStruct{
+			{UserID: 1, Username: "alice_", Messages: 4, Words: 30},
+			{UserID: 2, Username: "", Messages: 2, Words: 5},
+		},
+		since: nil,
+	}
+}
+
+var periodTests = []struct {
+	in       string
+	duration time.Duration
+}{
+	{"/wordstats", 7 * 24 * time.Hour},
+	{"/wordstats day", 24 * time.Hour},
+	{"/wordstats@bot week", 7 * 24 * time.Hour},
+	{"/wordstats MONTH", 30 * 24 * time.Hour},
+	{"/wordstats all", 0},
+}
+
+func TestMatcher_ProcessPeriods(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range periodTests {
+		repo := provideRepo()
+
+		_, err := wordstats.MakeMatcher(repo).Process(telegramclient.TestWebhookMessage(tt.in))
+		require.NoError(t, err, tt.in)
+		require.NotNil(t, repo.since, tt.in)
+
+		if tt.duration == 0 {
+			assert.True(t, repo.since.IsZero(), tt.in)
+		} else {
+			assert.WithinDuration(t, time.Now().Add(-tt.duration), *repo.since, time.Minute, tt.in)
+		}
+	}
+}
+
+func TestMatcher_Process(t *testing.T) {
+	t.Parallel()
+
+	replies, err := wordstats.MakeMatcher(provideRepo()).Process(telegramclient.TestWebhookMessage("/wordstats"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+
+	text := replies[0].Text
+	assert.True(t, strings.HasPrefix(text, "```\nWortstatistik, letzte 7 Tage\n"))
+	assert.Contains(t, text, "    30      4  7.5 alice\\_\n")
+	assert.Contains(t, text, "     5      2  2.5 \\#2\n")
+	assert.Contains(t, text, "\n09 ████████         2\n")
+	assert.Contains(t, text, "\n21 ████████████████ 4\n")
+	assert.Contains(t, text, "\nMo                  0\n")
+	assert.Contains(t, text, "\nSo ████████████████ 6\n```")
+}
+
+func TestMatcher_ProcessEmpty(t *testing.T) {
+	t.Parallel()
+
+	repo := provideRepo()
+	repo.counts = nil
+
+	replies, err := wordstats.MakeMatcher(repo).Process(telegramclient.TestWebhookMessage("/wordstats day"))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "In diesem Zeitraum (letzte 24 Stunden) wurde hier nichts geschrieben.", replies[0].Text)
+}
+
+func TestMatcher_ProcessNoMatch(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{"", "wordstats", "/wordstatsx", " /wordstats"} {
+		replies, err := wordstats.MakeMatcher(provideRepo()).Process(telegramclient.TestWebhookMessage(in))
+		require.Error(t, err, in)
+		assert.Nil(t, replies, in)
+	}
+}
@@ -0,0 +1,34 @@
+package matchersettings
+
+import (
+	"errors"
+
+	matcher "github.com/br0-space/bot-matcher"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+// Matcher wraps another matcher and skips it in chats where it was disabled.
+type Matcher struct {
+	matcher.Interface
+
+	settings interfaces.MatcherSettingsServiceInterface
+}
+
+func Wrap(
+	m matcher.Interface,
+	settings interfaces.MatcherSettingsServiceInterface,
+) Matcher {
+	return Matcher{
+		Interface: m,
+		settings:  settings,
+	}
+}
+
+func (m Matcher) Process(messageIn telegramclient.WebhookMessageStruct) ([]telegramclient.MessageStruct, error) {
+	if !m.settings.IsEnabled(messageIn.Chat.ID, m.Identifier()) {
+		return nil, errors.New("matcher is disabled in this chat")
+	}
+
+	return m.Interface.Process(messageIn)
+}
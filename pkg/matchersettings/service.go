@@ -0,0 +1,94 @@
+package matchersettings
+
+import (
+	"sync"
+
+	logger "github.com/br0-space/bot-logger"
+	"github.com/br0-space/bot/interfaces"
+)
+
+// Service keeps track of the matchers that were disabled in a chat.
+// Settings are loaded from the database once per chat and cached afterwards.
+type Service struct {
+	log      logger.Interface
+	repo     interfaces.MatcherSettingRepoInterface
+	lock     sync.RWMutex
+	disabled map[int64]map[string]bool
+}
+
+func NewService(repo interfaces.MatcherSettingRepoInterface) *Service {
+	return &Service{
+		log:      logger.New(),
+		repo:     repo,
+		lock:     sync.RWMutex{},
+		disabled: make(map[int64]map[string]bool),
+	}
+}
+
+// IsEnabled returns false if the matcher was disabled in the given chat.
+// If the settings cannot be loaded, all matchers are considered enabled.
+func (s *Service) IsEnabled(chatID int64, identifier string) bool {
+	if err := s.load(chatID); err != nil {
+		s.log.Error("Unable to load matcher settings for chat", chatID, err)
+
+		return true
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return !s.disabled[chatID][identifier]
+}
+
+func (s *Service) SetEnabled(chatID int64, identifier string, enabled bool) error {
+	if err := s.load(chatID); err != nil {
+		return err
+	}
+
+	if err := s.repo.SetEnabled(chatID, identifier, enabled); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if enabled {
+		delete(s.disabled[chatID], identifier)
+	} else {
+		s.disabled[chatID][identifier] = true
+	}
+
+	return nil
+}
+
+// load reads the settings of a chat from the database unless they are cached already.
+func (s *Service) load(chatID int64) error {
+	s.lock.RLock()
+	_, ok := s.disabled[chatID]
+	s.lock.RUnlock()
+
+	if ok {
+		return nil
+	}
+
+	identifiers, err := s.repo.GetDisabledIdentifiers(chatID)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.disabled[chatID]; ok {
+		return nil
+	}
+
+	disabled := make(map[string]bool, len(identifiers))
+	for _, identifier := range identifiers {
+		disabled[identifier] = true
+	}
+
+	s.disabled[chatID] = disabled
+
+	return nil
+}
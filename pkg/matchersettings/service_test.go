@@ -0,0 +1,84 @@
+package matchersettings_test
+
+import (
+	"errors"
+	"testing"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/pkg/matchers/ping"
+	"github.com/br0-space/bot/pkg/matchersettings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	disabled map[int64][]string
+	loads    int
+	err      error
+}
+
+func (r *fakeRepo) SetEnabled(_ int64, _ string, _ bool) error {
+	return r.err
+}
+
+func (r *fakeRepo) GetDisabledIdentifiers(chatID int64) ([]string, error) {
+	r.loads++
+
+	return r.disabled[chatID], r.err
+}
+
+func TestService_IsEnabled(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeRepo{disabled: map[int64][]string{1: {"ping"}}}
+	service := matchersettings.NewService(repo)
+
+	assert.False(t, service.IsEnabled(1, "ping"))
+	assert.True(t, service.IsEnabled(1, "roll"))
+	assert.True(t, service.IsEnabled(2, "ping"))
+	assert.Equal(t, 2, repo.loads)
+}
+
+func TestService_SetEnabled(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeRepo{disabled: map[int64][]string{}}
+	service := matchersettings.NewService(repo)
+
+	require.NoError(t, service.SetEnabled(1, "ping", false))
+	assert.False(t, service.IsEnabled(1, "ping"))
+	assert.True(t, service.IsEnabled(2, "ping"))
+
+	require.NoError(t, service.SetEnabled(1, "ping", true))
+	assert.True(t, service.IsEnabled(1, "ping"))
+	assert.Equal(t, 2, repo.loads)
+}
+
+func TestService_RepoError(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeRepo{err: errors.New("database is gone")}
+	service := matchersettings.NewService(repo)
+
+	assert.True(t, service.IsEnabled(1, "ping"))
+	require.Error(t, service.SetEnabled(1, "ping", false))
+}
+
+func TestMatcher_Process(t *testing.T) {
+	t.Parallel()
+
+	service := matchersettings.NewService(&fakeRepo{disabled: map[int64][]string{}})
+	m := matchersettings.Wrap(ping.MakeMatcher(), service)
+	msg := telegramclient.TestWebhookMessage("/ping")
+
+	replies, err := m.Process(msg)
+	require.NoError(t, err)
+	assert.Len(t, replies, 1)
+
+	require.NoError(t, service.SetEnabled(msg.Chat.ID, "ping", false))
+
+	replies, err = m.Process(msg)
+	require.Error(t, err)
+	assert.Empty(t, replies)
+	assert.Equal(t, "ping", m.Identifier())
+}
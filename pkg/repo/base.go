@@ -29,7 +29,3 @@ func (r *BaseRepo) TableName() string {
 
 	return stmt.Table
 }
-
-func (r *BaseRepo) Migrate() error {
-	return r.tx.AutoMigrate(r.Model())
-}
@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const dialectPostgres = "postgres"
+
+// hourOfDayExpr returns an SQL expression for the hour of the day (0-23) of a
+// timestamp column, shifted to the current UTC offset of the given location.
+// The offset is applied as a whole, so records from the other side of a
+// daylight saving time change are off by an hour.
+func hourOfDayExpr(tx *gorm.DB, column string, loc *time.Location) string {
+	offset := utcOffset(loc)
+
+	if tx.Dialector.Name() == dialectPostgres {
+		return fmt.Sprintf(`CAST(EXTRACT(HOUR FROM (%s AT TIME ZONE 'UTC') + INTERVAL '%d seconds') AS INTEGER)`, column, offset)
+	}
+
+	return fmt.Sprintf(`CAST(strftime('%%H', %s, '%+d seconds') AS INTEGER)`, column, offset)
+}
+
+// dayOfWeekExpr returns an SQL expression for the day of the week (0 = Sunday)
+// of a timestamp column, shifted like hourOfDayExpr.
+func dayOfWeekExpr(tx *gorm.DB, column string, loc *time.Location) string {
+	offset := utcOffset(loc)
+
+	if tx.Dialector.Name() == dialectPostgres {
+		return fmt.Sprintf(`CAST(EXTRACT(DOW FROM (%s AT TIME ZONE 'UTC') + INTERVAL '%d seconds') AS INTEGER)`, column, offset)
+	}
+
+	return fmt.Sprintf(`CAST(strftime('%%w', %s, '%+d seconds') AS INTEGER)`, column, offset)
+}
+
+// dateExpr returns an SQL expression for the calendar day (2006-01-02) of a
+// timestamp column, shifted like hourOfDayExpr.
+func dateExpr(tx *gorm.DB, column string, loc *time.Location) string {
+	offset := utcOffset(loc)
+
+	if tx.Dialector.Name() == dialectPostgres {
+		return fmt.Sprintf(`TO_CHAR((%s AT TIME ZONE 'UTC') + INTERVAL '%d seconds', 'YYYY-MM-DD')`, column, offset)
+	}
+
+	return fmt.Sprintf(`date(%s, '%+d seconds')`, column, offset)
+}
+
+func utcOffset(loc *time.Location) int {
+	if loc == nil {
+		return 0
+	}
+
+	_, offset := time.Now().In(loc).Zone()
+
+	return offset
+}
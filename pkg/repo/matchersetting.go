@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"github.com/br0-space/bot/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MatcherSettingRepo struct {
+	BaseRepo
+}
+
+func NewMatcherSettingRepo(tx *gorm.DB) *MatcherSettingRepo {
+	return &MatcherSettingRepo{
+		BaseRepo: NewBaseRepo(
+			tx,
+			&interfaces.MatcherSetting{
+				ChatID:     0,
+				Identifier: "",
+				Enabled:    false,
+			},
+		),
+	}
+}
+
+func (r MatcherSettingRepo) SetEnabled(chatID int64, identifier string, enabled bool) error {
+	return r.tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "chat_id"}, {Name: "identifier"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"enabled": enabled,
+		}),
+	}).Create(&interfaces.MatcherSetting{
+		ChatID:     chatID,
+		Identifier: identifier,
+		Enabled:    enabled,
+	}).Error
+}
+
+func (r MatcherSettingRepo) GetDisabledIdentifiers(chatID int64) ([]string, error) {
+	var identifiers []string
+	if err := r.tx.
+		Model(r.Model()).
+		Where("chat_id = ? AND enabled = ?", chatID, false).
+		Order("identifier asc").
+		Pluck("identifier", &identifiers).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return identifiers, nil
+}
@@ -0,0 +1,33 @@
+package repo_test
+
+import (
+	"testing"
+
+	"github.com/br0-space/bot/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcherSettingRepo_SetEnabled(t *testing.T) {
+	t.Parallel()
+
+	r := repo.NewMatcherSettingRepo(provideDatabase(t))
+
+	require.NoError(t, r.SetEnabled(1, "goodmorning", false))
+	require.NoError(t, r.SetEnabled(1, "buzzwords", false))
+	require.NoError(t, r.SetEnabled(2, "ping", false))
+
+	disabled, err := r.GetDisabledIdentifiers(1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"buzzwords", "goodmorning"}, disabled)
+
+	require.NoError(t, r.SetEnabled(1, "buzzwords", true))
+
+	disabled, err = r.GetDisabledIdentifiers(1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"goodmorning"}, disabled)
+
+	disabled, err = r.GetDisabledIdentifiers(3)
+	require.NoError(t, err)
+	assert.Empty(t, disabled)
+}
@@ -7,6 +7,11 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	hoursPerDay = 24
+	daysPerWeek = 7
+)
+
 type MessageStatsRepo struct {
 	BaseRepo
 }
@@ -16,6 +21,7 @@ func NewMessageStatsRepo(tx *gorm.DB) *MessageStatsRepo {
 		BaseRepo: NewBaseRepo(
 			tx,
 			&interfaces.MessageStats{
+				ChatID: 0,
 				UserID: 0,
 				Time:   time.Time{},
 				Words:  0,
@@ -24,37 +30,164 @@ func NewMessageStatsRepo(tx *gorm.DB) *MessageStatsRepo {
 	}
 }
 
-func (r MessageStatsRepo) InsertMessageStats(userID int64, words int) error {
+func (r MessageStatsRepo) InsertMessageStats(chatID int64, userID int64, words int) error {
 	return r.tx.Create(&interfaces.MessageStats{
+		ChatID: chatID,
 		UserID: userID,
-		Time:   time.Now(),
+		Time:   time.Now().UTC(),
 		Words:  words,
 	}).Error
 }
 
-func (r MessageStatsRepo) GetKnownUserIDs() ([]int64, error) {
+func (r MessageStatsRepo) GetKnownUserIDs(chatID int64) ([]int64, error) {
 	var userIDs []int64
 
 	err := r.tx.
-		Select("DISTINCT user_id").
-		Where("user_id != 0").
-		Find(&userIDs).
+		Model(r.Model()).
+		Distinct().
+		Where("chat_id = ? AND user_id != 0", chatID).
+		Pluck("user_id", &userIDs).
 		Error
 
 	return userIDs, err
 }
 
-func (r MessageStatsRepo) GetWordCounts() ([]interfaces.MessageStatsWordCountStruct, error) {
+func (r MessageStatsRepo) GetWordCounts(chatID int64, since time.Time) ([]interfaces.MessageStatsWordCountStruct, error) {
 	var records []interfaces.MessageStatsWordCountStruct
 
-	err := r.tx.Model(&interfaces.MessageStats{}).
-		Joins("UserStats").
-		Select(`"message_stats".user_id, "UserStats".username, count("message_stats".words) as words`).
-		Where(`"message_stats"user_id != 0`).
-		Group(`"message_stats".user_id, "UserStats".id, "UserStats".username`).
-		Order(`count("message_stats".words) desc`).
+	err := r.scope(chatID, since).
+		Select(`
+			m.user_id,
+			COALESCE(s.username, '') as username,
+			COUNT(*) as messages,
+			SUM(m.words) as words
+		`).
+		Joins("LEFT JOIN stats s ON m.chat_id = s.chat_id AND m.user_id = s.user_id").
+		Where("m.user_id != 0").
+		Group("m.user_id, s.username").
+		Order("words DESC, messages DESC").
 		Scan(&records).
 		Error
 
 	return records, err
 }
+
+func (r MessageStatsRepo) GetHourHistogram(
+	chatID int64,
+	since time.Time,
+	loc *time.Location,
+) ([]interfaces.MessageStatsBucketStruct, error) {
+	return r.histogram(chatID, since, hourOfDayExpr(r.tx, "m.time", loc), hoursPerDay)
+}
+
+func (r MessageStatsRepo) GetWeekdayHistogram(
+	chatID int64,
+	since time.Time,
+	loc *time.Location,
+) ([]interfaces.MessageStatsBucketStruct, error) {
+	return r.histogram(chatID, since, dayOfWeekExpr(r.tx, "m.time", loc), daysPerWeek)
+}
+
+func (r MessageStatsRepo) GetActivityHeatmap(
+	chatID int64,
+	userID int64,
+	since time.Time,
+	loc *time.Location,
+) ([]interfaces.MessageStatsHeatmapCellStruct, error) {
+	var records []interfaces.MessageStatsHeatmapCellStruct
+
+	if err := r.userScope(chatID, userID, since).
+		Select(dayOfWeekExpr(r.tx, "m.time", loc) + " as weekday, " +
+			hourOfDayExpr(r.tx, "m.time", loc) + " as hour, " +
+			"COUNT(*) as messages, SUM(m.words) as words").
+		Group("weekday, hour").
+		Scan(&records).
+		Error; err != nil {
+		return nil, err
+	}
+
+	cells := make([]interfaces.MessageStatsHeatmapCellStruct, daysPerWeek*hoursPerDay)
+	for i := range cells {
+		cells[i].Weekday = time.Weekday(i / hoursPerDay)
+		cells[i].Hour = i % hoursPerDay
+	}
+
+	for _, record := range records {
+		if record.Weekday >= 0 && int(record.Weekday) < daysPerWeek && record.Hour >= 0 && record.Hour < hoursPerDay {
+			cells[int(record.Weekday)*hoursPerDay+record.Hour] = record
+		}
+	}
+
+	return cells, nil
+}
+
+func (r MessageStatsRepo) GetDailyActivity(
+	chatID int64,
+	userID int64,
+	since time.Time,
+	loc *time.Location,
+) ([]interfaces.MessageStatsDayStruct, error) {
+	var records []interfaces.MessageStatsDayStruct
+
+	err := r.userScope(chatID, userID, since).
+		Select(dateExpr(r.tx, "m.time", loc) + " as day, COUNT(*) as messages, SUM(m.words) as words").
+		Group("day").
+		Order("day").
+		Scan(&records).
+		Error
+
+	return records, err
+}
+
+// scope selects the messages of a chat since the given time, a zero time means all time.
+func (r MessageStatsRepo) scope(chatID int64, since time.Time) *gorm.DB {
+	query := r.tx.
+		Table("message_stats m").
+		Where("m.chat_id = ? AND m.deleted_at IS NULL", chatID)
+	if !since.IsZero() {
+		query = query.Where("m.time >= ?", since.UTC())
+	}
+
+	return query
+}
+
+// userScope narrows scope down to a single user unless the user ID is 0.
+func (r MessageStatsRepo) userScope(chatID int64, userID int64, since time.Time) *gorm.DB {
+	query := r.scope(chatID, since)
+	if userID != 0 {
+		query = query.Where("m.user_id = ?", userID)
+	}
+
+	return query
+}
+
+// histogram groups messages by the given bucket expression and fills in empty buckets.
+func (r MessageStatsRepo) histogram(
+	chatID int64,
+	since time.Time,
+	bucketExpr string,
+	size int,
+) ([]interfaces.MessageStatsBucketStruct, error) {
+	var records []interfaces.MessageStatsBucketStruct
+
+	if err := r.scope(chatID, since).
+		Select(bucketExpr + " as bucket, COUNT(*) as messages, SUM(m.words) as words").
+		Group("bucket").
+		Scan(&records).
+		Error; err != nil {
+		return nil, err
+	}
+
+	buckets := make([]interfaces.MessageStatsBucketStruct, size)
+	for i := range buckets {
+		buckets[i].Bucket = i
+	}
+
+	for _, record := range records {
+		if record.Bucket >= 0 && record.Bucket < size {
+			buckets[record.Bucket] = record
+		}
+	}
+
+	return buckets, nil
+}
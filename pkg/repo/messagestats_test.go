@@ -0,0 +1,146 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// provideMessageStats creates a chat with two known users and messages at fixed times.
+func provideMessageStats(t *testing.T) (*repo.MessageStatsRepo, time.Time) {
+	t.Helper()
+
+	conn := provideDatabase(t)
+	statsRepo := repo.NewUserStatsRepo(conn)
+	messageStatsRepo := repo.NewMessageStatsRepo(conn)
+
+	require.NoError(t, statsRepo.UpdateStats(1, 10, "alice"))
+	require.NoError(t, statsRepo.UpdateStats(1, 20, "bob"))
+
+	// Wednesday, 2026-01-07 10:30 UTC
+	now := time.Date(2026, 1, 7, 10, 30, 0, 0, time.UTC)
+
+	insertMessageStats(t, conn, 1, 10, now.Add(-time.Hour), 5)
+	insertMessageStats(t, conn, 1, 10, now.Add(-48*time.Hour), 7)
+	insertMessageStats(t, conn, 1, 20, now, 3)
+	insertMessageStats(t, conn, 1, 20, now.Add(-time.Minute), 4)
+	insertMessageStats(t, conn, 1, 30, now, 1)
+	insertMessageStats(t, conn, 2, 10, now, 100)
+
+	return messageStatsRepo, now
+}
+
+func insertMessageStats(t *testing.T, conn *gorm.DB, chatID int64, userID int64, at time.Time, words int) {
+	t.Helper()
+
+	require.NoError(t, conn.Create(&interfaces.MessageStats{
+		ChatID: chatID,
+		UserID: userID,
+		Time:   at,
+		Words:  words,
+	}).Error)
+}
+
+func TestMessageStatsRepo_GetWordCounts(t *testing.T) {
+	t.Parallel()
+
+	r, now := provideMessageStats(t)
+
+	counts, err := r.GetWordCounts(1, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.MessageStatsWordCountStruct{
+		{UserID: 10, Username: "alice", Messages: 2, Words: 12},
+		{UserID: 20, Username: "bob", Messages: 2, Words: 7},
+		{UserID: 30, Username: "", Messages: 1, Words: 1},
+	}, counts)
+
+	counts, err = r.GetWordCounts(1, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.MessageStatsWordCountStruct{
+		{UserID: 20, Username: "bob", Messages: 2, Words: 7},
+		{UserID: 10, Username: "alice", Messages: 1, Words: 5},
+		{UserID: 30, Username: "", Messages: 1, Words: 1},
+	}, counts)
+
+	counts, err = r.GetWordCounts(3, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestMessageStatsRepo_GetHourHistogram(t *testing.T) {
+	t.Parallel()
+
+	r, _ := provideMessageStats(t)
+
+	hours, err := r.GetHourHistogram(1, time.Time{}, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, hours, 24)
+	assert.Equal(t, interfaces.MessageStatsBucketStruct{Bucket: 9, Messages: 1, Words: 5}, hours[9])
+	assert.Equal(t, interfaces.MessageStatsBucketStruct{Bucket: 10, Messages: 4, Words: 15}, hours[10])
+	assert.Equal(t, interfaces.MessageStatsBucketStruct{Bucket: 11, Messages: 0, Words: 0}, hours[11])
+
+	hours, err = r.GetHourHistogram(1, time.Time{}, time.FixedZone("UTC+2", 2*60*60))
+	require.NoError(t, err)
+	assert.Equal(t, 1, hours[11].Messages)
+	assert.Equal(t, 4, hours[12].Messages)
+}
+
+func TestMessageStatsRepo_GetWeekdayHistogram(t *testing.T) {
+	t.Parallel()
+
+	r, now := provideMessageStats(t)
+
+	days, err := r.GetWeekdayHistogram(1, time.Time{}, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, days, 7)
+	assert.Equal(t, interfaces.MessageStatsBucketStruct{Bucket: int(time.Monday), Messages: 1, Words: 7}, days[time.Monday])
+	assert.Equal(t, interfaces.MessageStatsBucketStruct{Bucket: int(time.Wednesday), Messages: 4, Words: 13}, days[time.Wednesday])
+
+	days, err = r.GetWeekdayHistogram(1, now.Add(-24*time.Hour), time.FixedZone("UTC-11", -11*60*60))
+	require.NoError(t, err)
+	assert.Equal(t, 4, days[time.Tuesday].Messages)
+	assert.Equal(t, 0, days[time.Wednesday].Messages)
+}
+
+func TestMessageStatsRepo_GetActivityHeatmap(t *testing.T) {
+	t.Parallel()
+
+	r, _ := provideMessageStats(t)
+
+	cells, err := r.GetActivityHeatmap(1, 0, time.Time{}, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, cells, 7*24)
+	assert.Equal(t, interfaces.MessageStatsHeatmapCellStruct{Weekday: time.Wednesday, Hour: 10, Messages: 3, Words: 8}, cells[3*24+10])
+	assert.Equal(t, interfaces.MessageStatsHeatmapCellStruct{Weekday: time.Wednesday, Hour: 9, Messages: 1, Words: 5}, cells[3*24+9])
+	assert.Equal(t, interfaces.MessageStatsHeatmapCellStruct{Weekday: time.Monday, Hour: 10, Messages: 1, Words: 7}, cells[1*24+10])
+	assert.Equal(t, interfaces.MessageStatsHeatmapCellStruct{Weekday: time.Sunday, Hour: 0, Messages: 0, Words: 0}, cells[0])
+
+	cells, err = r.GetActivityHeatmap(1, 20, time.Time{}, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cells[3*24+10].Messages)
+	assert.Equal(t, 0, cells[3*24+9].Messages)
+}
+
+func TestMessageStatsRepo_GetDailyActivity(t *testing.T) {
+	t.Parallel()
+
+	r, now := provideMessageStats(t)
+
+	days, err := r.GetDailyActivity(1, 0, time.Time{}, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.MessageStatsDayStruct{
+		{Day: "2026-01-05", Messages: 1, Words: 7},
+		{Day: "2026-01-07", Messages: 4, Words: 13},
+	}, days)
+
+	days, err = r.GetDailyActivity(1, 10, now.Add(-24*time.Hour), time.FixedZone("UTC+14", 14*60*60))
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.MessageStatsDayStruct{
+		{Day: "2026-01-07", Messages: 1, Words: 5},
+	}, days)
+}
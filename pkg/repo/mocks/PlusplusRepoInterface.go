@@ -5,6 +5,8 @@ package mocks
 import (
 	interfaces "github.com/br0-space/bot/interfaces"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // PlusplusRepoInterface is an autogenerated mock type for the PlusplusRepoInterface type
@@ -20,13 +22,150 @@ func (_m *PlusplusRepoInterface) EXPECT() *PlusplusRepoInterface_Expecter {
 	return &PlusplusRepoInterface_Expecter{mock: &_m.Mock}
 }
 
-// FindFlops provides a mock function with given fields: limit
-func (_m *PlusplusRepoInterface) FindFlops(limit int) ([]interfaces.Plusplus, error) {
-	ret := _m.Called(limit)
+// Delete provides a mock function with given fields: chatID, userID, name
+func (_m *PlusplusRepoInterface) Delete(chatID int64, userID int64, name string) (interfaces.PlusplusCorrection, error) {
+	ret := _m.Called(chatID, userID, name)
+
+	var r0 interfaces.PlusplusCorrection
+	if rf, ok := ret.Get(0).(func(int64, int64, string) interfaces.PlusplusCorrection); ok {
+		r0 = rf(chatID, userID, name)
+	} else {
+		r0 = ret.Get(0).(interfaces.PlusplusCorrection)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int64, string) error); ok {
+		r1 = rf(chatID, userID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type PlusplusRepoInterface_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - chatID int64
+//   - userID int64
+//   - name string
+func (_e *PlusplusRepoInterface_Expecter) Delete(chatID interface{}, userID interface{}, name interface{}) *PlusplusRepoInterface_Delete_Call {
+	return &PlusplusRepoInterface_Delete_Call{Call: _e.mock.On("Delete", chatID, userID, name)}
+}
+
+func (_c *PlusplusRepoInterface_Delete_Call) Run(run func(chatID int64, userID int64, name string)) *PlusplusRepoInterface_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_Delete_Call) Return(_a0 interfaces.PlusplusCorrection, _a1 error) *PlusplusRepoInterface_Delete_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// FindAliases provides a mock function with given fields: chatID
+func (_m *PlusplusRepoInterface) FindAliases(chatID int64) ([]interfaces.PlusplusAlias, error) {
+	ret := _m.Called(chatID)
+
+	var r0 []interfaces.PlusplusAlias
+	if rf, ok := ret.Get(0).(func(int64) []interfaces.PlusplusAlias); ok {
+		r0 = rf(chatID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interfaces.PlusplusAlias)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(chatID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_FindAliases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAliases'
+type PlusplusRepoInterface_FindAliases_Call struct {
+	*mock.Call
+}
+
+// FindAliases is a helper method to define mock.On call
+//   - chatID int64
+func (_e *PlusplusRepoInterface_Expecter) FindAliases(chatID interface{}) *PlusplusRepoInterface_FindAliases_Call {
+	return &PlusplusRepoInterface_FindAliases_Call{Call: _e.mock.On("FindAliases", chatID)}
+}
+
+func (_c *PlusplusRepoInterface_FindAliases_Call) Run(run func(chatID int64)) *PlusplusRepoInterface_FindAliases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_FindAliases_Call) Return(_a0 []interfaces.PlusplusAlias, _a1 error) *PlusplusRepoInterface_FindAliases_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// FindByName provides a mock function with given fields: chatID, name
+func (_m *PlusplusRepoInterface) FindByName(chatID int64, name string) (interfaces.Plusplus, error) {
+	ret := _m.Called(chatID, name)
+
+	var r0 interfaces.Plusplus
+	if rf, ok := ret.Get(0).(func(int64, string) interfaces.Plusplus); ok {
+		r0 = rf(chatID, name)
+	} else {
+		r0 = ret.Get(0).(interfaces.Plusplus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
+		r1 = rf(chatID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_FindByName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByName'
+type PlusplusRepoInterface_FindByName_Call struct {
+	*mock.Call
+}
+
+// FindByName is a helper method to define mock.On call
+//   - chatID int64
+//   - name string
+func (_e *PlusplusRepoInterface_Expecter) FindByName(chatID interface{}, name interface{}) *PlusplusRepoInterface_FindByName_Call {
+	return &PlusplusRepoInterface_FindByName_Call{Call: _e.mock.On("FindByName", chatID, name)}
+}
+
+func (_c *PlusplusRepoInterface_FindByName_Call) Run(run func(chatID int64, name string)) *PlusplusRepoInterface_FindByName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_FindByName_Call) Return(_a0 interfaces.Plusplus, _a1 error) *PlusplusRepoInterface_FindByName_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// FindFlops provides a mock function with given fields: chatID, limit
+func (_m *PlusplusRepoInterface) FindFlops(chatID int64, limit int) ([]interfaces.Plusplus, error) {
+	ret := _m.Called(chatID, limit)
 
 	var r0 []interfaces.Plusplus
-	if rf, ok := ret.Get(0).(func(int) []interfaces.Plusplus); ok {
-		r0 = rf(limit)
+	if rf, ok := ret.Get(0).(func(int64, int) []interfaces.Plusplus); ok {
+		r0 = rf(chatID, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]interfaces.Plusplus)
@@ -34,8 +173,8 @@ func (_m *PlusplusRepoInterface) FindFlops(limit int) ([]interfaces.Plusplus, er
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(limit)
+	if rf, ok := ret.Get(1).(func(int64, int) error); ok {
+		r1 = rf(chatID, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -49,14 +188,15 @@ type PlusplusRepoInterface_FindFlops_Call struct {
 }
 
 // FindFlops is a helper method to define mock.On call
+//   - chatID int64
 //   - limit int
-func (_e *PlusplusRepoInterface_Expecter) FindFlops(limit interface{}) *PlusplusRepoInterface_FindFlops_Call {
-	return &PlusplusRepoInterface_FindFlops_Call{Call: _e.mock.On("FindFlops", limit)}
+func (_e *PlusplusRepoInterface_Expecter) FindFlops(chatID interface{}, limit interface{}) *PlusplusRepoInterface_FindFlops_Call {
+	return &PlusplusRepoInterface_FindFlops_Call{Call: _e.mock.On("FindFlops", chatID, limit)}
 }
 
-func (_c *PlusplusRepoInterface_FindFlops_Call) Run(run func(limit int)) *PlusplusRepoInterface_FindFlops_Call {
+func (_c *PlusplusRepoInterface_FindFlops_Call) Run(run func(chatID int64, limit int)) *PlusplusRepoInterface_FindFlops_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int))
+		run(args[0].(int64), args[1].(int))
 	})
 	return _c
 }
@@ -66,13 +206,206 @@ func (_c *PlusplusRepoInterface_FindFlops_Call) Return(_a0 []interfaces.Plusplus
 	return _c
 }
 
-// FindTops provides a mock function with given fields: limit
-func (_m *PlusplusRepoInterface) FindTops(limit int) ([]interfaces.Plusplus, error) {
-	ret := _m.Called(limit)
+// FindFlopsInPeriod provides a mock function with given fields: chatID, since, until, limit
+func (_m *PlusplusRepoInterface) FindFlopsInPeriod(chatID int64, since time.Time, until time.Time, limit int) ([]interfaces.PlusplusPeriodStruct, error) {
+	ret := _m.Called(chatID, since, until, limit)
+
+	var r0 []interfaces.PlusplusPeriodStruct
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time, int) []interfaces.PlusplusPeriodStruct); ok {
+		r0 = rf(chatID, since, until, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interfaces.PlusplusPeriodStruct)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, time.Time, time.Time, int) error); ok {
+		r1 = rf(chatID, since, until, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_FindFlopsInPeriod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindFlopsInPeriod'
+type PlusplusRepoInterface_FindFlopsInPeriod_Call struct {
+	*mock.Call
+}
+
+// FindFlopsInPeriod is a helper method to define mock.On call
+//   - chatID int64
+//   - since time.Time
+//   - until time.Time
+//   - limit int
+func (_e *PlusplusRepoInterface_Expecter) FindFlopsInPeriod(chatID interface{}, since interface{}, until interface{}, limit interface{}) *PlusplusRepoInterface_FindFlopsInPeriod_Call {
+	return &PlusplusRepoInterface_FindFlopsInPeriod_Call{Call: _e.mock.On("FindFlopsInPeriod", chatID, since, until, limit)}
+}
+
+func (_c *PlusplusRepoInterface_FindFlopsInPeriod_Call) Run(run func(chatID int64, since time.Time, until time.Time, limit int)) *PlusplusRepoInterface_FindFlopsInPeriod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(time.Time), args[2].(time.Time), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_FindFlopsInPeriod_Call) Return(_a0 []interfaces.PlusplusPeriodStruct, _a1 error) *PlusplusRepoInterface_FindFlopsInPeriod_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// FindReasons provides a mock function with given fields: chatID, name, limit
+func (_m *PlusplusRepoInterface) FindReasons(chatID int64, name string, limit int) ([]interfaces.PlusplusChangeStruct, error) {
+	ret := _m.Called(chatID, name, limit)
+
+	var r0 []interfaces.PlusplusChangeStruct
+	if rf, ok := ret.Get(0).(func(int64, string, int) []interfaces.PlusplusChangeStruct); ok {
+		r0 = rf(chatID, name, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interfaces.PlusplusChangeStruct)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, string, int) error); ok {
+		r1 = rf(chatID, name, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_FindReasons_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindReasons'
+type PlusplusRepoInterface_FindReasons_Call struct {
+	*mock.Call
+}
+
+// FindReasons is a helper method to define mock.On call
+//   - chatID int64
+//   - name string
+//   - limit int
+func (_e *PlusplusRepoInterface_Expecter) FindReasons(chatID interface{}, name interface{}, limit interface{}) *PlusplusRepoInterface_FindReasons_Call {
+	return &PlusplusRepoInterface_FindReasons_Call{Call: _e.mock.On("FindReasons", chatID, name, limit)}
+}
+
+func (_c *PlusplusRepoInterface_FindReasons_Call) Run(run func(chatID int64, name string, limit int)) *PlusplusRepoInterface_FindReasons_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_FindReasons_Call) Return(_a0 []interfaces.PlusplusChangeStruct, _a1 error) *PlusplusRepoInterface_FindReasons_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// FindRecentChanges provides a mock function with given fields: chatID, name, limit
+func (_m *PlusplusRepoInterface) FindRecentChanges(chatID int64, name string, limit int) ([]interfaces.PlusplusChangeStruct, error) {
+	ret := _m.Called(chatID, name, limit)
+
+	var r0 []interfaces.PlusplusChangeStruct
+	if rf, ok := ret.Get(0).(func(int64, string, int) []interfaces.PlusplusChangeStruct); ok {
+		r0 = rf(chatID, name, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interfaces.PlusplusChangeStruct)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, string, int) error); ok {
+		r1 = rf(chatID, name, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_FindRecentChanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindRecentChanges'
+type PlusplusRepoInterface_FindRecentChanges_Call struct {
+	*mock.Call
+}
+
+// FindRecentChanges is a helper method to define mock.On call
+//   - chatID int64
+//   - name string
+//   - limit int
+func (_e *PlusplusRepoInterface_Expecter) FindRecentChanges(chatID interface{}, name interface{}, limit interface{}) *PlusplusRepoInterface_FindRecentChanges_Call {
+	return &PlusplusRepoInterface_FindRecentChanges_Call{Call: _e.mock.On("FindRecentChanges", chatID, name, limit)}
+}
+
+func (_c *PlusplusRepoInterface_FindRecentChanges_Call) Run(run func(chatID int64, name string, limit int)) *PlusplusRepoInterface_FindRecentChanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_FindRecentChanges_Call) Return(_a0 []interfaces.PlusplusChangeStruct, _a1 error) *PlusplusRepoInterface_FindRecentChanges_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// FindTopGivers provides a mock function with given fields: chatID, name, limit
+func (_m *PlusplusRepoInterface) FindTopGivers(chatID int64, name string, limit int) ([]interfaces.PlusplusGiverStruct, error) {
+	ret := _m.Called(chatID, name, limit)
+
+	var r0 []interfaces.PlusplusGiverStruct
+	if rf, ok := ret.Get(0).(func(int64, string, int) []interfaces.PlusplusGiverStruct); ok {
+		r0 = rf(chatID, name, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interfaces.PlusplusGiverStruct)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, string, int) error); ok {
+		r1 = rf(chatID, name, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_FindTopGivers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTopGivers'
+type PlusplusRepoInterface_FindTopGivers_Call struct {
+	*mock.Call
+}
+
+// FindTopGivers is a helper method to define mock.On call
+//   - chatID int64
+//   - name string
+//   - limit int
+func (_e *PlusplusRepoInterface_Expecter) FindTopGivers(chatID interface{}, name interface{}, limit interface{}) *PlusplusRepoInterface_FindTopGivers_Call {
+	return &PlusplusRepoInterface_FindTopGivers_Call{Call: _e.mock.On("FindTopGivers", chatID, name, limit)}
+}
+
+func (_c *PlusplusRepoInterface_FindTopGivers_Call) Run(run func(chatID int64, name string, limit int)) *PlusplusRepoInterface_FindTopGivers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_FindTopGivers_Call) Return(_a0 []interfaces.PlusplusGiverStruct, _a1 error) *PlusplusRepoInterface_FindTopGivers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// FindTops provides a mock function with given fields: chatID, limit
+func (_m *PlusplusRepoInterface) FindTops(chatID int64, limit int) ([]interfaces.Plusplus, error) {
+	ret := _m.Called(chatID, limit)
 
 	var r0 []interfaces.Plusplus
-	if rf, ok := ret.Get(0).(func(int) []interfaces.Plusplus); ok {
-		r0 = rf(limit)
+	if rf, ok := ret.Get(0).(func(int64, int) []interfaces.Plusplus); ok {
+		r0 = rf(chatID, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]interfaces.Plusplus)
@@ -80,8 +413,8 @@ func (_m *PlusplusRepoInterface) FindTops(limit int) ([]interfaces.Plusplus, err
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(limit)
+	if rf, ok := ret.Get(1).(func(int64, int) error); ok {
+		r1 = rf(chatID, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -95,14 +428,15 @@ type PlusplusRepoInterface_FindTops_Call struct {
 }
 
 // FindTops is a helper method to define mock.On call
+//   - chatID int64
 //   - limit int
-func (_e *PlusplusRepoInterface_Expecter) FindTops(limit interface{}) *PlusplusRepoInterface_FindTops_Call {
-	return &PlusplusRepoInterface_FindTops_Call{Call: _e.mock.On("FindTops", limit)}
+func (_e *PlusplusRepoInterface_Expecter) FindTops(chatID interface{}, limit interface{}) *PlusplusRepoInterface_FindTops_Call {
+	return &PlusplusRepoInterface_FindTops_Call{Call: _e.mock.On("FindTops", chatID, limit)}
 }
 
-func (_c *PlusplusRepoInterface_FindTops_Call) Run(run func(limit int)) *PlusplusRepoInterface_FindTops_Call {
+func (_c *PlusplusRepoInterface_FindTops_Call) Run(run func(chatID int64, limit int)) *PlusplusRepoInterface_FindTops_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int))
+		run(args[0].(int64), args[1].(int))
 	})
 	return _c
 }
@@ -112,20 +446,164 @@ func (_c *PlusplusRepoInterface_FindTops_Call) Return(_a0 []interfaces.Plusplus,
 	return _c
 }
 
-// Increment provides a mock function with given fields: name, increment
-func (_m *PlusplusRepoInterface) Increment(name string, increment int) (int, error) {
-	ret := _m.Called(name, increment)
+// FindTopsInPeriod provides a mock function with given fields: chatID, since, until, limit
+func (_m *PlusplusRepoInterface) FindTopsInPeriod(chatID int64, since time.Time, until time.Time, limit int) ([]interfaces.PlusplusPeriodStruct, error) {
+	ret := _m.Called(chatID, since, until, limit)
+
+	var r0 []interfaces.PlusplusPeriodStruct
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time, int) []interfaces.PlusplusPeriodStruct); ok {
+		r0 = rf(chatID, since, until, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interfaces.PlusplusPeriodStruct)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, time.Time, time.Time, int) error); ok {
+		r1 = rf(chatID, since, until, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_FindTopsInPeriod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTopsInPeriod'
+type PlusplusRepoInterface_FindTopsInPeriod_Call struct {
+	*mock.Call
+}
+
+// FindTopsInPeriod is a helper method to define mock.On call
+//   - chatID int64
+//   - since time.Time
+//   - until time.Time
+//   - limit int
+func (_e *PlusplusRepoInterface_Expecter) FindTopsInPeriod(chatID interface{}, since interface{}, until interface{}, limit interface{}) *PlusplusRepoInterface_FindTopsInPeriod_Call {
+	return &PlusplusRepoInterface_FindTopsInPeriod_Call{Call: _e.mock.On("FindTopsInPeriod", chatID, since, until, limit)}
+}
+
+func (_c *PlusplusRepoInterface_FindTopsInPeriod_Call) Run(run func(chatID int64, since time.Time, until time.Time, limit int)) *PlusplusRepoInterface_FindTopsInPeriod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(time.Time), args[2].(time.Time), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_FindTopsInPeriod_Call) Return(_a0 []interfaces.PlusplusPeriodStruct, _a1 error) *PlusplusRepoInterface_FindTopsInPeriod_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetLastIncrementTime provides a mock function with given fields: chatID, userID, name
+func (_m *PlusplusRepoInterface) GetLastIncrementTime(chatID int64, userID int64, name string) (time.Time, error) {
+	ret := _m.Called(chatID, userID, name)
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(int64, int64, string) time.Time); ok {
+		r0 = rf(chatID, userID, name)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int64, string) error); ok {
+		r1 = rf(chatID, userID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_GetLastIncrementTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastIncrementTime'
+type PlusplusRepoInterface_GetLastIncrementTime_Call struct {
+	*mock.Call
+}
+
+// GetLastIncrementTime is a helper method to define mock.On call
+//   - chatID int64
+//   - userID int64
+//   - name string
+func (_e *PlusplusRepoInterface_Expecter) GetLastIncrementTime(chatID interface{}, userID interface{}, name interface{}) *PlusplusRepoInterface_GetLastIncrementTime_Call {
+	return &PlusplusRepoInterface_GetLastIncrementTime_Call{Call: _e.mock.On("GetLastIncrementTime", chatID, userID, name)}
+}
+
+func (_c *PlusplusRepoInterface_GetLastIncrementTime_Call) Run(run func(chatID int64, userID int64, name string)) *PlusplusRepoInterface_GetLastIncrementTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_GetLastIncrementTime_Call) Return(_a0 time.Time, _a1 error) *PlusplusRepoInterface_GetLastIncrementTime_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetWeeklyTrend provides a mock function with given fields: chatID, name, weeks, loc
+func (_m *PlusplusRepoInterface) GetWeeklyTrend(chatID int64, name string, weeks int, loc *time.Location) ([]interfaces.PlusplusWeekStruct, error) {
+	ret := _m.Called(chatID, name, weeks, loc)
+
+	var r0 []interfaces.PlusplusWeekStruct
+	if rf, ok := ret.Get(0).(func(int64, string, int, *time.Location) []interfaces.PlusplusWeekStruct); ok {
+		r0 = rf(chatID, name, weeks, loc)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interfaces.PlusplusWeekStruct)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, string, int, *time.Location) error); ok {
+		r1 = rf(chatID, name, weeks, loc)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_GetWeeklyTrend_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWeeklyTrend'
+type PlusplusRepoInterface_GetWeeklyTrend_Call struct {
+	*mock.Call
+}
+
+// GetWeeklyTrend is a helper method to define mock.On call
+//   - chatID int64
+//   - name string
+//   - weeks int
+//   - loc *time.Location
+func (_e *PlusplusRepoInterface_Expecter) GetWeeklyTrend(chatID interface{}, name interface{}, weeks interface{}, loc interface{}) *PlusplusRepoInterface_GetWeeklyTrend_Call {
+	return &PlusplusRepoInterface_GetWeeklyTrend_Call{Call: _e.mock.On("GetWeeklyTrend", chatID, name, weeks, loc)}
+}
+
+func (_c *PlusplusRepoInterface_GetWeeklyTrend_Call) Run(run func(chatID int64, name string, weeks int, loc *time.Location)) *PlusplusRepoInterface_GetWeeklyTrend_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(string), args[2].(int), args[3].(*time.Location))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_GetWeeklyTrend_Call) Return(_a0 []interfaces.PlusplusWeekStruct, _a1 error) *PlusplusRepoInterface_GetWeeklyTrend_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// Increment provides a mock function with given fields: chatID, userID, messageID, name, increment
+func (_m *PlusplusRepoInterface) Increment(chatID int64, userID int64, messageID int64, name string, increment int) (int, error) {
+	ret := _m.Called(chatID, userID, messageID, name, increment)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(string, int) int); ok {
-		r0 = rf(name, increment)
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, string, int) int); ok {
+		r0 = rf(chatID, userID, messageID, name, increment)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, int) error); ok {
-		r1 = rf(name, increment)
+	if rf, ok := ret.Get(1).(func(int64, int64, int64, string, int) error); ok {
+		r1 = rf(chatID, userID, messageID, name, increment)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -139,15 +617,18 @@ type PlusplusRepoInterface_Increment_Call struct {
 }
 
 // Increment is a helper method to define mock.On call
+//   - chatID int64
+//   - userID int64
+//   - messageID int64
 //   - name string
 //   - increment int
-func (_e *PlusplusRepoInterface_Expecter) Increment(name interface{}, increment interface{}) *PlusplusRepoInterface_Increment_Call {
-	return &PlusplusRepoInterface_Increment_Call{Call: _e.mock.On("Increment", name, increment)}
+func (_e *PlusplusRepoInterface_Expecter) Increment(chatID interface{}, userID interface{}, messageID interface{}, name interface{}, increment interface{}) *PlusplusRepoInterface_Increment_Call {
+	return &PlusplusRepoInterface_Increment_Call{Call: _e.mock.On("Increment", chatID, userID, messageID, name, increment)}
 }
 
-func (_c *PlusplusRepoInterface_Increment_Call) Run(run func(name string, increment int)) *PlusplusRepoInterface_Increment_Call {
+func (_c *PlusplusRepoInterface_Increment_Call) Run(run func(chatID int64, userID int64, messageID int64, name string, increment int)) *PlusplusRepoInterface_Increment_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(int))
+		run(args[0].(int64), args[1].(int64), args[2].(int64), args[3].(string), args[4].(int))
 	})
 	return _c
 }
@@ -157,6 +638,300 @@ func (_c *PlusplusRepoInterface_Increment_Call) Return(_a0 int, _a1 error) *Plus
 	return _c
 }
 
+// IncrementWithReason provides a mock function with given fields: chatID, userID, messageID, name, increment, reason
+func (_m *PlusplusRepoInterface) IncrementWithReason(chatID int64, userID int64, messageID int64, name string, increment int, reason string) (int, error) {
+	ret := _m.Called(chatID, userID, messageID, name, increment, reason)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, string, int, string) int); ok {
+		r0 = rf(chatID, userID, messageID, name, increment, reason)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int64, int64, string, int, string) error); ok {
+		r1 = rf(chatID, userID, messageID, name, increment, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_IncrementWithReason_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrementWithReason'
+type PlusplusRepoInterface_IncrementWithReason_Call struct {
+	*mock.Call
+}
+
+// IncrementWithReason is a helper method to define mock.On call
+//   - chatID int64
+//   - userID int64
+//   - messageID int64
+//   - name string
+//   - increment int
+//   - reason string
+func (_e *PlusplusRepoInterface_Expecter) IncrementWithReason(chatID interface{}, userID interface{}, messageID interface{}, name interface{}, increment interface{}, reason interface{}) *PlusplusRepoInterface_IncrementWithReason_Call {
+	return &PlusplusRepoInterface_IncrementWithReason_Call{Call: _e.mock.On("IncrementWithReason", chatID, userID, messageID, name, increment, reason)}
+}
+
+func (_c *PlusplusRepoInterface_IncrementWithReason_Call) Run(run func(chatID int64, userID int64, messageID int64, name string, increment int, reason string)) *PlusplusRepoInterface_IncrementWithReason_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(int64), args[2].(int64), args[3].(string), args[4].(int), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_IncrementWithReason_Call) Return(_a0 int, _a1 error) *PlusplusRepoInterface_IncrementWithReason_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// Merge provides a mock function with given fields: chatID, from, into
+func (_m *PlusplusRepoInterface) Merge(chatID int64, from string, into string) (string, int, error) {
+	ret := _m.Called(chatID, from, into)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(int64, string, string) string); ok {
+		r0 = rf(chatID, from, into)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(int64, string, string) int); ok {
+		r1 = rf(chatID, from, into)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(int64, string, string) error); ok {
+		r2 = rf(chatID, from, into)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PlusplusRepoInterface_Merge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Merge'
+type PlusplusRepoInterface_Merge_Call struct {
+	*mock.Call
+}
+
+// Merge is a helper method to define mock.On call
+//   - chatID int64
+//   - from string
+//   - into string
+func (_e *PlusplusRepoInterface_Expecter) Merge(chatID interface{}, from interface{}, into interface{}) *PlusplusRepoInterface_Merge_Call {
+	return &PlusplusRepoInterface_Merge_Call{Call: _e.mock.On("Merge", chatID, from, into)}
+}
+
+func (_c *PlusplusRepoInterface_Merge_Call) Run(run func(chatID int64, from string, into string)) *PlusplusRepoInterface_Merge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_Merge_Call) Return(_a0 string, _a1 int, _a2 error) *PlusplusRepoInterface_Merge_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+// ReviseMessage provides a mock function with given fields: chatID, userID, messageID, increments, rule
+func (_m *PlusplusRepoInterface) ReviseMessage(chatID int64, userID int64, messageID int64, increments []interfaces.PlusplusIncrementStruct, rule interfaces.PlusplusRevisionRule) ([]interfaces.PlusplusRevisionStruct, error) {
+	ret := _m.Called(chatID, userID, messageID, increments, rule)
+
+	var r0 []interfaces.PlusplusRevisionStruct
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, []interfaces.PlusplusIncrementStruct, interfaces.PlusplusRevisionRule) []interfaces.PlusplusRevisionStruct); ok {
+		r0 = rf(chatID, userID, messageID, increments, rule)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interfaces.PlusplusRevisionStruct)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int64, int64, []interfaces.PlusplusIncrementStruct, interfaces.PlusplusRevisionRule) error); ok {
+		r1 = rf(chatID, userID, messageID, increments, rule)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_ReviseMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReviseMessage'
+type PlusplusRepoInterface_ReviseMessage_Call struct {
+	*mock.Call
+}
+
+// ReviseMessage is a helper method to define mock.On call
+//   - chatID int64
+//   - userID int64
+//   - messageID int64
+//   - increments []interfaces.PlusplusIncrementStruct
+//   - rule interfaces.PlusplusRevisionRule
+func (_e *PlusplusRepoInterface_Expecter) ReviseMessage(chatID interface{}, userID interface{}, messageID interface{}, increments interface{}, rule interface{}) *PlusplusRepoInterface_ReviseMessage_Call {
+	return &PlusplusRepoInterface_ReviseMessage_Call{Call: _e.mock.On("ReviseMessage", chatID, userID, messageID, increments, rule)}
+}
+
+func (_c *PlusplusRepoInterface_ReviseMessage_Call) Run(run func(chatID int64, userID int64, messageID int64, increments []interfaces.PlusplusIncrementStruct, rule interfaces.PlusplusRevisionRule)) *PlusplusRepoInterface_ReviseMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(int64), args[2].(int64), args[3].([]interfaces.PlusplusIncrementStruct), args[4].(interfaces.PlusplusRevisionRule))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_ReviseMessage_Call) Return(_a0 []interfaces.PlusplusRevisionStruct, _a1 error) *PlusplusRepoInterface_ReviseMessage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// SetAlias provides a mock function with given fields: chatID, alias, name
+func (_m *PlusplusRepoInterface) SetAlias(chatID int64, alias string, name string) (string, error) {
+	ret := _m.Called(chatID, alias, name)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(int64, string, string) string); ok {
+		r0 = rf(chatID, alias, name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, string, string) error); ok {
+		r1 = rf(chatID, alias, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_SetAlias_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAlias'
+type PlusplusRepoInterface_SetAlias_Call struct {
+	*mock.Call
+}
+
+// SetAlias is a helper method to define mock.On call
+//   - chatID int64
+//   - alias string
+//   - name string
+func (_e *PlusplusRepoInterface_Expecter) SetAlias(chatID interface{}, alias interface{}, name interface{}) *PlusplusRepoInterface_SetAlias_Call {
+	return &PlusplusRepoInterface_SetAlias_Call{Call: _e.mock.On("SetAlias", chatID, alias, name)}
+}
+
+func (_c *PlusplusRepoInterface_SetAlias_Call) Run(run func(chatID int64, alias string, name string)) *PlusplusRepoInterface_SetAlias_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_SetAlias_Call) Return(_a0 string, _a1 error) *PlusplusRepoInterface_SetAlias_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// SetValue provides a mock function with given fields: chatID, userID, messageID, name, value
+func (_m *PlusplusRepoInterface) SetValue(chatID int64, userID int64, messageID int64, name string, value int) (interfaces.PlusplusCorrection, error) {
+	ret := _m.Called(chatID, userID, messageID, name, value)
+
+	var r0 interfaces.PlusplusCorrection
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, string, int) interfaces.PlusplusCorrection); ok {
+		r0 = rf(chatID, userID, messageID, name, value)
+	} else {
+		r0 = ret.Get(0).(interfaces.PlusplusCorrection)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int64, int64, string, int) error); ok {
+		r1 = rf(chatID, userID, messageID, name, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_SetValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetValue'
+type PlusplusRepoInterface_SetValue_Call struct {
+	*mock.Call
+}
+
+// SetValue is a helper method to define mock.On call
+//   - chatID int64
+//   - userID int64
+//   - messageID int64
+//   - name string
+//   - value int
+func (_e *PlusplusRepoInterface_Expecter) SetValue(chatID interface{}, userID interface{}, messageID interface{}, name interface{}, value interface{}) *PlusplusRepoInterface_SetValue_Call {
+	return &PlusplusRepoInterface_SetValue_Call{Call: _e.mock.On("SetValue", chatID, userID, messageID, name, value)}
+}
+
+func (_c *PlusplusRepoInterface_SetValue_Call) Run(run func(chatID int64, userID int64, messageID int64, name string, value int)) *PlusplusRepoInterface_SetValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(int64), args[2].(int64), args[3].(string), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_SetValue_Call) Return(_a0 interfaces.PlusplusCorrection, _a1 error) *PlusplusRepoInterface_SetValue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// Undo provides a mock function with given fields: chatID, userID, since
+func (_m *PlusplusRepoInterface) Undo(chatID int64, userID int64, since time.Time) ([]interfaces.PlusplusCorrection, error) {
+	ret := _m.Called(chatID, userID, since)
+
+	var r0 []interfaces.PlusplusCorrection
+	if rf, ok := ret.Get(0).(func(int64, int64, time.Time) []interfaces.PlusplusCorrection); ok {
+		r0 = rf(chatID, userID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interfaces.PlusplusCorrection)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int64, time.Time) error); ok {
+		r1 = rf(chatID, userID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlusplusRepoInterface_Undo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Undo'
+type PlusplusRepoInterface_Undo_Call struct {
+	*mock.Call
+}
+
+// Undo is a helper method to define mock.On call
+//   - chatID int64
+//   - userID int64
+//   - since time.Time
+func (_e *PlusplusRepoInterface_Expecter) Undo(chatID interface{}, userID interface{}, since interface{}) *PlusplusRepoInterface_Undo_Call {
+	return &PlusplusRepoInterface_Undo_Call{Call: _e.mock.On("Undo", chatID, userID, since)}
+}
+
+func (_c *PlusplusRepoInterface_Undo_Call) Run(run func(chatID int64, userID int64, since time.Time)) *PlusplusRepoInterface_Undo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *PlusplusRepoInterface_Undo_Call) Return(_a0 []interfaces.PlusplusCorrection, _a1 error) *PlusplusRepoInterface_Undo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
 type mockConstructorTestingTNewPlusplusRepoInterface interface {
 	mock.TestingT
 	Cleanup(func())
@@ -1,7 +1,12 @@
 package repo
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/br0-space/bot/interfaces"
 	"gorm.io/gorm"
@@ -19,62 +24,1022 @@ func NewPlusplusRepo(tx *gorm.DB) *PlusplusRepo {
 		BaseRepo: NewBaseRepo(
 			tx,
 			&interfaces.Plusplus{
-				Name:  "",
-				Value: 0,
+				ChatID: 0,
+				Name:   "",
+				Value:  0,
 			},
 		),
 	}
 }
 
-func (r PlusplusRepo) Increment(name string, increment int) (int, error) {
+func (r PlusplusRepo) Increment(chatID int64, userID int64, messageID int64, name string, increment int) (int, error) {
+	return r.increment(chatID, userID, messageID, name, increment, "", false)
+}
+
+func (r PlusplusRepo) IncrementWithReason(
+	chatID int64,
+	userID int64,
+	messageID int64,
+	name string,
+	increment int,
+	reason string,
+) (int, error) {
+	return r.increment(chatID, userID, messageID, name, increment, reason, true)
+}
+
+func (r PlusplusRepo) ReviseMessage(
+	chatID int64,
+	userID int64,
+	messageID int64,
+	increments []interfaces.PlusplusIncrementStruct,
+	rule interfaces.PlusplusRevisionRule,
+) ([]interfaces.PlusplusRevisionStruct, error) {
 	mutexPlusplus.Lock()
 	defer mutexPlusplus.Unlock()
 
-	if err := r.tx.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "name"}},
-		DoUpdates: clause.Assignments(map[string]any{
-			"value": gorm.Expr("plusplus.value + ?", increment),
-		}),
-	}).Create(&interfaces.Plusplus{
-		Name:  name,
-		Value: increment,
-	}).Error; err != nil {
+	revisions := make([]interfaces.PlusplusRevisionStruct, 0)
+
+	err := r.tx.Transaction(func(tx *gorm.DB) error {
+		revisable, err := isRevisable(tx, chatID, userID, messageID)
+		if err != nil || !revisable {
+			return err
+		}
+
+		names := make([]string, 0, len(increments))
+		desired := make(map[string]int, len(increments))
+		existing := make(map[string]int, len(increments))
+		reasons := make(map[string]string, len(increments))
+
+		seen := make(map[string]bool, len(increments))
+
+		add := func(changes map[string]int, name string, delta int) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+
+			changes[name] += delta
+		}
+
+		for _, increment := range increments {
+			canonical, err := resolveAlias(tx, chatID, increment.Name)
+			if err != nil {
+				return err
+			}
+
+			add(desired, canonical, increment.Increment)
+
+			if increment.Reason != "" {
+				reasons[canonical] = increment.Reason
+			}
+		}
+
+		var events []interfaces.PlusplusEvent
+		if err := tx.
+			Where("chat_id = ? AND giver_user_id = ? AND message_id = ? AND token = ?", chatID, userID, messageID, true).
+			Find(&events).
+			Error; err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			canonical, err := findCanonical(tx, chatID, event.Name)
+			if err != nil {
+				return err
+			}
+
+			add(existing, canonical, event.Delta)
+		}
+
+		for _, name := range names {
+			delta := desired[name] - existing[name]
+			if delta == 0 {
+				continue
+			}
+
+			// Taking back votes of the message is always allowed, everything else is a new vote
+			if !reverts(delta, existing[name]) {
+				last, err := lastIncrementTime(tx, chatID, userID, name)
+				if err != nil {
+					return err
+				}
+
+				displayed := name
+				if err := displayNames(tx, chatID, &displayed); err != nil {
+					return err
+				}
+
+				if rule(name, displayed, delta, desired[name], last) != "" {
+					continue
+				}
+			}
+
+			if err := tx.Create(&interfaces.PlusplusEvent{
+				ChatID:      chatID,
+				GiverUserID: userID,
+				Name:        name,
+				Delta:       delta,
+				MessageID:   messageID,
+				Reason:      reasons[name],
+				Token:       true,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := addValue(tx, chatID, name, delta); err != nil {
+				return err
+			}
+
+			record, err := findTerm(tx, chatID, name)
+			if err != nil {
+				return err
+			}
+
+			revisions = append(revisions, interfaces.PlusplusRevisionStruct{
+				Name:  name,
+				Delta: delta,
+				Value: record.Value,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	displayed := make([]*string, 0, len(revisions))
+	for i := range revisions {
+		displayed = append(displayed, &revisions[i].Name)
+	}
+
+	return revisions, displayNames(r.tx, chatID, displayed...)
+}
+
+// isRevisable checks if an edit may change what a message counted. Undone changes
+// stay undone, and changes from before tokens were recorded can't be told apart
+// from buzzwords, so revising them would count the tokens twice.
+func isRevisable(tx *gorm.DB, chatID int64, userID int64, messageID int64) (bool, error) {
+	var undone int64
+	if err := tx.
+		Unscoped().
+		Model(&interfaces.PlusplusEvent{}).
+		Where("chat_id = ? AND giver_user_id = ? AND message_id = ? AND deleted_at IS NOT NULL", chatID, userID, messageID).
+		Count(&undone).
+		Error; err != nil || undone > 0 {
+		return false, err
+	}
+
+	var counts []struct {
+		Token bool
+		Count int64
+	}
+	if err := tx.
+		Model(&interfaces.PlusplusEvent{}).
+		Select("token, COUNT(*) AS count").
+		Where("chat_id = ? AND giver_user_id = ? AND message_id = ?", chatID, userID, messageID).
+		Group("token").
+		Scan(&counts).
+		Error; err != nil {
+		return false, err
+	}
+
+	tokens, others := int64(0), int64(0)
+
+	for _, count := range counts {
+		if count.Token {
+			tokens += count.Count
+		} else {
+			others += count.Count
+		}
+	}
+
+	return tokens > 0 || others == 0, nil
+}
+
+// reverts checks if a change only takes back some or all of the existing change of a message.
+func reverts(delta int, existing int) bool {
+	if delta > 0 {
+		return existing < 0 && delta <= -existing
+	}
+
+	return existing > 0 && -delta <= existing
+}
+
+// increment changes the value of a term and records the change in the event log.
+func (r PlusplusRepo) increment(
+	chatID int64,
+	userID int64,
+	messageID int64,
+	name string,
+	increment int,
+	reason string,
+	token bool,
+) (int, error) {
+	mutexPlusplus.Lock()
+	defer mutexPlusplus.Unlock()
+
+	var value int
+
+	err := r.tx.Transaction(func(tx *gorm.DB) error {
+		canonical, err := resolveAlias(tx, chatID, name)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(&interfaces.PlusplusEvent{
+			ChatID:      chatID,
+			GiverUserID: userID,
+			Name:        canonical,
+			Delta:       increment,
+			MessageID:   messageID,
+			Reason:      reason,
+			Token:       token,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := addValue(tx, chatID, canonical, increment); err != nil {
+			return err
+		}
+
+		record, err := findTerm(tx, chatID, canonical)
+		if err != nil {
+			return err
+		}
+
+		value = record.Value
+
+		return nil
+	})
+	if err != nil {
 		return 0, err
 	}
 
+	return value, nil
+}
+
+func (r PlusplusRepo) FindTops(chatID int64, limit int) ([]interfaces.Plusplus, error) {
+	return r.ranking(chatID, limit, "DESC")
+}
+
+func (r PlusplusRepo) FindFlops(chatID int64, limit int) ([]interfaces.Plusplus, error) {
+	return r.ranking(chatID, limit, "ASC")
+}
+
+func (r PlusplusRepo) FindTopsInPeriod(
+	chatID int64,
+	since time.Time,
+	until time.Time,
+	limit int,
+) ([]interfaces.PlusplusPeriodStruct, error) {
+	return r.periodRanking(chatID, since, until, limit, "DESC")
+}
+
+func (r PlusplusRepo) FindFlopsInPeriod(
+	chatID int64,
+	since time.Time,
+	until time.Time,
+	limit int,
+) ([]interfaces.PlusplusPeriodStruct, error) {
+	return r.periodRanking(chatID, since, until, limit, "ASC")
+}
+
+func (r PlusplusRepo) FindByName(chatID int64, name string) (interfaces.Plusplus, error) {
+	canonical, err := resolveAlias(r.tx, chatID, name)
+	if err != nil {
+		return interfaces.Plusplus{}, err
+	}
+
+	record, err := findTerm(r.tx, chatID, canonical)
+	if err != nil {
+		return interfaces.Plusplus{}, err
+	}
+
+	return record, displayNames(r.tx, chatID, &record.Name)
+}
+
+func (r PlusplusRepo) SetAlias(chatID int64, alias string, name string) (string, error) {
+	mutexPlusplus.Lock()
+	defer mutexPlusplus.Unlock()
+
+	var canonical string
+
+	err := r.tx.Transaction(func(tx *gorm.DB) error {
+		var err error
+
+		canonical, err = setAlias(tx, chatID, alias, name)
+
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return canonical, displayNames(r.tx, chatID, &canonical)
+}
+
+func (r PlusplusRepo) Merge(chatID int64, from string, into string) (string, int, error) {
+	mutexPlusplus.Lock()
+	defer mutexPlusplus.Unlock()
+
 	var record interfaces.Plusplus
+
+	err := r.tx.Transaction(func(tx *gorm.DB) error {
+		from, err := resolveMention(tx, chatID, from)
+		if err != nil {
+			return err
+		}
+
+		canonical, err := setAlias(tx, chatID, from, into)
+		if err != nil {
+			return err
+		}
+
+		var source interfaces.Plusplus
+		if err := tx.
+			Where("chat_id = ? AND name = ?", chatID, from).
+			Limit(1).
+			Find(&source).
+			Error; err != nil {
+			return err
+		}
+
+		if source.ID != 0 {
+			if err := addValue(tx, chatID, canonical, source.Value); err != nil {
+				return err
+			}
+
+			// The unique index also covers soft deleted records
+			if err := tx.Unscoped().Delete(&source).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.
+			Table("plusplus_events").
+			Where("chat_id = ? AND name = ?", chatID, from).
+			Update("name", canonical).
+			Error; err != nil {
+			return err
+		}
+
+		record, err = findTerm(tx, chatID, canonical)
+
+		return err
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := displayNames(r.tx, chatID, &record.Name); err != nil {
+		return "", 0, err
+	}
+
+	return record.Name, record.Value, nil
+}
+
+func (r PlusplusRepo) FindAliases(chatID int64) ([]interfaces.PlusplusAlias, error) {
+	var records []interfaces.PlusplusAlias
+
+	err := r.tx.
+		Where("chat_id = ?", chatID).
+		Order("name, alias").
+		Find(&records).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]*string, 0, 2*len(records))
+	for i := range records {
+		names = append(names, &records[i].Alias, &records[i].Name)
+	}
+
+	return records, displayNames(r.tx, chatID, names...)
+}
+
+func (r PlusplusRepo) FindTopGivers(chatID int64, name string, limit int) ([]interfaces.PlusplusGiverStruct, error) {
+	canonical, err := resolveAlias(r.tx, chatID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []interfaces.PlusplusGiverStruct
+
+	err = r.events(chatID, canonical).
+		Select(`
+			e.giver_user_id as user_id,
+			COALESCE(s.username, '') as username,
+			SUM(CASE WHEN e.delta > 0 THEN e.delta ELSE 0 END) as plus,
+			SUM(CASE WHEN e.delta < 0 THEN -e.delta ELSE 0 END) as minus
+		`).
+		Group("e.giver_user_id, s.username").
+		Order("SUM(ABS(e.delta)) DESC, e.giver_user_id").
+		Limit(limit).
+		Scan(&records).
+		Error
+
+	return records, err
+}
+
+func (r PlusplusRepo) FindRecentChanges(chatID int64, name string, limit int) ([]interfaces.PlusplusChangeStruct, error) {
+	return r.changes(chatID, name, limit, false)
+}
+
+func (r PlusplusRepo) FindReasons(chatID int64, name string, limit int) ([]interfaces.PlusplusChangeStruct, error) {
+	return r.changes(chatID, name, limit, true)
+}
+
+func (r PlusplusRepo) GetLastIncrementTime(chatID int64, userID int64, name string) (time.Time, error) {
+	canonical, err := resolveAlias(r.tx, chatID, name)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return lastIncrementTime(r.tx, chatID, userID, canonical)
+}
+
+// lastIncrementTime returns when a user last changed a canonical term, a zero time if never.
+func lastIncrementTime(tx *gorm.DB, chatID int64, userID int64, canonical string) (time.Time, error) {
+	var event interfaces.PlusplusEvent
+
+	err := tx.
+		Where("chat_id = ? AND giver_user_id = ?", chatID, userID).
+		Where(termCondition("name", chatID, canonical)).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&event).
+		Error
+
+	return event.CreatedAt, err
+}
+
+func (r PlusplusRepo) Undo(chatID int64, userID int64, since time.Time) ([]interfaces.PlusplusCorrection, error) {
+	mutexPlusplus.Lock()
+	defer mutexPlusplus.Unlock()
+
+	corrections := make([]interfaces.PlusplusCorrection, 0)
+
+	err := r.tx.Transaction(func(tx *gorm.DB) error {
+		var last interfaces.PlusplusEvent
+		if err := tx.
+			Where("chat_id = ? AND giver_user_id = ? AND token = ? AND created_at >= ?", chatID, userID, true, since.UTC()).
+			Order("created_at DESC, id DESC").
+			Limit(1).
+			Find(&last).
+			Error; err != nil || last.ID == 0 {
+			return err
+		}
+
+		var events []interfaces.PlusplusEvent
+		if err := tx.
+			Where(
+				"chat_id = ? AND giver_user_id = ? AND message_id = ? AND token = ? AND created_at >= ?",
+				chatID, userID, last.MessageID, true, since.UTC(),
+			).
+			Order("id").
+			Find(&events).
+			Error; err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(events))
+		deltas := make(map[string]int, len(events))
+
+		for _, event := range events {
+			if _, exists := deltas[event.Name]; !exists {
+				names = append(names, event.Name)
+			}
+
+			deltas[event.Name] += event.Delta
+		}
+
+		if err := tx.Delete(&events).Error; err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			if err := addValue(tx, chatID, name, -deltas[name]); err != nil {
+				return err
+			}
+
+			canonical, err := findCanonical(tx, chatID, name)
+			if err != nil {
+				return err
+			}
+
+			record, err := findTerm(tx, chatID, canonical)
+			if err != nil {
+				return err
+			}
+
+			correction, err := r.correct(tx, chatID, userID, interfaces.PlusplusActionUndo, canonical, record.Value+deltas[name], record.Value)
+			if err != nil {
+				return err
+			}
+
+			corrections = append(corrections, correction)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]*string, 0, len(corrections))
+	for i := range corrections {
+		names = append(names, &corrections[i].Name)
+	}
+
+	return corrections, displayNames(r.tx, chatID, names...)
+}
+
+func (r PlusplusRepo) SetValue(
+	chatID int64,
+	userID int64,
+	messageID int64,
+	name string,
+	value int,
+) (interfaces.PlusplusCorrection, error) {
+	mutexPlusplus.Lock()
+	defer mutexPlusplus.Unlock()
+
+	var correction interfaces.PlusplusCorrection
+
+	err := r.tx.Transaction(func(tx *gorm.DB) error {
+		canonical, err := resolveAlias(tx, chatID, name)
+		if err != nil {
+			return err
+		}
+
+		record, err := findTerm(tx, chatID, canonical)
+		if err != nil {
+			return err
+		}
+
+		// The difference is logged as a change, so the event log keeps adding up to the value
+		if delta := value - record.Value; delta != 0 {
+			if err := tx.Create(&interfaces.PlusplusEvent{
+				ChatID:      chatID,
+				GiverUserID: userID,
+				Name:        canonical,
+				Delta:       delta,
+				MessageID:   messageID,
+				Reason:      "",
+				Token:       false,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := addValue(tx, chatID, canonical, delta); err != nil {
+				return err
+			}
+		}
+
+		correction, err = r.correct(tx, chatID, userID, interfaces.PlusplusActionSet, canonical, record.Value, value)
+
+		return err
+	})
+	if err != nil {
+		return interfaces.PlusplusCorrection{}, err
+	}
+
+	return correction, displayNames(r.tx, chatID, &correction.Name)
+}
+
+func (r PlusplusRepo) Delete(chatID int64, userID int64, name string) (interfaces.PlusplusCorrection, error) {
+	mutexPlusplus.Lock()
+	defer mutexPlusplus.Unlock()
+
+	var correction interfaces.PlusplusCorrection
+
+	err := r.tx.Transaction(func(tx *gorm.DB) error {
+		canonical, err := resolveAlias(tx, chatID, name)
+		if err != nil {
+			return err
+		}
+
+		record, err := findTerm(tx, chatID, canonical)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.
+			Where("chat_id = ?", chatID).
+			Where(termCondition("name", chatID, canonical)).
+			Delete(&interfaces.PlusplusEvent{}).
+			Error; err != nil {
+			return err
+		}
+
+		// The unique indexes also cover soft deleted records
+		if err := tx.
+			Unscoped().
+			Where("chat_id = ?", chatID).
+			Where(termCondition("name", chatID, canonical)).
+			Delete(&interfaces.Plusplus{}).
+			Error; err != nil {
+			return err
+		}
+
+		if err := tx.
+			Unscoped().
+			Where("chat_id = ? AND name = ?", chatID, canonical).
+			Delete(&interfaces.PlusplusAlias{}).
+			Error; err != nil {
+			return err
+		}
+
+		correction, err = r.correct(tx, chatID, userID, interfaces.PlusplusActionDelete, canonical, record.Value, 0)
+
+		return err
+	})
+	if err != nil {
+		return interfaces.PlusplusCorrection{}, err
+	}
+
+	return correction, displayNames(r.tx, chatID, &correction.Name)
+}
+
+func (r PlusplusRepo) GetWeeklyTrend(
+	chatID int64,
+	name string,
+	weeks int,
+	loc *time.Location,
+) ([]interfaces.PlusplusWeekStruct, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	canonical, err := resolveAlias(r.tx, chatID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := make([]interfaces.PlusplusWeekStruct, max(weeks, 0))
+	if len(trend) == 0 {
+		return trend, nil
+	}
+
+	start := startOfWeek(time.Now().In(loc)).AddDate(0, 0, -daysPerWeek*(weeks-1))
+	for i := range trend {
+		trend[i].Week = start.AddDate(0, 0, daysPerWeek*i)
+	}
+
+	var events []interfaces.PlusplusEvent
 	if err := r.tx.
-		Where("name = ?", name).
-		First(&record).
+		Select("created_at, delta").
+		Where("chat_id = ? AND created_at >= ?", chatID, start.UTC()).
+		Where(termCondition("name", chatID, canonical)).
+		Find(&events).
 		Error; err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return record.Value, nil
+	for _, event := range events {
+		for i := len(trend) - 1; i >= 0; i-- {
+			if !event.CreatedAt.Before(trend[i].Week) {
+				trend[i].Delta += event.Delta
+
+				break
+			}
+		}
+	}
+
+	return trend, nil
 }
 
-func (r PlusplusRepo) FindTops(limit int) ([]interfaces.Plusplus, error) {
+// changes returns the latest changes of a term, optionally only those with a reason.
+func (r PlusplusRepo) changes(chatID int64, name string, limit int, withReason bool) ([]interfaces.PlusplusChangeStruct, error) {
+	canonical, err := resolveAlias(r.tx, chatID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.events(chatID, canonical)
+	if withReason {
+		query = query.Where("e.reason != ''")
+	}
+
+	var records []interfaces.PlusplusChangeStruct
+
+	err = query.
+		Select("e.giver_user_id as user_id, COALESCE(s.username, '') as username, e.delta, e.reason, e.created_at").
+		Order("e.created_at DESC, e.id DESC").
+		Limit(limit).
+		Scan(&records).
+		Error
+
+	return records, err
+}
+
+// ranking returns the canonical terms of a chat ordered by value, the values of aliases added up.
+func (r PlusplusRepo) ranking(chatID int64, limit int, direction string) ([]interfaces.Plusplus, error) {
 	var records []interfaces.Plusplus
+
 	if err := r.tx.
-		Order("value desc").
+		Table("plusplus p").
+		Select("p.chat_id, COALESCE(a.name, p.name) as name, SUM(p.value) as value").
+		Joins("LEFT JOIN plusplus_aliases a ON p.chat_id = a.chat_id AND p.name = a.alias AND a.deleted_at IS NULL").
+		Where("p.chat_id = ? AND p.deleted_at IS NULL", chatID).
+		Group("p.chat_id, COALESCE(a.name, p.name)").
+		Order("SUM(p.value) " + direction + ", name").
 		Limit(limit).
-		Find(&records).
+		Scan(&records).
 		Error; err != nil {
 		return nil, err
 	}
 
-	return records, nil
+	names := make([]*string, 0, len(records))
+	for i := range records {
+		names = append(names, &records[i].Name)
+	}
+
+	return records, displayNames(r.tx, chatID, names...)
 }
 
-func (r PlusplusRepo) FindFlops(limit int) ([]interfaces.Plusplus, error) {
-	var records []interfaces.Plusplus
+// periodRanking returns the canonical terms of a chat ordered by their net
+// change in a period, together with their all-time values.
+func (r PlusplusRepo) periodRanking(
+	chatID int64,
+	since time.Time,
+	until time.Time,
+	limit int,
+	direction string,
+) ([]interfaces.PlusplusPeriodStruct, error) {
+	var records []interfaces.PlusplusPeriodStruct
+
 	if err := r.tx.
-		Order("value asc").
+		Table("plusplus_events e").
+		Select("COALESCE(a.name, e.name) as name, SUM(e.delta) as delta").
+		Joins("LEFT JOIN plusplus_aliases a ON e.chat_id = a.chat_id AND e.name = a.alias AND a.deleted_at IS NULL").
+		Where("e.chat_id = ? AND e.created_at >= ? AND e.created_at < ? AND e.deleted_at IS NULL", chatID, since.UTC(), until.UTC()).
+		Group("COALESCE(a.name, e.name)").
+		Order("SUM(e.delta) " + direction + ", name").
 		Limit(limit).
-		Find(&records).
+		Scan(&records).
 		Error; err != nil {
 		return nil, err
 	}
 
-	return records, nil
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		names = append(names, record.Name)
+	}
+
+	var totals []interfaces.Plusplus
+	if err := r.tx.
+		Table("plusplus p").
+		Select("COALESCE(a.name, p.name) as name, SUM(p.value) as value").
+		Joins("LEFT JOIN plusplus_aliases a ON p.chat_id = a.chat_id AND p.name = a.alias AND a.deleted_at IS NULL").
+		Where("p.chat_id = ? AND p.deleted_at IS NULL AND COALESCE(a.name, p.name) IN ?", chatID, names).
+		Group("COALESCE(a.name, p.name)").
+		Scan(&totals).
+		Error; err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]int, len(totals))
+	for _, total := range totals {
+		values[total.Name] = total.Value
+	}
+
+	displayed := make([]*string, 0, len(records))
+	for i := range records {
+		records[i].Value = values[records[i].Name]
+		displayed = append(displayed, &records[i].Name)
+	}
+
+	return records, displayNames(r.tx, chatID, displayed...)
+}
+
+// events returns a query on the event log of a canonical term and its
+// aliases, joined with the user stats for the names of the givers.
+func (r PlusplusRepo) events(chatID int64, canonical string) *gorm.DB {
+	return r.tx.
+		Table("plusplus_events e").
+		Joins("LEFT JOIN stats s ON e.chat_id = s.chat_id AND e.giver_user_id = s.user_id").
+		Where("e.chat_id = ? AND e.deleted_at IS NULL", chatID).
+		Where(termCondition("e.name", chatID, canonical))
+}
+
+// correct records a correction in the audit log.
+func (r PlusplusRepo) correct(
+	tx *gorm.DB,
+	chatID int64,
+	userID int64,
+	action string,
+	name string,
+	oldValue int,
+	newValue int,
+) (interfaces.PlusplusCorrection, error) {
+	correction := interfaces.PlusplusCorrection{
+		ChatID:   chatID,
+		UserID:   userID,
+		Action:   action,
+		Name:     name,
+		OldValue: oldValue,
+		NewValue: newValue,
+	}
+
+	if err := tx.Create(&correction).Error; err != nil {
+		return interfaces.PlusplusCorrection{}, err
+	}
+
+	r.log.Infof("User %d corrected %q in chat %d by %s from %d to %d", userID, name, chatID, action, oldValue, newValue)
+
+	return correction, nil
+}
+
+// resolveAlias returns the canonical term for a name or mention, or the name itself if it isn't an alias.
+func resolveAlias(tx *gorm.DB, chatID int64, name string) (string, error) {
+	name, err := resolveMention(tx, chatID, name)
+	if err != nil {
+		return "", err
+	}
+
+	return findCanonical(tx, chatID, name)
+}
+
+// findCanonical returns the canonical term for a stored term, or the term itself if it isn't an alias.
+func findCanonical(tx *gorm.DB, chatID int64, name string) (string, error) {
+	var alias interfaces.PlusplusAlias
+	if err := tx.
+		Where("chat_id = ? AND alias = ?", chatID, name).
+		Limit(1).
+		Find(&alias).
+		Error; err != nil {
+		return "", err
+	}
+
+	if alias.Name == "" {
+		return name, nil
+	}
+
+	return alias.Name, nil
+}
+
+// resolveMention returns the term of a chat member for an @mention, or the
+// name itself if it isn't a mention of a known member.
+func resolveMention(tx *gorm.DB, chatID int64, name string) (string, error) {
+	if !strings.HasPrefix(name, "@") {
+		return name, nil
+	}
+
+	userID, err := NewUserStatsRepo(tx).GetUserIDByUsername(chatID, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return name, nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return interfaces.PlusplusUserPrefix + strconv.FormatInt(userID, 10), nil
+}
+
+// displayNames replaces the terms of chat members by their current name in
+// the chat, or by their user ID if the name is unknown.
+func displayNames(tx *gorm.DB, chatID int64, names ...*string) error {
+	userIDs := make(map[*string]int64)
+
+	for _, name := range names {
+		value, found := strings.CutPrefix(*name, interfaces.PlusplusUserPrefix)
+		if !found {
+			continue
+		}
+
+		if userID, err := strconv.ParseInt(value, 10, 64); err == nil {
+			userIDs[name] = userID
+		}
+	}
+
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, userID)
+	}
+
+	var records []interfaces.Stats
+	if err := tx.
+		Where("chat_id = ? AND user_id IN ?", chatID, ids).
+		Find(&records).
+		Error; err != nil {
+		return err
+	}
+
+	usernames := make(map[int64]string, len(records))
+	for _, record := range records {
+		usernames[record.UserID] = record.Username
+	}
+
+	for name, userID := range userIDs {
+		*name = usernames[userID]
+		if *name == "" {
+			*name = fmt.Sprintf("#%d", userID)
+		}
+	}
+
+	return nil
+}
+
+// termCondition matches a column against a canonical term and all of its aliases.
+func termCondition(column string, chatID int64, canonical string) clause.Expr {
+	return gorm.Expr(
+		"("+column+" = ? OR "+column+" IN (SELECT alias FROM plusplus_aliases WHERE chat_id = ? AND name = ? AND deleted_at IS NULL))",
+		canonical,
+		chatID,
+		canonical,
+	)
+}
+
+// findTerm returns the counter of a canonical term with the values of its aliases added.
+func findTerm(tx *gorm.DB, chatID int64, canonical string) (interfaces.Plusplus, error) {
+	var records []interfaces.Plusplus
+	if err := tx.
+		Where("chat_id = ?", chatID).
+		Where(termCondition("name", chatID, canonical)).
+		Find(&records).
+		Error; err != nil {
+		return interfaces.Plusplus{}, err
+	}
+
+	if len(records) == 0 {
+		return interfaces.Plusplus{}, gorm.ErrRecordNotFound
+	}
+
+	record := interfaces.Plusplus{
+		ChatID: chatID,
+		Name:   canonical,
+		Value:  0,
+	}
+	for _, alias := range records {
+		record.Value += alias.Value
+	}
+
+	return record, nil
+}
+
+func addValue(tx *gorm.DB, chatID int64, name string, increment int) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "chat_id"}, {Name: "name"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"value": gorm.Expr("plusplus.value + ?", increment),
+		}),
+	}).Create(&interfaces.Plusplus{
+		ChatID: chatID,
+		Name:   name,
+		Value:  increment,
+	}).Error
+}
+
+// setAlias maps a term onto the canonical term of another one and redirects
+// the aliases of the term, so aliases never point to other aliases.
+func setAlias(tx *gorm.DB, chatID int64, alias string, name string) (string, error) {
+	alias, err := resolveMention(tx, chatID, alias)
+	if err != nil {
+		return "", err
+	}
+
+	canonical, err := resolveAlias(tx, chatID, name)
+	if err != nil {
+		return "", err
+	}
+
+	if canonical == alias {
+		return "", interfaces.ErrPlusplusSelfAlias
+	}
+
+	if err := tx.
+		Model(&interfaces.PlusplusAlias{}).
+		Where("chat_id = ? AND name = ?", chatID, alias).
+		Update("name", canonical).
+		Error; err != nil {
+		return "", err
+	}
+
+	return canonical, tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_id"}, {Name: "alias"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+	}).Create(&interfaces.PlusplusAlias{
+		ChatID: chatID,
+		Alias:  alias,
+		Name:   canonical,
+	}).Error
+}
+
+// startOfWeek returns Monday 00:00 of the week the given time is in, in its location.
+func startOfWeek(t time.Time) time.Time {
+	year, month, day := t.Date()
+
+	return time.Date(year, month, day-(int(t.Weekday())+daysPerWeek-1)%daysPerWeek, 0, 0, 0, 0, t.Location())
 }
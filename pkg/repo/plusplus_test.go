@@ -0,0 +1,754 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestPlusplusRepo_IncrementPerChat(t *testing.T) {
+	t.Parallel()
+
+	r := repo.NewPlusplusRepo(provideDatabase(t))
+
+	value, err := r.Increment(1, 10, 100, "foo", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+
+	value, err = r.Increment(2, 10, 101, "foo", -1)
+	require.NoError(t, err)
+	assert.Equal(t, -1, value)
+
+	value, err = r.Increment(1, 20, 102, "foo", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, value)
+
+	tops, err := r.FindTops(1, 10)
+	require.NoError(t, err)
+	require.Len(t, tops, 1)
+	assert.Equal(t, 3, tops[0].Value)
+
+	flops, err := r.FindFlops(2, 10)
+	require.NoError(t, err)
+	require.Len(t, flops, 1)
+	assert.Equal(t, -1, flops[0].Value)
+}
+
+func TestPlusplusRepo_IncrementRecordsEvent(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	_, err := r.Increment(1, 10, 100, "foo", 2)
+	require.NoError(t, err)
+
+	_, err = r.Increment(1, 20, 101, "foo", -1)
+	require.NoError(t, err)
+
+	var events []interfaces.PlusplusEvent
+	require.NoError(t, conn.Order("id").Find(&events).Error)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(1), events[0].ChatID)
+	assert.Equal(t, int64(10), events[0].GiverUserID)
+	assert.Equal(t, "foo", events[0].Name)
+	assert.Equal(t, 2, events[0].Delta)
+	assert.Equal(t, int64(100), events[0].MessageID)
+	assert.Equal(t, -1, events[1].Delta)
+
+	record, err := r.FindByName(1, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, 1, record.Value)
+
+	_, err = r.FindByName(2, "foo")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestPlusplusRepo_Givers(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	statsRepo := repo.NewUserStatsRepo(conn)
+	r := repo.NewPlusplusRepo(conn)
+	require.NoError(t, statsRepo.UpdateStats(1, 10, "alice"))
+
+	for _, increment := range []struct {
+		chatID int64
+		userID int64
+		name   string
+		delta  int
+	}{
+		{1, 10, "foo", 1},
+		{1, 10, "foo", 2},
+		{1, 20, "foo", -1},
+		{1, 20, "foo", 1},
+		{1, 30, "foo", -5},
+		{1, 10, "bar", 9},
+		{2, 10, "foo", 9},
+	} {
+		_, err := r.Increment(increment.chatID, increment.userID, 0, increment.name, increment.delta)
+		require.NoError(t, err)
+	}
+
+	givers, err := r.FindTopGivers(1, "foo", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.PlusplusGiverStruct{
+		{UserID: 30, Username: "", Plus: 0, Minus: 5},
+		{UserID: 10, Username: "alice", Plus: 3, Minus: 0},
+	}, givers)
+
+	changes, err := r.FindRecentChanges(1, "foo", 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 5)
+	assert.Equal(t, int64(30), changes[0].UserID)
+	assert.Equal(t, -5, changes[0].Delta)
+	assert.Equal(t, 1, changes[1].Delta)
+	assert.Equal(t, "alice", changes[3].Username)
+	assert.Equal(t, 2, changes[3].Delta)
+	assert.WithinDuration(t, time.Now(), changes[0].CreatedAt, time.Minute)
+}
+
+func TestPlusplusRepo_GetWeeklyTrend(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	_, err := r.Increment(1, 10, 0, "foo", 2)
+	require.NoError(t, err)
+
+	_, err = r.Increment(1, 10, 0, "foo", -1)
+	require.NoError(t, err)
+
+	for _, event := range []struct {
+		age   time.Duration
+		delta int
+	}{
+		{14 * 24 * time.Hour, 3},
+		{14 * 24 * time.Hour, 1},
+		{100 * 24 * time.Hour, 50},
+	} {
+		require.NoError(t, conn.Create(&interfaces.PlusplusEvent{
+			Model:       gorm.Model{CreatedAt: time.Now().Add(-event.age)},
+			ChatID:      1,
+			GiverUserID: 10,
+			Name:        "foo",
+			Delta:       event.delta,
+			MessageID:   0,
+		}).Error)
+	}
+
+	trend, err := r.GetWeeklyTrend(1, "foo", 4, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, trend, 4)
+	assert.Equal(t, time.Monday, trend[0].Week.Weekday())
+	assert.Equal(t, 7*24*time.Hour, trend[3].Week.Sub(trend[2].Week))
+	assert.Equal(t, []int{0, 4, 0, 1}, []int{trend[0].Delta, trend[1].Delta, trend[2].Delta, trend[3].Delta})
+
+	trend, err = r.GetWeeklyTrend(2, "foo", 4, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 0, 0, 0}, []int{trend[0].Delta, trend[1].Delta, trend[2].Delta, trend[3].Delta})
+}
+
+func TestPlusplusRepo_GetLastIncrementTime(t *testing.T) {
+	t.Parallel()
+
+	r := repo.NewPlusplusRepo(provideDatabase(t))
+
+	last, err := r.GetLastIncrementTime(1, 10, "foo")
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	_, err = r.Increment(1, 10, 0, "foo", 1)
+	require.NoError(t, err)
+
+	last, err = r.GetLastIncrementTime(1, 10, "foo")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), last, time.Minute)
+
+	for _, other := range []struct {
+		chatID int64
+		userID int64
+		name   string
+	}{
+		{2, 10, "foo"},
+		{1, 20, "foo"},
+		{1, 10, "bar"},
+	} {
+		last, err = r.GetLastIncrementTime(other.chatID, other.userID, other.name)
+		require.NoError(t, err)
+		assert.True(t, last.IsZero(), other)
+	}
+}
+
+func TestPlusplusRepo_Aliases(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	for _, increment := range []struct {
+		name  string
+		delta int
+	}{
+		{"kaffee", 3},
+		{"coffee", 2},
+		{"tee", 1},
+	} {
+		_, err := r.Increment(1, 10, 0, increment.name, increment.delta)
+		require.NoError(t, err)
+	}
+
+	canonical, err := r.SetAlias(1, "coffee", "kaffee")
+	require.NoError(t, err)
+	assert.Equal(t, "kaffee", canonical)
+
+	// An alias of an alias points to the canonical term
+	canonical, err = r.SetAlias(1, "☕", "coffee")
+	require.NoError(t, err)
+	assert.Equal(t, "kaffee", canonical)
+
+	_, err = r.SetAlias(1, "kaffee", "coffee")
+	require.ErrorIs(t, err, interfaces.ErrPlusplusSelfAlias)
+
+	value, err := r.Increment(1, 20, 0, "☕", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 6, value)
+
+	record, err := r.FindByName(1, "coffee")
+	require.NoError(t, err)
+	assert.Equal(t, "kaffee", record.Name)
+	assert.Equal(t, 6, record.Value)
+
+	tops, err := r.FindTops(1, 10)
+	require.NoError(t, err)
+	require.Len(t, tops, 2)
+	assert.Equal(t, "kaffee", tops[0].Name)
+	assert.Equal(t, 6, tops[0].Value)
+	assert.Equal(t, "tee", tops[1].Name)
+
+	flops, err := r.FindFlops(1, 1)
+	require.NoError(t, err)
+	require.Len(t, flops, 1)
+	assert.Equal(t, "tee", flops[0].Name)
+
+	changes, err := r.FindRecentChanges(1, "kaffee", 10)
+	require.NoError(t, err)
+	assert.Len(t, changes, 3)
+
+	last, err := r.GetLastIncrementTime(1, 10, "coffee")
+	require.NoError(t, err)
+	assert.False(t, last.IsZero())
+
+	aliases, err := r.FindAliases(1)
+	require.NoError(t, err)
+	require.Len(t, aliases, 2)
+	assert.Equal(t, "kaffee", aliases[0].Name)
+	assert.Equal(t, "kaffee", aliases[1].Name)
+
+	// Aliases are scoped to the chat
+	value, err = r.Increment(2, 10, 0, "coffee", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+}
+
+func TestPlusplusRepo_Merge(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	for _, increment := range []struct {
+		name  string
+		delta int
+	}{
+		{"kaffee", 3},
+		{"coffee", 2},
+		{"coffee", -1},
+	} {
+		_, err := r.Increment(1, 10, 0, increment.name, increment.delta)
+		require.NoError(t, err)
+	}
+
+	canonical, value, err := r.Merge(1, "coffee", "kaffee")
+	require.NoError(t, err)
+	assert.Equal(t, "kaffee", canonical)
+	assert.Equal(t, 4, value)
+
+	var names []string
+	require.NoError(t, conn.Model(&interfaces.Plusplus{}).Unscoped().Pluck("name", &names).Error)
+	assert.Equal(t, []string{"kaffee"}, names)
+
+	require.NoError(t, conn.Model(&interfaces.PlusplusEvent{}).Distinct().Pluck("name", &names).Error)
+	assert.Equal(t, []string{"kaffee"}, names)
+
+	value, err = r.Increment(1, 10, 0, "coffee", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 5, value)
+
+	_, _, err = r.Merge(1, "kaffee", "coffee")
+	require.ErrorIs(t, err, interfaces.ErrPlusplusSelfAlias)
+
+	// Merging a term without a value only adds the alias
+	canonical, value, err = r.Merge(1, "java", "coffee")
+	require.NoError(t, err)
+	assert.Equal(t, "kaffee", canonical)
+	assert.Equal(t, 5, value)
+}
+
+func TestPlusplusRepo_FindReasons(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	_, err := r.IncrementWithReason(1, 10, 0, "coffee", 1, "for the smell")
+	require.NoError(t, err)
+
+	value, err := r.IncrementWithReason(1, 10, 0, "kaffee", 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+
+	_, err = r.SetAlias(1, "coffee", "kaffee")
+	require.NoError(t, err)
+
+	value, err = r.IncrementWithReason(1, 20, 0, "kaffee", -1, "too bitter")
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+
+	reasons, err := r.FindReasons(1, "kaffee", 10)
+	require.NoError(t, err)
+	require.Len(t, reasons, 2)
+	assert.Equal(t, "too bitter", reasons[0].Reason)
+	assert.Equal(t, -1, reasons[0].Delta)
+	assert.Equal(t, "for the smell", reasons[1].Reason)
+
+	reasons, err = r.FindReasons(2, "kaffee", 10)
+	require.NoError(t, err)
+	assert.Empty(t, reasons)
+}
+
+func TestPlusplusRepo_FindInPeriod(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	now := time.Now()
+
+	for _, increment := range []struct {
+		chatID int64
+		name   string
+		delta  int
+		age    time.Duration
+	}{
+		{1, "kaffee", 10, 400 * 24 * time.Hour},
+		{1, "kaffee", 1, time.Hour},
+		{1, "coffee", 2, 2 * time.Hour},
+		{1, "tee", 3, time.Hour},
+		{1, "tee", -1, 10 * 24 * time.Hour},
+		{1, "montag", -2, time.Hour},
+		{2, "kaffee", 50, time.Hour},
+	} {
+		_, err := r.Increment(increment.chatID, 10, 0, increment.name, increment.delta)
+		require.NoError(t, err)
+
+		require.NoError(t, conn.
+			Table("plusplus_events").
+			Where("id = (SELECT MAX(id) FROM plusplus_events)").
+			Update("created_at", now.Add(-increment.age).UTC()).
+			Error)
+	}
+
+	_, err := r.SetAlias(1, "coffee", "kaffee")
+	require.NoError(t, err)
+
+	tops, err := r.FindTopsInPeriod(1, now.Add(-7*24*time.Hour), now, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.PlusplusPeriodStruct{
+		{Name: "kaffee", Delta: 3, Value: 13},
+		{Name: "tee", Delta: 3, Value: 2},
+		{Name: "montag", Delta: -2, Value: -2},
+	}, tops)
+
+	flops, err := r.FindFlopsInPeriod(1, now.Add(-30*24*time.Hour), now, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.PlusplusPeriodStruct{
+		{Name: "montag", Delta: -2, Value: -2},
+		{Name: "tee", Delta: 2, Value: 2},
+	}, flops)
+
+	tops, err = r.FindTopsInPeriod(1, now.Add(-500*24*time.Hour), now.Add(-300*24*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.PlusplusPeriodStruct{{Name: "kaffee", Delta: 10, Value: 13}}, tops)
+
+	tops, err = r.FindTopsInPeriod(3, now.Add(-500*24*time.Hour), now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, tops)
+}
+
+func TestPlusplusRepo_Mentions(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	stats := repo.NewUserStatsRepo(conn)
+	require.NoError(t, stats.UpdateStats(1, 42, "@Alice"))
+	require.NoError(t, stats.UpdateStats(1, 43, "Bob Builder"))
+
+	// @mentions of members and terms of users without a username end up on the same user
+	_, err := r.Increment(1, 10, 100, "@alice", 2)
+	require.NoError(t, err)
+
+	value, err := r.Increment(1, 10, 101, "user 42", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, value)
+
+	_, err = r.Increment(1, 10, 102, "user 43", 1)
+	require.NoError(t, err)
+
+	_, err = r.Increment(1, 10, 103, "user 44", 1)
+	require.NoError(t, err)
+
+	// Unknown users stay literal terms
+	_, err = r.Increment(1, 10, 104, "@carol", -1)
+	require.NoError(t, err)
+
+	tops, err := r.FindTops(1, 10)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(tops))
+	for _, top := range tops {
+		names = append(names, top.Name)
+	}
+
+	assert.Equal(t, []string{"@Alice", "Bob Builder", "#44", "@carol"}, names)
+
+	// After a rename the counter follows the user
+	require.NoError(t, stats.UpdateStats(1, 42, "@Alicia"))
+
+	record, err := r.FindByName(1, "@ALICIA")
+	require.NoError(t, err)
+	assert.Equal(t, "@Alicia", record.Name)
+	assert.Equal(t, 3, record.Value)
+
+	period, err := r.FindTopsInPeriod(1, time.Now().Add(-time.Hour), time.Now().Add(time.Hour), 1)
+	require.NoError(t, err)
+	require.Len(t, period, 1)
+	assert.Equal(t, interfaces.PlusplusPeriodStruct{Name: "@Alicia", Delta: 3, Value: 3}, period[0])
+}
+
+func TestPlusplusRepo_Undo(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	_, err := r.IncrementWithReason(1, 10, 100, "foo", 2, "")
+	require.NoError(t, err)
+
+	_, err = r.IncrementWithReason(1, 10, 101, "foo", -5, "")
+	require.NoError(t, err)
+
+	_, err = r.IncrementWithReason(1, 10, 101, "bar", 1, "")
+	require.NoError(t, err)
+
+	_, err = r.IncrementWithReason(1, 20, 102, "foo", 1, "")
+	require.NoError(t, err)
+
+	// Only the latest message of the user is reverted
+	corrections, err := r.Undo(1, 10, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, corrections, 2)
+	assert.Equal(t, "foo", corrections[0].Name)
+	assert.Equal(t, -2, corrections[0].OldValue)
+	assert.Equal(t, 3, corrections[0].NewValue)
+	assert.Equal(t, "bar", corrections[1].Name)
+	assert.Equal(t, 1, corrections[1].OldValue)
+	assert.Equal(t, 0, corrections[1].NewValue)
+
+	changes, err := r.FindRecentChanges(1, "foo", 10)
+	require.NoError(t, err)
+	assert.Len(t, changes, 2)
+
+	// Changes before the window can't be reverted
+	corrections, err = r.Undo(1, 10, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, corrections)
+
+	corrections, err = r.Undo(1, 10, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, corrections, 1)
+	assert.Equal(t, 1, corrections[0].NewValue)
+
+	var audit []interfaces.PlusplusCorrection
+	require.NoError(t, conn.Order("id").Find(&audit).Error)
+	require.Len(t, audit, 3)
+	assert.Equal(t, int64(10), audit[0].UserID)
+	assert.Equal(t, interfaces.PlusplusActionUndo, audit[0].Action)
+}
+
+func TestPlusplusRepo_UndoOnlyVotes(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	_, err := r.IncrementWithReason(1, 10, 100, "foo", 1, "")
+	require.NoError(t, err)
+
+	// A /karma set and a buzzword are logged under the same user, but aren't votes
+	_, err = r.SetValue(1, 10, 101, "foo", 42)
+	require.NoError(t, err)
+
+	_, err = r.Increment(1, 10, 102, "coffee", 1)
+	require.NoError(t, err)
+
+	corrections, err := r.Undo(1, 10, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, corrections, 1)
+	assert.Equal(t, "foo", corrections[0].Name)
+	assert.Equal(t, 42, corrections[0].OldValue)
+	assert.Equal(t, 41, corrections[0].NewValue)
+
+	coffee, err := r.FindByName(1, "coffee")
+	require.NoError(t, err)
+	assert.Equal(t, 1, coffee.Value)
+
+	corrections, err = r.Undo(1, 10, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, corrections)
+}
+
+func TestPlusplusRepo_SetValueAndDelete(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	_, err := r.Increment(1, 10, 100, "foo", -8)
+	require.NoError(t, err)
+
+	_, err = r.Increment(1, 10, 101, "coffee", 2)
+	require.NoError(t, err)
+
+	_, err = r.SetAlias(1, "coffee", "foo")
+	require.NoError(t, err)
+
+	_, err = r.SetValue(1, 99, 102, "bar", 1)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// The value includes the aliases, the difference is logged as a change of the admin
+	correction, err := r.SetValue(1, 99, 102, "coffee", 4)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", correction.Name)
+	assert.Equal(t, -6, correction.OldValue)
+	assert.Equal(t, 4, correction.NewValue)
+
+	record, err := r.FindByName(1, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, 4, record.Value)
+
+	givers, err := r.FindTopGivers(1, "foo", 10)
+	require.NoError(t, err)
+	require.Len(t, givers, 2)
+	assert.Equal(t, int64(99), givers[1].UserID)
+	assert.Equal(t, 10, givers[1].Plus)
+
+	correction, err = r.Delete(1, 99, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, 4, correction.OldValue)
+	assert.Equal(t, 0, correction.NewValue)
+
+	_, err = r.FindByName(1, "coffee")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	aliases, err := r.FindAliases(1)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+
+	// A deleted term starts from scratch
+	value, err := r.Increment(1, 10, 103, "foo", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	changes, err := r.FindRecentChanges(1, "foo", 10)
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+
+	var audit []interfaces.PlusplusCorrection
+	require.NoError(t, conn.Order("id").Find(&audit).Error)
+	require.Len(t, audit, 2)
+	assert.Equal(t, []string{interfaces.PlusplusActionSet, interfaces.PlusplusActionDelete}, []string{audit[0].Action, audit[1].Action})
+	assert.Equal(t, int64(99), audit[1].UserID)
+}
+
+func TestPlusplusRepo_ReviseMessage(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	_, err := r.IncrementWithReason(1, 10, 100, "foo", 2, "")
+	require.NoError(t, err)
+
+	_, err = r.IncrementWithReason(1, 10, 100, "bar", 1, "")
+	require.NoError(t, err)
+
+	// Buzzwords share the message, but aren't tokens
+	_, err = r.Increment(1, 10, 100, "buzz", 1)
+	require.NoError(t, err)
+
+	// The edit removes bar, lowers foo and adds baz
+	revisions, err := r.ReviseMessage(1, 10, 100, []interfaces.PlusplusIncrementStruct{
+		{Name: "foo", Increment: 1},
+		{Name: "baz", Increment: 1, Reason: "the fix"},
+	}, allowRevision)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.PlusplusRevisionStruct{
+		{Name: "foo", Delta: -1, Value: 1},
+		{Name: "baz", Delta: 1, Value: 1},
+		{Name: "bar", Delta: -1, Value: 0},
+	}, revisions)
+
+	// Editing again without changes doesn't change anything
+	revisions, err = r.ReviseMessage(1, 10, 100, []interfaces.PlusplusIncrementStruct{
+		{Name: "foo", Increment: 1},
+		{Name: "baz", Increment: 1},
+	}, allowRevision)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	buzz, err := r.FindByName(1, "buzz")
+	require.NoError(t, err)
+	assert.Equal(t, 1, buzz.Value)
+
+	reasons, err := r.FindReasons(1, "baz", 10)
+	require.NoError(t, err)
+	require.Len(t, reasons, 1)
+	assert.Equal(t, "the fix", reasons[0].Reason)
+}
+
+func TestPlusplusRepo_ReviseMessageAppliesRule(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	_, err := r.IncrementWithReason(1, 10, 100, "foo", 2, "")
+	require.NoError(t, err)
+
+	checked := make([]string, 0)
+	rejectBar := func(term string, _ string, delta int, total int, last time.Time) string {
+		checked = append(checked, term)
+
+		if term == "bar" {
+			assert.Equal(t, 1, delta)
+			assert.Equal(t, 1, total)
+			assert.True(t, last.IsZero())
+
+			return "rejected"
+		}
+
+		return ""
+	}
+
+	// Lowering foo only takes back votes, so the rule doesn't need to allow it
+	revisions, err := r.ReviseMessage(1, 10, 100, []interfaces.PlusplusIncrementStruct{
+		{Name: "foo", Increment: 1},
+		{Name: "bar", Increment: 1},
+	}, rejectBar)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.PlusplusRevisionStruct{
+		{Name: "foo", Delta: -1, Value: 1},
+	}, revisions)
+	assert.Equal(t, []string{"bar"}, checked)
+
+	_, err = r.FindByName(1, "bar")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestPlusplusRepo_ReviseMessageKeepsCountedVotes(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	_, err := r.IncrementWithReason(1, 10, 100, "foo", 1, "")
+	require.NoError(t, err)
+
+	rejectAboveThree := func(_ string, _ string, delta int, total int, _ time.Time) string {
+		assert.Equal(t, 4, delta)
+
+		if total > 3 {
+			return "rejected"
+		}
+
+		return ""
+	}
+
+	// Rejecting the new votes leaves the vote the message already counted
+	revisions, err := r.ReviseMessage(1, 10, 100, []interfaces.PlusplusIncrementStruct{
+		{Name: "foo", Increment: 5},
+	}, rejectAboveThree)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	record, err := r.FindByName(1, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, 1, record.Value)
+}
+
+func TestPlusplusRepo_ReviseMessageSkipsUndoneMessages(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	_, err := r.IncrementWithReason(1, 10, 100, "foo", 1, "")
+	require.NoError(t, err)
+
+	_, err = r.Undo(1, 10, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	revisions, err := r.ReviseMessage(1, 10, 100, []interfaces.PlusplusIncrementStruct{
+		{Name: "foo", Increment: 1},
+	}, allowRevision)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	record, err := r.FindByName(1, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, 0, record.Value)
+}
+
+func TestPlusplusRepo_ReviseMessageSkipsMessagesWithoutTokens(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewPlusplusRepo(conn)
+
+	// Changes from before tokens were recorded look like buzzwords
+	_, err := r.Increment(1, 10, 100, "foo", 1)
+	require.NoError(t, err)
+
+	revisions, err := r.ReviseMessage(1, 10, 100, []interfaces.PlusplusIncrementStruct{
+		{Name: "foo", Increment: 1},
+	}, allowRevision)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	record, err := r.FindByName(1, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, 1, record.Value)
+}
+
+func allowRevision(string, string, int, int, time.Time) string {
+	return ""
+}
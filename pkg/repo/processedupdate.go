@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/br0-space/bot/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProcessedUpdateRepo struct {
+	BaseRepo
+}
+
+func NewProcessedUpdateRepo(tx *gorm.DB) *ProcessedUpdateRepo {
+	return &ProcessedUpdateRepo{
+		BaseRepo: NewBaseRepo(
+			tx,
+			&interfaces.ProcessedUpdate{
+				UpdateID: 0,
+			},
+		),
+	}
+}
+
+func (r ProcessedUpdateRepo) Insert(updateID int64) (bool, error) {
+	result := r.tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "update_id"}},
+		DoNothing: true,
+	}).Create(&interfaces.ProcessedUpdate{
+		UpdateID: updateID,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r ProcessedUpdateRepo) DeleteOlderThan(t time.Time) error {
+	return r.tx.
+		Unscoped().
+		Where("created_at < ?", t.UTC()).
+		Delete(&interfaces.ProcessedUpdate{}).
+		Error
+}
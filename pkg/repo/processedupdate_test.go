@@ -0,0 +1,80 @@
+package repo_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	logger "github.com/br0-space/bot-logger"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/db"
+	"github.com/br0-space/bot/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// provideDatabase opens a fresh SQLite database in a temporary directory
+// and applies all migrations.
+func provideDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	cfg := interfaces.DatabaseConfigStruct{Driver: "sqlite"}
+	cfg.SQLite.File = filepath.Join(t.TempDir(), "test.db")
+
+	conn := db.NewConnection(logger.New(), cfg)
+	require.NotNil(t, conn)
+
+	t.Cleanup(func() {
+		_ = db.CloseConnection(conn)
+	})
+
+	require.NoError(t, db.MakeDatabaseMigration(conn, db.Migrations(0)).Migrate())
+
+	return conn
+}
+
+func TestProcessedUpdateRepo_Insert(t *testing.T) {
+	t.Parallel()
+
+	r := repo.NewProcessedUpdateRepo(provideDatabase(t))
+
+	inserted, err := r.Insert(1)
+	require.NoError(t, err)
+	assert.True(t, inserted)
+
+	inserted, err = r.Insert(1)
+	require.NoError(t, err)
+	assert.False(t, inserted)
+
+	inserted, err = r.Insert(2)
+	require.NoError(t, err)
+	assert.True(t, inserted)
+}
+
+func TestProcessedUpdateRepo_DeleteOlderThan(t *testing.T) {
+	t.Parallel()
+
+	r := repo.NewProcessedUpdateRepo(provideDatabase(t))
+
+	_, err := r.Insert(1)
+	require.NoError(t, err)
+
+	// The result doesn't depend on the time zone of the given time
+	east := time.FixedZone("east", 2*60*60)
+	west := time.FixedZone("west", -7*60*60)
+
+	for _, zone := range []*time.Location{time.UTC, east, west} {
+		require.NoError(t, r.DeleteOlderThan(time.Now().Add(-time.Hour).In(zone)))
+
+		inserted, err := r.Insert(1)
+		require.NoError(t, err)
+		assert.False(t, inserted, zone.String())
+	}
+
+	require.NoError(t, r.DeleteOlderThan(time.Now().Add(time.Hour).In(west)))
+
+	inserted, err := r.Insert(1)
+	require.NoError(t, err)
+	assert.True(t, inserted)
+}
@@ -1,15 +1,23 @@
 package repo
 
 import (
+	"encoding/json"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/br0-space/bot/interfaces"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var mutexRoll sync.Mutex
 
+// sessionBatchSize is the number of rolls read at once when looking for the start of a session.
+const sessionBatchSize = 100
+
 // RollRepo implements the RollRepoInterface for database operations.
+// Secret rolls of the GM are left out of everything shown to the chat.
 type RollRepo struct {
 	BaseRepo
 }
@@ -32,11 +40,16 @@ func (r RollRepo) SaveRoll(roll *interfaces.Roll) error {
 	return r.tx.Create(roll).Error
 }
 
-// GetOverallStats returns overall statistics for all rolls.
-func (r RollRepo) GetOverallStats() (*interfaces.RollStatsStruct, error) {
+// GetOverallStats returns overall statistics for all rolls in a chat.
+func (r RollRepo) GetOverallStats(chatID int64) (*interfaces.RollStatsStruct, error) {
+	return r.GetOverallStatsSince(chatID, time.Time{})
+}
+
+// GetOverallStatsSince returns overall statistics for the rolls in a chat since the given time.
+func (r RollRepo) GetOverallStatsSince(chatID int64, since time.Time) (*interfaces.RollStatsStruct, error) {
 	var stats interfaces.RollStatsStruct
 
-	err := r.tx.Model(&interfaces.Roll{}).
+	query := r.tx.Model(&interfaces.Roll{}).
 		Select(`
 			COUNT(*) as total_rolls,
 			SUM(dice_count) as total_dice,
@@ -47,17 +60,21 @@ func (r RollRepo) GetOverallStats() (*interfaces.RollStatsStruct, error) {
 			SUM(CASE WHEN critical_failure THEN 1 ELSE 0 END) as critical_failures,
 			AVG(CASE WHEN success IS NOT NULL AND success = true THEN 100.0 ELSE 0 END) as success_rate
 		`).
-		Where("deleted_at IS NULL").
-		Scan(&stats).Error
-	if err != nil {
+		Where("chat_id = ? AND hidden = ? AND deleted_at IS NULL", chatID, false)
+
+	if !since.IsZero() {
+		query = query.Where("created_at >= ?", since.UTC())
+	}
+
+	if err := query.Scan(&stats).Error; err != nil {
 		return nil, err
 	}
 
 	return &stats, nil
 }
 
-// GetUserStats returns statistics for a specific user.
-func (r RollRepo) GetUserStats(userID int64) (*interfaces.RollStatsStruct, error) {
+// GetUserStats returns statistics for a specific user in a chat.
+func (r RollRepo) GetUserStats(chatID int64, userID int64) (*interfaces.RollStatsStruct, error) {
 	var stats interfaces.RollStatsStruct
 
 	err := r.tx.Model(&interfaces.Roll{}).
@@ -74,8 +91,8 @@ func (r RollRepo) GetUserStats(userID int64) (*interfaces.RollStatsStruct, error
 			AVG(CASE WHEN r.success IS NOT NULL AND r.success = true THEN 100.0 ELSE 0 END) as success_rate
 		`).
 		Table("rolls r").
-		Joins("INNER JOIN stats s ON r.user_id = s.user_id").
-		Where("r.user_id = ? AND r.deleted_at IS NULL", userID).
+		Joins("INNER JOIN stats s ON r.chat_id = s.chat_id AND r.user_id = s.user_id").
+		Where("r.chat_id = ? AND r.user_id = ? AND r.hidden = ? AND r.deleted_at IS NULL", chatID, userID, false).
 		Group("r.user_id, s.username").
 		Scan(&stats).Error
 	if err != nil {
@@ -85,22 +102,13 @@ func (r RollRepo) GetUserStats(userID int64) (*interfaces.RollStatsStruct, error
 	return &stats, nil
 }
 
-// GetUserIDByUsername looks up a user ID by username (case-insensitive exact match).
-func (r RollRepo) GetUserIDByUsername(username string) (int64, error) {
-	var stats interfaces.Stats
-
-	err := r.tx.Model(&interfaces.Stats{}).
-		Where("LOWER(username) = LOWER(?)", username).
-		First(&stats).Error
-	if err != nil {
-		return 0, err
-	}
-
-	return stats.UserID, nil
+// GetUserIDByUsername looks up a user ID by username (case-insensitive exact match) among the members of a chat.
+func (r RollRepo) GetUserIDByUsername(chatID int64, username string) (int64, error) {
+	return NewUserStatsRepo(r.tx).GetUserIDByUsername(chatID, username)
 }
 
-// GetLuckiestRoller returns the user with the highest average roll.
-func (r RollRepo) GetLuckiestRoller() (*interfaces.RollStatsStruct, error) {
+// GetLuckiestRoller returns the user with the highest average roll in a chat.
+func (r RollRepo) GetLuckiestRoller(chatID int64) (*interfaces.RollStatsStruct, error) {
 	var stats interfaces.RollStatsStruct
 
 	err := r.tx.Model(&interfaces.Roll{}).
@@ -116,8 +124,8 @@ func (r RollRepo) GetLuckiestRoller() (*interfaces.RollStatsStruct, error) {
 			SUM(CASE WHEN r.critical_failure THEN 1 ELSE 0 END) as critical_failures
 		`).
 		Table("rolls r").
-		Joins("INNER JOIN stats s ON r.user_id = s.user_id").
-		Where("r.deleted_at IS NULL").
+		Joins("INNER JOIN stats s ON r.chat_id = s.chat_id AND r.user_id = s.user_id").
+		Where("r.chat_id = ? AND r.hidden = ? AND r.deleted_at IS NULL", chatID, false).
 		Group("r.user_id, s.username").
 		Having("COUNT(*) >= 10"). // Minimum rolls to be considered
 		Order("average_roll DESC").
@@ -130,8 +138,8 @@ func (r RollRepo) GetLuckiestRoller() (*interfaces.RollStatsStruct, error) {
 	return &stats, nil
 }
 
-// GetUnluckiestRoller returns the user with the lowest average roll.
-func (r RollRepo) GetUnluckiestRoller() (*interfaces.RollStatsStruct, error) {
+// GetUnluckiestRoller returns the user with the lowest average roll in a chat.
+func (r RollRepo) GetUnluckiestRoller(chatID int64) (*interfaces.RollStatsStruct, error) {
 	var stats interfaces.RollStatsStruct
 
 	err := r.tx.Model(&interfaces.Roll{}).
@@ -147,8 +155,8 @@ func (r RollRepo) GetUnluckiestRoller() (*interfaces.RollStatsStruct, error) {
 			SUM(CASE WHEN r.critical_failure THEN 1 ELSE 0 END) as critical_failures
 		`).
 		Table("rolls r").
-		Joins("INNER JOIN stats s ON r.user_id = s.user_id").
-		Where("r.deleted_at IS NULL").
+		Joins("INNER JOIN stats s ON r.chat_id = s.chat_id AND r.user_id = s.user_id").
+		Where("r.chat_id = ? AND r.hidden = ? AND r.deleted_at IS NULL", chatID, false).
 		Group("r.user_id, s.username").
 		Having("COUNT(*) >= 10"). // Minimum rolls to be considered
 		Order("average_roll ASC").
@@ -161,19 +169,30 @@ func (r RollRepo) GetUnluckiestRoller() (*interfaces.RollStatsStruct, error) {
 	return &stats, nil
 }
 
-// GetTopRollers returns the top N most active rollers.
-func (r RollRepo) GetTopRollers(limit int) ([]interfaces.RollStatsStruct, error) {
+// GetTopRollers returns the top N most active rollers in a chat.
+func (r RollRepo) GetTopRollers(chatID int64, limit int) ([]interfaces.RollStatsStruct, error) {
+	return r.GetTopRollersSince(chatID, time.Time{}, limit)
+}
+
+// GetTopRollersSince returns the top N most active rollers in a chat since the given time.
+func (r RollRepo) GetTopRollersSince(chatID int64, since time.Time, limit int) ([]interfaces.RollStatsStruct, error) {
 	var stats []interfaces.RollStatsStruct
 
-	err := r.tx.Model(&interfaces.Roll{}).
+	query := r.tx.Model(&interfaces.Roll{}).
 		Select(`
 			r.user_id,
 			s.username,
 			COUNT(*) as total_rolls
 		`).
 		Table("rolls r").
-		Joins("INNER JOIN stats s ON r.user_id = s.user_id").
-		Where("r.deleted_at IS NULL").
+		Joins("INNER JOIN stats s ON r.chat_id = s.chat_id AND r.user_id = s.user_id").
+		Where("r.chat_id = ? AND r.hidden = ? AND r.deleted_at IS NULL", chatID, false)
+
+	if !since.IsZero() {
+		query = query.Where("r.created_at >= ?", since.UTC())
+	}
+
+	err := query.
 		Group("r.user_id, s.username").
 		Order("total_rolls DESC").
 		Limit(limit).
@@ -184,3 +203,259 @@ func (r RollRepo) GetTopRollers(limit int) ([]interfaces.RollStatsStruct, error)
 
 	return stats, nil
 }
+
+// GetStreaks returns the longest streaks and the biggest swing of each user in a chat since the given time,
+// ordered by user ID. The streaks are counted here instead of in SQL, as gaps-and-islands queries differ
+// between SQLite and PostgreSQL.
+//
+//nolint:cyclop // Counting all streaks in a single pass is easier to follow than splitting it up.
+func (r RollRepo) GetStreaks(chatID int64, since time.Time) ([]interfaces.RollStreakStruct, error) {
+	var rolls []struct {
+		UserID      int64
+		Username    string
+		Total       int
+		Success     *bool
+		CriticalHit bool
+	}
+
+	query := r.tx.Table("rolls r").
+		Select("r.user_id, s.username, r.total, r.success, r.critical_hit").
+		Joins("INNER JOIN stats s ON r.chat_id = s.chat_id AND r.user_id = s.user_id").
+		Where("r.chat_id = ? AND r.hidden = ? AND r.deleted_at IS NULL", chatID, false)
+
+	if !since.IsZero() {
+		query = query.Where("r.created_at >= ?", since.UTC())
+	}
+
+	if err := query.Order("r.user_id, r.created_at, r.id").Scan(&rolls).Error; err != nil {
+		return nil, err
+	}
+
+	var (
+		streaks                    []interfaces.RollStreakStruct
+		successes, failures, crits int
+		previous                   int
+	)
+
+	for i, roll := range rolls {
+		if i == 0 || rolls[i-1].UserID != roll.UserID {
+			streaks = append(streaks, interfaces.RollStreakStruct{UserID: roll.UserID, Username: roll.Username})
+			successes, failures, crits = 0, 0, 0
+			previous = roll.Total
+		}
+
+		current := &streaks[len(streaks)-1]
+
+		if abs(roll.Total-previous) > abs(current.SwingTo-current.SwingFrom) {
+			current.SwingFrom, current.SwingTo = previous, roll.Total
+		}
+
+		previous = roll.Total
+
+		if roll.Success != nil {
+			if *roll.Success {
+				successes++
+				failures = 0
+			} else {
+				failures++
+				successes = 0
+			}
+		}
+
+		if roll.CriticalHit {
+			crits++
+		} else {
+			crits = 0
+		}
+
+		current.SuccessStreak = max(current.SuccessStreak, successes)
+		current.FailureStreak = max(current.FailureStreak, failures)
+		current.CriticalStreak = max(current.CriticalStreak, crits)
+	}
+
+	return streaks, nil
+}
+
+// GetSessionStart returns the time of the first roll after the last break longer than gap,
+// or the zero time if there are no rolls in the chat. The rolls are read backwards in
+// batches, so only the rolls of the session have to be loaded.
+func (r RollRepo) GetSessionStart(chatID int64, gap time.Duration) (time.Time, error) {
+	var start time.Time
+
+	for {
+		var times []time.Time
+
+		query := r.tx.Model(&interfaces.Roll{}).Where("chat_id = ? AND hidden = ?", chatID, false)
+
+		if !start.IsZero() {
+			query = query.Where("created_at < ?", start.UTC())
+		}
+
+		err := query.
+			Order("created_at DESC").
+			Limit(sessionBatchSize).
+			Pluck("created_at", &times).Error
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		for _, t := range times {
+			if !start.IsZero() && start.Sub(t) > gap {
+				return start, nil
+			}
+
+			start = t
+		}
+
+		if len(times) < sessionBatchSize {
+			return start, nil
+		}
+	}
+}
+
+// GetRecentRolls returns the latest rolls in a chat, newest first. A user ID of 0 returns the rolls of all users.
+func (r RollRepo) GetRecentRolls(chatID int64, userID int64, limit int) ([]interfaces.RollHistoryStruct, error) {
+	var rolls []interfaces.RollHistoryStruct
+
+	query := r.tx.Table("rolls r").
+		Select("r.*, s.username").
+		Joins("LEFT JOIN stats s ON r.chat_id = s.chat_id AND r.user_id = s.user_id").
+		Where("r.chat_id = ? AND r.hidden = ? AND r.deleted_at IS NULL", chatID, false)
+
+	if userID != 0 {
+		query = query.Where("r.user_id = ?", userID)
+	}
+
+	err := query.
+		Order("r.created_at DESC, r.id DESC").
+		Limit(limit).
+		Scan(&rolls).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rolls, nil
+}
+
+// GetFaceCounts counts the faces rolled in a chat per die size, ordered by size.
+// A user ID of 0 counts the rolls of all users. Rolls without faces are from
+// before dice expressions, their results are all faces of a plain NdM roll.
+func (r RollRepo) GetFaceCounts(chatID int64, userID int64) ([]interfaces.RollFacesStruct, error) {
+	var rolls []interfaces.Roll
+
+	query := r.tx.Select("id", "dice_sides", "results", "faces").Where("chat_id = ? AND hidden = ?", chatID, false)
+
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	if err := query.Find(&rolls).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int][]int)
+
+	count := func(sides int, face int) {
+		if face < 1 || face > sides {
+			return
+		}
+
+		if counts[sides] == nil {
+			counts[sides] = make([]int, sides)
+		}
+
+		counts[sides][face-1]++
+	}
+
+	for _, roll := range rolls {
+		faces := make(map[int][]int)
+
+		if roll.Faces != "" {
+			if err := json.Unmarshal([]byte(roll.Faces), &faces); err != nil {
+				r.log.Warningf("Skipping faces of roll %d: %s", roll.ID, err)
+
+				continue
+			}
+		} else {
+			var results []int
+			if err := json.Unmarshal([]byte(roll.Results), &results); err != nil {
+				r.log.Warningf("Skipping results of roll %d: %s", roll.ID, err)
+
+				continue
+			}
+
+			faces[roll.DiceSides] = results
+		}
+
+		for sides, values := range faces {
+			for _, face := range values {
+				count(sides, face)
+			}
+		}
+	}
+
+	result := make([]interfaces.RollFacesStruct, 0, len(counts))
+	for sides, faceCounts := range counts {
+		result = append(result, interfaces.RollFacesStruct{Sides: sides, Counts: faceCounts})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Sides < result[j].Sides
+	})
+
+	return result, nil
+}
+
+// SaveMacro creates a macro or replaces the notation of an existing one.
+func (r RollRepo) SaveMacro(userID int64, name string, notation string) error {
+	mutexRoll.Lock()
+	defer mutexRoll.Unlock()
+
+	return r.tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"notation", "updated_at"}),
+	}).Create(&interfaces.RollMacro{
+		UserID:   userID,
+		Name:     name,
+		Notation: notation,
+	}).Error
+}
+
+// GetMacro returns a macro of a user, gorm.ErrRecordNotFound if it doesn't exist.
+func (r RollRepo) GetMacro(userID int64, name string) (*interfaces.RollMacro, error) {
+	var macro interfaces.RollMacro
+
+	if err := r.tx.Where("user_id = ? AND name = ?", userID, name).First(&macro).Error; err != nil {
+		return nil, err
+	}
+
+	return &macro, nil
+}
+
+// GetMacros returns all macros of a user, ordered by name.
+func (r RollRepo) GetMacros(userID int64) ([]interfaces.RollMacro, error) {
+	var macros []interfaces.RollMacro
+
+	err := r.tx.Where("user_id = ?", userID).Order("name").Find(&macros).Error
+
+	return macros, err
+}
+
+// DeleteMacro deletes a macro and reports whether it existed. It is deleted
+// permanently, so the name can be used for a new macro.
+func (r RollRepo) DeleteMacro(userID int64, name string) (bool, error) {
+	mutexRoll.Lock()
+	defer mutexRoll.Unlock()
+
+	result := r.tx.Unscoped().Where("user_id = ? AND name = ?", userID, name).Delete(&interfaces.RollMacro{})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
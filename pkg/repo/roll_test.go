@@ -0,0 +1,398 @@
+package repo_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestRollRepo_Macros(t *testing.T) {
+	t.Parallel()
+
+	r := repo.NewRollRepo(provideDatabase(t))
+
+	require.NoError(t, r.SaveMacro(10, "attack", "1d20+7 15"))
+	require.NoError(t, r.SaveMacro(10, "damage", "2d6+3"))
+	require.NoError(t, r.SaveMacro(20, "attack", "1d20+2"))
+
+	// Saving again replaces the notation
+	require.NoError(t, r.SaveMacro(10, "attack", "1d20+8 15"))
+
+	macro, err := r.GetMacro(10, "attack")
+	require.NoError(t, err)
+	assert.Equal(t, "1d20+8 15", macro.Notation)
+
+	macros, err := r.GetMacros(10)
+	require.NoError(t, err)
+	require.Len(t, macros, 2)
+	assert.Equal(t, "attack", macros[0].Name)
+	assert.Equal(t, "damage", macros[1].Name)
+
+	deleted, err := r.DeleteMacro(10, "attack")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = r.DeleteMacro(10, "attack")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	_, err = r.GetMacro(10, "attack")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// Other users keep their macros, deleted names can be used again
+	macro, err = r.GetMacro(20, "attack")
+	require.NoError(t, err)
+	assert.Equal(t, "1d20+2", macro.Notation)
+
+	require.NoError(t, r.SaveMacro(10, "attack", "1d20+9"))
+}
+
+func TestRollRepo_GetRecentRolls(t *testing.T) {
+	t.Parallel()
+
+	conn := provideDatabase(t)
+	r := repo.NewRollRepo(conn)
+	require.NoError(t, conn.Exec("INSERT INTO stats (chat_id, user_id, username, posts) VALUES (1, 10, '@alice', 1)").Error)
+
+	for i, roll := range []interfaces.Roll{
+		{ChatID: 1, UserID: 10, Notation: "1d20", Total: 1},
+		{ChatID: 1, UserID: 20, Notation: "1d20", Total: 2},
+		{ChatID: 1, UserID: 10, Notation: "2d6+3", Total: 3},
+		{ChatID: 2, UserID: 10, Notation: "1d20", Total: 4},
+		// Secret rolls of the GM aren't in the history
+		{ChatID: 1, UserID: 10, Notation: "1d20+5", Total: 5, Hidden: true},
+	} {
+		roll.Results = "[]"
+		roll.DiceCount = i + 1
+		require.NoError(t, r.SaveRoll(&roll))
+	}
+
+	rolls, err := r.GetRecentRolls(1, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, rolls, 3)
+	assert.Equal(t, 3, rolls[0].Total)
+	assert.Equal(t, "2d6+3", rolls[0].Notation)
+	assert.Equal(t, "@alice", rolls[0].Username)
+	assert.Equal(t, int64(20), rolls[1].UserID)
+	assert.Empty(t, rolls[1].Username)
+
+	rolls, err = r.GetRecentRolls(1, 10, 1)
+	require.NoError(t, err)
+	require.Len(t, rolls, 1)
+	assert.Equal(t, 3, rolls[0].Total)
+}
+
+func TestRollRepo_GetFaceCounts(t *testing.T) {
+	t.Parallel()
+
+	r := repo.NewRollRepo(provideDatabase(t))
+
+	for _, roll := range []interfaces.Roll{
+		// Rolls from before dice expressions only have results
+		{ChatID: 1, UserID: 10, DiceSides: 6, Results: "[1,6,6]"},
+		{ChatID: 1, UserID: 10, DiceSides: 20, Results: "[20]", Faces: `{"20":[3,20],"4":[4]}`},
+		{ChatID: 1, UserID: 20, DiceSides: 6, Results: "[2]", Faces: `{"6":[2]}`},
+		{ChatID: 2, UserID: 10, DiceSides: 6, Results: "[5]", Faces: `{"6":[5]}`},
+	} {
+		require.NoError(t, r.SaveRoll(&roll))
+	}
+
+	faces, err := r.GetFaceCounts(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.RollFacesStruct{
+		{Sides: 4, Counts: []int{0, 0, 0, 1}},
+		{Sides: 6, Counts: []int{1, 1, 0, 0, 0, 2}},
+		{Sides: 20, Counts: []int{0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}},
+	}, faces)
+
+	faces, err = r.GetFaceCounts(1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.RollFacesStruct{{Sides: 6, Counts: []int{0, 1, 0, 0, 0, 0}}}, faces)
+}
+
+// provideRollRepoWithUsers provides a roll repo with the users 10 (@alice) and 20 (@bob) in chat 1.
+func provideRollRepoWithUsers(t *testing.T) *repo.RollRepo {
+	t.Helper()
+
+	conn := provideDatabase(t)
+	r := repo.NewRollRepo(conn)
+	require.NoError(t, conn.Exec("INSERT INTO stats (chat_id, user_id, username, posts) VALUES (1, 10, '@alice', 1), (1, 20, '@bob', 1)").Error)
+
+	return r
+}
+
+func TestRollRepo_StatsSince(t *testing.T) {
+	t.Parallel()
+
+	r := provideRollRepoWithUsers(t)
+	// The database connection stores all times in UTC
+	now := time.Now().UTC()
+
+	for _, roll := range []interfaces.Roll{
+		{ChatID: 1, UserID: 10, Total: 4, Model: gorm.Model{CreatedAt: now.AddDate(0, 0, -40)}},
+		{ChatID: 1, UserID: 10, Total: 6, Model: gorm.Model{CreatedAt: now.AddDate(0, 0, -10)}},
+		{ChatID: 1, UserID: 20, Total: 8, Model: gorm.Model{CreatedAt: now.Add(-time.Hour)}},
+		{ChatID: 1, UserID: 20, Total: 10, Model: gorm.Model{CreatedAt: now}},
+	} {
+		roll.DiceCount = 1
+		roll.Results = "[]"
+		require.NoError(t, r.SaveRoll(&roll))
+	}
+
+	stats, err := r.GetOverallStats(1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, stats.TotalRolls)
+	assert.Equal(t, 4, stats.LowestRoll)
+
+	stats, err = r.GetOverallStatsSince(1, now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.TotalRolls)
+	assert.Equal(t, 6, stats.LowestRoll)
+
+	rollers, err := r.GetTopRollersSince(1, now.AddDate(0, 0, -7), 5)
+	require.NoError(t, err)
+	require.Len(t, rollers, 1)
+	assert.Equal(t, "@bob", rollers[0].Username)
+	assert.Equal(t, 2, rollers[0].TotalRolls)
+
+	rollers, err = r.GetTopRollers(1, 5)
+	require.NoError(t, err)
+	assert.Len(t, rollers, 2)
+}
+
+func TestRollRepo_GetStreaks(t *testing.T) {
+	t.Parallel()
+
+	r := provideRollRepoWithUsers(t)
+	now := time.Now().UTC()
+	success, failure := true, false
+
+	for i, roll := range []interfaces.Roll{
+		{UserID: 10, Total: 15, Success: &success},
+		{UserID: 20, Total: 1, Success: &failure},
+		{UserID: 10, Total: 20, Success: &success, CriticalHit: true},
+		{UserID: 10, Total: 7},
+		{UserID: 10, Total: 20, Success: &success, CriticalHit: true},
+		{UserID: 10, Total: 20, CriticalHit: true},
+		{UserID: 10, Total: 2, Success: &failure},
+		{UserID: 20, Total: 20, Success: &success},
+		{UserID: 20, Total: 3, Success: &failure},
+		{UserID: 20, Total: 4, Success: &failure},
+	} {
+		roll.ChatID = 1
+		roll.DiceCount = 1
+		roll.Results = "[]"
+		roll.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, r.SaveRoll(&roll))
+	}
+
+	// Rolls of other chats and users without stats don't count
+	require.NoError(t, r.SaveRoll(&interfaces.Roll{ChatID: 2, UserID: 10, DiceCount: 1, Results: "[]", Total: 100}))
+	require.NoError(t, r.SaveRoll(&interfaces.Roll{ChatID: 1, UserID: 30, DiceCount: 1, Results: "[]", Total: 100}))
+
+	streaks, err := r.GetStreaks(1, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.RollStreakStruct{
+		{UserID: 10, Username: "@alice", SuccessStreak: 3, FailureStreak: 1, CriticalStreak: 2, SwingFrom: 20, SwingTo: 2},
+		{UserID: 20, Username: "@bob", SuccessStreak: 1, FailureStreak: 2, CriticalStreak: 0, SwingFrom: 1, SwingTo: 20},
+	}, streaks)
+
+	// Only the last three rolls of @bob
+	streaks, err = r.GetStreaks(1, now.Add(7*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.RollStreakStruct{
+		{UserID: 20, Username: "@bob", SuccessStreak: 1, FailureStreak: 2, CriticalStreak: 0, SwingFrom: 20, SwingTo: 3},
+	}, streaks)
+}
+
+func TestRollRepo_GetSessionStart(t *testing.T) {
+	t.Parallel()
+
+	r := repo.NewRollRepo(provideDatabase(t))
+
+	start, err := r.GetSessionStart(1, 3*time.Hour)
+	require.NoError(t, err)
+	assert.True(t, start.IsZero())
+
+	now := time.Now().UTC()
+
+	for _, createdAt := range []time.Time{
+		now.Add(-26 * time.Hour),
+		now.Add(-5 * time.Hour),
+		now.Add(-3 * time.Hour),
+		now,
+	} {
+		require.NoError(t, r.SaveRoll(&interfaces.Roll{
+			Model:     gorm.Model{CreatedAt: createdAt},
+			ChatID:    1,
+			UserID:    10,
+			DiceCount: 1,
+			Results:   "[]",
+		}))
+	}
+
+	start, err = r.GetSessionStart(1, 3*time.Hour)
+	require.NoError(t, err)
+	assert.WithinDuration(t, now.Add(-5*time.Hour), start, time.Second)
+
+	start, err = r.GetSessionStart(1, time.Hour)
+	require.NoError(t, err)
+	assert.WithinDuration(t, now, start, time.Second)
+}
+
+func TestRollRepo_GetSessionStartOfLongSession(t *testing.T) {
+	t.Parallel()
+
+	r := repo.NewRollRepo(provideDatabase(t))
+	now := time.Now().UTC()
+
+	// A session with more rolls than are read at once, after a long break
+	createdAts := []time.Time{now.Add(-48 * time.Hour)}
+	for i := range 250 {
+		createdAts = append(createdAts, now.Add(-time.Duration(i)*time.Minute))
+	}
+
+	for _, createdAt := range createdAts {
+		require.NoError(t, r.SaveRoll(&interfaces.Roll{
+			Model:     gorm.Model{CreatedAt: createdAt},
+			ChatID:    1,
+			UserID:    10,
+			DiceCount: 1,
+			Results:   "[]",
+		}))
+	}
+
+	start, err := r.GetSessionStart(1, 3*time.Hour)
+	require.NoError(t, err)
+	assert.WithinDuration(t, now.Add(-249*time.Minute), start, time.Second)
+
+	start, err = r.GetSessionStart(1, 72*time.Hour)
+	require.NoError(t, err)
+	assert.WithinDuration(t, now.Add(-48*time.Hour), start, time.Second)
+}
+
+func TestRollRepo_HiddenRollsAreLeftOut(t *testing.T) {
+	t.Parallel()
+
+	r := provideRollRepoWithUsers(t)
+	now := time.Now().UTC()
+	success := true
+
+	for i, roll := range []interfaces.Roll{
+		{UserID: 10, Total: 12, Success: &success},
+		{UserID: 10, Total: 14, Success: &success},
+		{UserID: 20, Total: 8},
+	} {
+		roll.ChatID = 1
+		roll.DiceCount = 1
+		roll.DiceSides = 20
+		roll.Results = fmt.Sprintf("[%d]", roll.Total)
+		roll.Faces = fmt.Sprintf(`{"20":[%d]}`, roll.Total)
+		roll.CreatedAt = now.Add(time.Duration(i-10) * time.Hour)
+		require.NoError(t, r.SaveRoll(&roll))
+	}
+
+	results := func() []any {
+		overall, err := r.GetOverallStats(1)
+		require.NoError(t, err)
+
+		user, err := r.GetUserStats(1, 20)
+		require.NoError(t, err)
+
+		luckiest, err := r.GetLuckiestRoller(1)
+		require.NoError(t, err)
+
+		unluckiest, err := r.GetUnluckiestRoller(1)
+		require.NoError(t, err)
+
+		rollers, err := r.GetTopRollers(1, 5)
+		require.NoError(t, err)
+
+		streaks, err := r.GetStreaks(1, time.Time{})
+		require.NoError(t, err)
+
+		start, err := r.GetSessionStart(1, time.Hour)
+		require.NoError(t, err)
+
+		faces, err := r.GetFaceCounts(1, 0)
+		require.NoError(t, err)
+
+		history, err := r.GetRecentRolls(1, 0, 10)
+		require.NoError(t, err)
+
+		return []any{overall, user, luckiest, unluckiest, rollers, streaks, start.Unix(), faces, len(history)}
+	}
+
+	before := results()
+
+	failure := false
+	require.NoError(t, r.SaveRoll(&interfaces.Roll{
+		ChatID:          1,
+		UserID:          20,
+		DiceCount:       1,
+		DiceSides:       20,
+		Results:         "[1]",
+		Total:           1,
+		Success:         &failure,
+		CriticalFailure: true,
+		Faces:           `{"20":[1]}`,
+		Hidden:          true,
+	}))
+
+	assert.Equal(t, before, results())
+}
+
+// useTimeZone sets time.Local for a test. Tests using it can't run in parallel.
+func useTimeZone(t *testing.T, offset time.Duration) {
+	t.Helper()
+
+	local := time.Local
+	time.Local = time.FixedZone("test", int(offset.Seconds()))
+
+	t.Cleanup(func() {
+		time.Local = local
+	})
+}
+
+//nolint:paralleltest // changes time.Local
+func TestRollRepo_StatsSinceInLocalTime(t *testing.T) {
+	for _, offset := range []time.Duration{2 * time.Hour, -7 * time.Hour} {
+		t.Run(offset.String(), func(t *testing.T) {
+			useTimeZone(t, offset)
+
+			r := provideRollRepoWithUsers(t)
+			now := time.Now()
+
+			for _, createdAt := range []time.Time{now.Add(-3 * time.Hour), now} {
+				require.NoError(t, r.SaveRoll(&interfaces.Roll{
+					Model:     gorm.Model{CreatedAt: createdAt.UTC()},
+					ChatID:    1,
+					UserID:    10,
+					DiceCount: 1,
+					Results:   "[]",
+				}))
+			}
+
+			since := now.Add(-time.Hour)
+
+			stats, err := r.GetOverallStatsSince(1, since)
+			require.NoError(t, err)
+			assert.Equal(t, 1, stats.TotalRolls)
+
+			rollers, err := r.GetTopRollersSince(1, since, 5)
+			require.NoError(t, err)
+			require.Len(t, rollers, 1)
+			assert.Equal(t, 1, rollers[0].TotalRolls)
+
+			start, err := r.GetSessionStart(1, 2*time.Hour)
+			require.NoError(t, err)
+			assert.WithinDuration(t, now, start, time.Second)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +21,7 @@ func NewUserStatsRepo(tx *gorm.DB) *UserStatsRepo {
 		BaseRepo: NewBaseRepo(
 			tx,
 			&interfaces.Stats{
+				ChatID:   0,
 				UserID:   0,
 				Username: "",
 				Posts:    0,
@@ -29,18 +31,19 @@ func NewUserStatsRepo(tx *gorm.DB) *UserStatsRepo {
 	}
 }
 
-func (r UserStatsRepo) UpdateStats(userID int64, username string) error {
+func (r UserStatsRepo) UpdateStats(chatID int64, userID int64, username string) error {
 	mutexStats.Lock()
 	defer mutexStats.Unlock()
 
 	return r.tx.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "user_id"}},
+		Columns: []clause.Column{{Name: "chat_id"}, {Name: "user_id"}},
 		DoUpdates: clause.Assignments(map[string]any{
 			"username":  username,
 			"posts":     gorm.Expr("stats.posts + 1"),
 			"last_post": time.Now(),
 		}),
 	}).Create(&interfaces.Stats{
+		ChatID:   chatID,
 		UserID:   userID,
 		Username: username,
 		Posts:    1,
@@ -48,11 +51,23 @@ func (r UserStatsRepo) UpdateStats(userID int64, username string) error {
 	}).Error
 }
 
-func (r UserStatsRepo) GetKnownUsers() ([]interfaces.StatsUserStruct, error) {
+func (r UserStatsRepo) GetKnownChatIDs() ([]int64, error) {
+	var chatIDs []int64
+
+	err := r.tx.
+		Model(&interfaces.Stats{}).
+		Distinct("chat_id").
+		Pluck("chat_id", &chatIDs).
+		Error
+
+	return chatIDs, err
+}
+
+func (r UserStatsRepo) GetKnownUsers(chatID int64) ([]interfaces.StatsUserStruct, error) {
 	var records []interfaces.Stats
 
 	r.tx.
-		Where("user_id != 0").
+		Where("chat_id = ? AND user_id != 0", chatID).
 		Order("username asc").
 		Find(&records)
 
@@ -69,11 +84,11 @@ func (r UserStatsRepo) GetKnownUsers() ([]interfaces.StatsUserStruct, error) {
 	return users, nil
 }
 
-func (r UserStatsRepo) GetTopUsers() ([]interfaces.StatsUserStruct, error) {
+func (r UserStatsRepo) GetTopUsers(chatID int64) ([]interfaces.StatsUserStruct, error) {
 	var records []interfaces.Stats
 
 	r.tx.
-		Where("user_id != 0").
+		Where("chat_id = ? AND user_id != 0", chatID).
 		Order("posts desc").
 		Find(&records)
 
@@ -89,3 +104,21 @@ func (r UserStatsRepo) GetTopUsers() ([]interfaces.StatsUserStruct, error) {
 
 	return users, nil
 }
+
+// GetUserIDByUsername looks up a user ID by username (case-insensitive exact match) among the members of a chat.
+// Usernames are stored with a leading @, the username may be given with or without it.
+func (r UserStatsRepo) GetUserIDByUsername(chatID int64, username string) (int64, error) {
+	var record interfaces.Stats
+
+	username = strings.TrimPrefix(username, "@")
+
+	err := r.tx.
+		Where("chat_id = ? AND user_id != 0 AND LOWER(username) IN (LOWER(?), LOWER(?))", chatID, username, "@"+username).
+		First(&record).
+		Error
+	if err != nil {
+		return 0, err
+	}
+
+	return record.UserID, nil
+}
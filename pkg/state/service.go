@@ -11,11 +11,16 @@ import (
 
 var getLastPostLock = &sync.Mutex{}
 
+type lastPostKey struct {
+	chatID int64
+	userID int64
+}
+
 type Service struct {
 	log              logger.Interface
 	userStatsRepo    interfaces.UserStatsRepoInterface
 	messageStatsRepo interfaces.MessageStatsRepoInterface
-	lastPost         map[int64]time.Time
+	lastPost         map[lastPostKey]time.Time
 }
 
 func NewService(
@@ -26,7 +31,7 @@ func NewService(
 		log:              logger.New(),
 		userStatsRepo:    userStatsRepo,
 		messageStatsRepo: messageStatsRepo,
-		lastPost:         make(map[int64]time.Time),
+		lastPost:         make(map[lastPostKey]time.Time),
 	}
 	state.init()
 
@@ -38,11 +43,11 @@ func (s *Service) ProcessMessage(messageIn telegramclient.WebhookMessageStruct)
 	s.updateMessageStats(messageIn)
 }
 
-func (s *Service) GetLastPost(userID int64) *time.Time {
+func (s *Service) GetLastPost(chatID int64, userID int64) *time.Time {
 	getLastPostLock.Lock()
 	defer getLastPostLock.Unlock()
 
-	if lastPost, ok := s.lastPost[userID]; ok {
+	if lastPost, ok := s.lastPost[lastPostKey{chatID: chatID, userID: userID}]; ok {
 		return &lastPost
 	}
 
@@ -52,22 +57,34 @@ func (s *Service) GetLastPost(userID int64) *time.Time {
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func (s *Service) init() {
-	users, err := s.userStatsRepo.GetKnownUsers()
+	chatIDs, err := s.userStatsRepo.GetKnownChatIDs()
 	if err != nil {
-		s.log.Error("Error while getting known users from DB:", err)
+		s.log.Error("Error while getting known chats from DB:", err)
 
 		return
 	}
 
-	for _, user := range users {
-		s.lastPost[user.ID] = user.LastPost
+	for _, chatID := range chatIDs {
+		users, err := s.userStatsRepo.GetKnownUsers(chatID)
+		if err != nil {
+			s.log.Error("Error while getting known users from DB:", err)
+
+			return
+		}
+
+		for _, user := range users {
+			s.lastPost[lastPostKey{chatID: chatID, userID: user.ID}] = user.LastPost
+		}
 	}
 }
 
 func (s *Service) updateUserStats(messageIn telegramclient.WebhookMessageStruct) {
-	s.lastPost[messageIn.From.ID] = time.Now()
+	getLastPostLock.Lock()
+	s.lastPost[lastPostKey{chatID: messageIn.Chat.ID, userID: messageIn.From.ID}] = time.Now()
+	getLastPostLock.Unlock()
 
 	if err := s.userStatsRepo.UpdateStats(
+		messageIn.Chat.ID,
 		messageIn.From.ID,
 		messageIn.From.UsernameOrName(),
 	); err != nil {
@@ -77,6 +94,7 @@ func (s *Service) updateUserStats(messageIn telegramclient.WebhookMessageStruct)
 
 func (s *Service) updateMessageStats(messageIn telegramclient.WebhookMessageStruct) {
 	if err := s.messageStatsRepo.InsertMessageStats(
+		messageIn.Chat.ID,
 		messageIn.From.ID,
 		messageIn.WordCount(),
 	); err != nil {
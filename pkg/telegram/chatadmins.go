@@ -0,0 +1,124 @@
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	logger "github.com/br0-space/bot-logger"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+)
+
+const (
+	endpointGetChatAdministrators = "getChatAdministrators"
+	chatAdminsCacheTTL            = 5 * time.Minute
+	chatAdminsRequestTimeout      = 10 * time.Second
+	chatTypePrivate               = "private"
+)
+
+type chatAdmins struct {
+	userIDs   map[int64]bool
+	fetchedAt time.Time
+}
+
+// ChatAdminChecker decides whether a user may administrate the bot in a chat.
+// Everyone may do so in private chats and users from the configured admin list
+// everywhere, all others need to be an administrator of the chat. Chat administrators are fetched from
+// Telegram and cached for a few minutes.
+type ChatAdminChecker struct {
+	log          logger.Interface
+	cfg          *telegramclient.ConfigStruct
+	httpClient   *http.Client
+	adminUserIDs map[int64]bool
+	lock         sync.Mutex
+	cache        map[int64]chatAdmins
+}
+
+func NewChatAdminChecker(config *telegramclient.ConfigStruct, adminUserIDs []int64) *ChatAdminChecker {
+	admins := make(map[int64]bool, len(adminUserIDs))
+	for _, userID := range adminUserIDs {
+		admins[userID] = true
+	}
+
+	return &ChatAdminChecker{
+		log: logger.New(),
+		cfg: config,
+		httpClient: &http.Client{
+			Timeout: chatAdminsRequestTimeout,
+		},
+		adminUserIDs: admins,
+		lock:         sync.Mutex{},
+		cache:        make(map[int64]chatAdmins),
+	}
+}
+
+func (c *ChatAdminChecker) IsAdmin(messageIn telegramclient.WebhookMessageStruct) (bool, error) {
+	chatID := messageIn.Chat.ID
+	userID := messageIn.From.ID
+
+	if messageIn.Chat.Type == chatTypePrivate || c.adminUserIDs[userID] {
+		return true, nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	admins, ok := c.cache[chatID]
+	if !ok || time.Since(admins.fetchedAt) > chatAdminsCacheTTL {
+		userIDs, err := c.getChatAdministrators(chatID)
+		if err != nil {
+			return false, err
+		}
+
+		admins = chatAdmins{
+			userIDs:   userIDs,
+			fetchedAt: time.Now(),
+		}
+		c.cache[chatID] = admins
+	}
+
+	return admins.userIDs[userID], nil
+}
+
+func (c *ChatAdminChecker) getChatAdministrators(chatID int64) (map[int64]bool, error) {
+	requestBytes, err := json.Marshal(getChatAdministratorsRequest{
+		ChatID: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf(c.cfg.BaseURL, c.cfg.APIKey) + endpointGetChatAdministrators
+
+	c.log.Debugf("Sending POST request to %s", url)
+
+	response, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(requestBytes)) //nolint:noctx
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody := &getChatAdministratorsResponse{
+		Ok:          false,
+		Result:      []chatMember{},
+		ErrorCode:   0,
+		Description: "",
+	}
+	if err := json.NewDecoder(response.Body).Decode(responseBody); err != nil {
+		return nil, fmt.Errorf("getChatAdministrators failed with %s: unable to decode response body", response.Status)
+	}
+
+	if !responseBody.Ok {
+		return nil, fmt.Errorf("getChatAdministrators failed with %d: %s", responseBody.ErrorCode, responseBody.Description)
+	}
+
+	userIDs := make(map[int64]bool, len(responseBody.Result))
+	for _, member := range responseBody.Result {
+		userIDs[member.User.ID] = true
+	}
+
+	return userIDs, nil
+}
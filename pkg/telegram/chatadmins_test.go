@@ -0,0 +1,88 @@
+package telegram_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/pkg/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChatAdministratorsAPI serves getChatAdministrators and counts the requests.
+type fakeChatAdministratorsAPI struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (f *fakeChatAdministratorsAPI) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+
+	var body struct {
+		ChatID int64 `json:"chat_id"` //nolint:tagliatelle
+	}
+	_ = json.NewDecoder(req.Body).Decode(&body)
+
+	if body.ChatID != 789 {
+		_, _ = res.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+
+		return
+	}
+
+	_, _ = res.Write([]byte(`{"ok":true,"result":[{"status":"creator","user":{"id":1}},{"status":"administrator","user":{"id":2}}]}`))
+}
+
+func newTestMessage(chatID int64, chatType string, userID int64) telegramclient.WebhookMessageStruct {
+	message := telegramclient.TestWebhookMessage("/disable ping")
+	message.Chat.ID = chatID
+	message.Chat.Type = chatType
+	message.From.ID = userID
+
+	return message
+}
+
+func TestChatAdminChecker_IsAdmin(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeChatAdministratorsAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	checker := telegram.NewChatAdminChecker(provideConfig(server, 789), []int64{42})
+
+	isAdmin, err := checker.IsAdmin(newTestMessage(789, "supergroup", 1))
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	isAdmin, err = checker.IsAdmin(newTestMessage(789, "supergroup", 2))
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	isAdmin, err = checker.IsAdmin(newTestMessage(789, "supergroup", 3))
+	require.NoError(t, err)
+	assert.False(t, isAdmin)
+
+	isAdmin, err = checker.IsAdmin(newTestMessage(999, "supergroup", 42))
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	_, err = checker.IsAdmin(newTestMessage(999, "supergroup", 1))
+	require.Error(t, err)
+
+	// Everyone administrates their private chat, without asking Telegram
+	isAdmin, err = checker.IsAdmin(newTestMessage(1000, "private", 3))
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	assert.Equal(t, 2, api.requests)
+}
@@ -0,0 +1,33 @@
+package telegram
+
+// ChatFilter decides which chats the bot accepts messages from.
+// The configured chat is always allowed; without any configured chats, all chats are allowed.
+type ChatFilter struct {
+	allowed map[int64]struct{}
+}
+
+func NewChatFilter(chatID int64, allowedChatIDs []int64) *ChatFilter {
+	allowed := make(map[int64]struct{}, len(allowedChatIDs)+1)
+
+	if chatID != 0 {
+		allowed[chatID] = struct{}{}
+	}
+
+	for _, allowedChatID := range allowedChatIDs {
+		allowed[allowedChatID] = struct{}{}
+	}
+
+	return &ChatFilter{
+		allowed: allowed,
+	}
+}
+
+func (f *ChatFilter) Allows(chatID int64) bool {
+	if len(f.allowed) == 0 {
+		return true
+	}
+
+	_, ok := f.allowed[chatID]
+
+	return ok
+}
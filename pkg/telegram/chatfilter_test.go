@@ -0,0 +1,31 @@
+package telegram_test
+
+import (
+	"testing"
+
+	"github.com/br0-space/bot/pkg/telegram"
+	"github.com/stretchr/testify/assert"
+)
+
+var chatFilterTests = []struct {
+	chatID         int64
+	allowedChatIDs []int64
+	in             int64
+	expected       bool
+}{
+	{0, nil, 789, true},
+	{789, nil, 789, true},
+	{789, nil, 999, false},
+	{789, []int64{999}, 999, true},
+	{0, []int64{999}, 789, false},
+	{0, []int64{-100123}, -100123, true},
+}
+
+func TestChatFilter_Allows(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range chatFilterTests {
+		filter := telegram.NewChatFilter(tt.chatID, tt.allowedChatIDs)
+		assert.Equal(t, tt.expected, filter.Allows(tt.in), "chat %d", tt.in)
+	}
+}
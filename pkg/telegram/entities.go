@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"sync"
+
+	"github.com/br0-space/bot/interfaces"
+)
+
+const entitiesCacheSize = 1000
+
+type entitiesKey struct {
+	chatID    int64
+	messageID int64
+}
+
+// EntitiesStore keeps the entities of the most recent messages in memory.
+// It is filled right before a message is processed and read by the matchers
+// while it is processed, so only a small number of messages has to be kept.
+// The messages of a chat are processed one after another, so it always holds
+// the entities of the version being processed, not those of a queued edit.
+type EntitiesStore struct {
+	lock     sync.Mutex
+	entities map[entitiesKey][]interfaces.MessageEntityStruct
+	ring     []entitiesKey
+	next     int
+}
+
+func NewEntitiesStore() *EntitiesStore {
+	return &EntitiesStore{
+		lock:     sync.Mutex{},
+		entities: make(map[entitiesKey][]interfaces.MessageEntityStruct, entitiesCacheSize),
+		ring:     make([]entitiesKey, 0, entitiesCacheSize),
+		next:     0,
+	}
+}
+
+func (s *EntitiesStore) Put(chatID int64, messageID int64, entities []interfaces.MessageEntityStruct) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := entitiesKey{chatID: chatID, messageID: messageID}
+
+	if _, exists := s.entities[key]; !exists {
+		if len(s.ring) < cap(s.ring) {
+			s.ring = append(s.ring, key)
+		} else {
+			delete(s.entities, s.ring[s.next])
+			s.ring[s.next] = key
+			s.next = (s.next + 1) % len(s.ring)
+		}
+	}
+
+	s.entities[key] = entities
+}
+
+func (s *EntitiesStore) Get(chatID int64, messageID int64) []interfaces.MessageEntityStruct {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.entities[entitiesKey{chatID: chatID, messageID: messageID}]
+}
@@ -0,0 +1,85 @@
+package telegram_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdate_DecodesEntities(t *testing.T) {
+	t.Parallel()
+
+	var update telegram.Update
+	require.NoError(t, json.Unmarshal([]byte(`{"update_id":1,"message":{"message_id":2,"chat":{"id":789},"text":"Anna++",`+
+		`"entities":[{"type":"text_mention","offset":0,"length":4,"user":{"id":42,"first_name":"Anna"}}]}}`), &update))
+
+	require.NotNil(t, update.Message)
+	assert.Equal(t, "Anna++", update.Message.TextOrCaption())
+	assert.Equal(t, []interfaces.MessageEntityStruct{{
+		Type:   "text_mention",
+		Offset: 0,
+		Length: 4,
+		User:   &telegramclient.WebhookMessageUserStruct{ID: 42, FirstName: "Anna"},
+	}}, update.Message.TextOrCaptionEntities())
+
+	update = telegram.Update{}
+	require.NoError(t, json.Unmarshal([]byte(`{"update_id":3,"message":{"message_id":4,"chat":{"id":789},"caption":"@anna++",`+
+		`"entities":[],"caption_entities":[{"type":"mention","offset":0,"length":5}]}}`), &update))
+
+	assert.Equal(t, []interfaces.MessageEntityStruct{{Type: "mention", Offset: 0, Length: 5}}, update.Message.TextOrCaptionEntities())
+}
+
+func TestUpdate_MessageOrEdit(t *testing.T) {
+	t.Parallel()
+
+	message := &telegram.Message{WebhookMessageStruct: telegramclient.TestWebhookMessage("foo++")}
+
+	got, edited := telegram.Update{ID: 1, Message: message}.MessageOrEdit()
+	assert.Same(t, message, got)
+	assert.False(t, edited)
+
+	got, edited = telegram.Update{ID: 2, EditedMessage: message}.MessageOrEdit()
+	assert.Same(t, message, got)
+	assert.True(t, edited)
+
+	got, edited = telegram.Update{ID: 3}.MessageOrEdit()
+	assert.Nil(t, got)
+	assert.False(t, edited)
+}
+
+func TestEntitiesStore(t *testing.T) {
+	t.Parallel()
+
+	store := telegram.NewEntitiesStore()
+	entities := []interfaces.MessageEntityStruct{{Type: "mention", Offset: 0, Length: 5}}
+
+	store.Put(789, 1, entities)
+
+	assert.Equal(t, entities, store.Get(789, 1))
+	assert.Nil(t, store.Get(789, 2))
+	assert.Nil(t, store.Get(790, 1))
+
+	// An edit without entities replaces those of the previous version
+	store.Put(789, 1, nil)
+	assert.Empty(t, store.Get(789, 1))
+}
+
+func TestEntitiesStore_EvictsOldestMessage(t *testing.T) {
+	t.Parallel()
+
+	store := telegram.NewEntitiesStore()
+	entities := []interfaces.MessageEntityStruct{{Type: "mention", Offset: 0, Length: 5}}
+
+	for messageID := range int64(1001) {
+		store.Put(789, messageID, entities)
+	}
+
+	assert.Nil(t, store.Get(789, 0))
+	assert.Equal(t, entities, store.Get(789, 1))
+	assert.Equal(t, entities, store.Get(789, 1000))
+}
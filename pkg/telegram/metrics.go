@@ -0,0 +1,13 @@
+package telegram
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var rejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "bot",
+	Subsystem: "webhook",
+	Name:      "rejected_total",
+	Help:      "Number of webhook requests rejected because of a wrong secret token or source address.",
+}, []string{"reason"})
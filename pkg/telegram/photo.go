@@ -0,0 +1,91 @@
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	logger "github.com/br0-space/bot-logger"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+)
+
+const photoRequestTimeout = 30 * time.Second
+
+// PhotoSender uploads generated images to Telegram. The telegram client can
+// only send photos that are already available via URL or file ID.
+type PhotoSender struct {
+	log        logger.Interface
+	cfg        *telegramclient.ConfigStruct
+	httpClient *http.Client
+}
+
+func NewPhotoSender(config *telegramclient.ConfigStruct) *PhotoSender {
+	return &PhotoSender{
+		log: logger.New(),
+		cfg: config,
+		httpClient: &http.Client{
+			Timeout: photoRequestTimeout,
+		},
+	}
+}
+
+func (s *PhotoSender) SendPhoto(chatID int64, replyToMessageID int64, filename string, photo []byte, caption string) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	fields := map[string]string{
+		"chat_id": strconv.FormatInt(chatID, 10),
+		"caption": caption,
+	}
+	if replyToMessageID != 0 {
+		fields["reply_to_message_id"] = strconv.FormatInt(replyToMessageID, 10)
+	}
+
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+
+	part, err := writer.CreateFormFile("photo", filename)
+	if err != nil {
+		return err
+	}
+
+	if _, err := part.Write(photo); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf(s.cfg.BaseURL, s.cfg.APIKey) + s.cfg.EndpointSendPhoto
+
+	s.log.Debugf("Sending POST request to %s", url)
+
+	response, err := s.httpClient.Post(url, writer.FormDataContentType(), body) //nolint:noctx
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	responseBody := &sendPhotoResponse{
+		Ok:          false,
+		ErrorCode:   0,
+		Description: "",
+	}
+	if err := json.NewDecoder(response.Body).Decode(responseBody); err != nil {
+		return fmt.Errorf("sendPhoto failed with %s: unable to decode response body", response.Status)
+	}
+
+	if !responseBody.Ok {
+		return fmt.Errorf("sendPhoto failed with %d: %s", responseBody.ErrorCode, responseBody.Description)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package telegram_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/br0-space/bot/pkg/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhotoSender_SendPhoto(t *testing.T) {
+	t.Parallel()
+
+	var (
+		path    string
+		fields  map[string]string
+		photo   []byte
+		handled bool
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		handled = true
+		path = req.URL.Path
+
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		fields = map[string]string{
+			"chat_id":             req.FormValue("chat_id"),
+			"reply_to_message_id": req.FormValue("reply_to_message_id"),
+			"caption":             req.FormValue("caption"),
+		}
+
+		file, _, err := req.FormFile("photo")
+		if err == nil {
+			photo, _ = io.ReadAll(file)
+		}
+
+		_, _ = res.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	cfg := provideConfig(server, 789)
+	cfg.EndpointSendPhoto = "sendPhoto"
+
+	err := telegram.NewPhotoSender(cfg).SendPhoto(789, 123, "activity.png", []byte("png"), "Aktivität")
+	require.NoError(t, err)
+	require.True(t, handled)
+	assert.Equal(t, "/botsecret/sendPhoto", path)
+	assert.Equal(t, map[string]string{
+		"chat_id":             "789",
+		"reply_to_message_id": "123",
+		"caption":             "Aktivität",
+	}, fields)
+	assert.Equal(t, []byte("png"), photo)
+}
+
+func TestPhotoSender_SendPhotoError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, _ *http.Request) {
+		res.WriteHeader(http.StatusBadRequest)
+		_, _ = res.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+	}))
+	defer server.Close()
+
+	err := telegram.NewPhotoSender(provideConfig(server, 789)).SendPhoto(999, 0, "activity.png", []byte("png"), "")
+	require.EqualError(t, err, "sendPhoto failed with 400: Bad Request: chat not found")
+}
@@ -0,0 +1,218 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	logger "github.com/br0-space/bot-logger"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const (
+	defaultEndpointGetUpdates    = "getUpdates"
+	defaultEndpointDeleteWebhook = "deleteWebhook"
+	requestTimeoutBuffer         = 10 * time.Second
+	retryDelay                   = 5 * time.Second
+	acknowledgeTimeout           = 5 * time.Second
+)
+
+// Poller fetches updates from Telegram via long polling (getUpdates) and
+// passes every update to the same function the webhook handler would call.
+// It is an alternative for environments without a public HTTPS URL.
+type Poller struct {
+	log        logger.Interface
+	cfg        *telegramclient.ConfigStruct
+	pollingCfg interfaces.PollingConfigStruct
+	httpClient *http.Client
+	fn         func(update Update)
+	offset     int64
+}
+
+func NewPoller(
+	config *telegramclient.ConfigStruct,
+	pollingConfig interfaces.PollingConfigStruct,
+	fn func(update Update),
+) *Poller {
+	return &Poller{
+		log:        logger.New(),
+		cfg:        config,
+		pollingCfg: pollingConfig,
+		httpClient: &http.Client{
+			Timeout: time.Duration(pollingConfig.Timeout)*time.Second + requestTimeoutBuffer,
+		},
+		fn:     fn,
+		offset: 0,
+	}
+}
+
+// Run removes a possibly configured webhook (Telegram refuses getUpdates
+// while one is set) and polls for updates until the context is cancelled.
+// Before returning, it confirms the processed updates to Telegram so they are
+// not delivered again after a restart.
+func (p *Poller) Run(ctx context.Context) error {
+	if err := p.deleteWebhook(ctx); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		if _, err := p.Poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+
+			p.log.Error("Error while polling Telegram updates:", err)
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+		}
+	}
+
+	p.acknowledge(context.WithoutCancel(ctx))
+
+	return nil
+}
+
+// Poll fetches one batch of updates, advances the offset past all of them
+// and processes the messages. It returns the number of processed messages.
+func (p *Poller) Poll(ctx context.Context) (int, error) {
+	updates, err := p.getUpdates(ctx, p.pollingCfg.Timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+
+	for _, update := range updates {
+		if update.ID >= p.offset {
+			p.offset = update.ID + 1
+		}
+
+		if message, _ := update.MessageOrEdit(); message == nil {
+			p.log.Debugf("Ignoring update %d without message", update.ID)
+
+			continue
+		}
+
+		p.fn(update)
+
+		processed++
+	}
+
+	return processed, nil
+}
+
+// Offset returns the ID of the next update that will be requested.
+func (p *Poller) Offset() int64 {
+	return p.offset
+}
+
+// acknowledge confirms all updates before the current offset. Telegram only
+// forgets updates once a getUpdates call with a higher offset was made.
+func (p *Poller) acknowledge(ctx context.Context) {
+	if p.offset == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, acknowledgeTimeout)
+	defer cancel()
+
+	if _, err := p.getUpdates(ctx, 0); err != nil {
+		p.log.Error("Error while acknowledging Telegram updates:", err)
+	}
+}
+
+func (p *Poller) getUpdates(ctx context.Context, timeout uint) ([]Update, error) {
+	requestBytes, err := json.Marshal(getUpdatesRequest{
+		Offset:         p.offset,
+		Timeout:        timeout,
+		Limit:          p.pollingCfg.Limit,
+		AllowedUpdates: []string{"message", "edited_message"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody := &getUpdatesResponse{
+		Ok:          false,
+		Result:      []Update{},
+		ErrorCode:   0,
+		Description: "",
+	}
+	if err := p.post(ctx, p.endpointGetUpdates(), requestBytes, responseBody); err != nil {
+		return nil, err
+	}
+
+	if !responseBody.Ok {
+		return nil, fmt.Errorf("getUpdates failed with %d: %s", responseBody.ErrorCode, responseBody.Description)
+	}
+
+	return responseBody.Result, nil
+}
+
+func (p *Poller) deleteWebhook(ctx context.Context) error {
+	p.log.Info("Removing Telegram webhook to enable long polling")
+
+	responseBody := &deleteWebhookResponse{
+		Ok:          false,
+		Result:      false,
+		ErrorCode:   0,
+		Description: "",
+	}
+	if err := p.post(ctx, p.endpointDeleteWebhook(), []byte("{}"), responseBody); err != nil {
+		return err
+	}
+
+	if !responseBody.Ok {
+		return fmt.Errorf("deleteWebhook failed with %d: %s", responseBody.ErrorCode, responseBody.Description)
+	}
+
+	return nil
+}
+
+func (p *Poller) post(ctx context.Context, endpoint string, requestBytes []byte, responseBody any) error {
+	url := fmt.Sprintf(p.cfg.BaseURL, p.cfg.APIKey) + endpoint
+
+	p.log.Debugf("Sending POST request to %s", url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(requestBytes))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	response, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if err := json.NewDecoder(response.Body).Decode(responseBody); err != nil {
+		return fmt.Errorf("%s failed with %s: unable to decode response body", endpoint, response.Status)
+	}
+
+	return nil
+}
+
+func (p *Poller) endpointGetUpdates() string {
+	if p.pollingCfg.EndpointGetUpdates != "" {
+		return p.pollingCfg.EndpointGetUpdates
+	}
+
+	return defaultEndpointGetUpdates
+}
+
+func (p *Poller) endpointDeleteWebhook() string {
+	if p.pollingCfg.EndpointDeleteWebhook != "" {
+		return p.pollingCfg.EndpointDeleteWebhook
+	}
+
+	return defaultEndpointDeleteWebhook
+}
@@ -0,0 +1,173 @@
+package telegram_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTelegramAPI serves getUpdates and deleteWebhook like the Telegram Bot API
+// and records the offsets the poller requested.
+type fakeTelegramAPI struct {
+	mu             sync.Mutex
+	updates        []telegram.Update
+	offsets        []int64
+	webhookDeleted bool
+}
+
+func (f *fakeTelegramAPI) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/deleteWebhook"):
+		f.webhookDeleted = true
+
+		_, _ = res.Write([]byte(`{"ok":true,"result":true}`))
+	case strings.HasSuffix(req.URL.Path, "/getUpdates"):
+		var body struct {
+			Offset int64 `json:"offset"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		f.offsets = append(f.offsets, body.Offset)
+
+		result := make([]telegram.Update, 0)
+
+		for _, update := range f.updates {
+			if update.ID >= body.Offset {
+				result = append(result, update)
+			}
+		}
+
+		_ = json.NewEncoder(res).Encode(map[string]any{"ok": true, "result": result})
+	default:
+		http.NotFound(res, req)
+	}
+}
+
+func newTestUpdate(id int64, chatID int64, text string) telegram.Update {
+	message := telegramclient.TestWebhookMessage(text)
+	message.Chat.ID = chatID
+
+	return telegram.Update{ID: id, Message: &telegram.Message{WebhookMessageStruct: message}}
+}
+
+func provideConfig(server *httptest.Server, chatID int64) *telegramclient.ConfigStruct {
+	return &telegramclient.ConfigStruct{
+		APIKey:              "secret",
+		WebhookURL:          "",
+		BaseURL:             server.URL + "/bot%s/",
+		EndpointSetWebhook:  "",
+		EndpointSendMessage: "",
+		EndpointSendPhoto:   "",
+		ChatID:              chatID,
+	}
+}
+
+func providePollingConfig() interfaces.PollingConfigStruct {
+	return interfaces.PollingConfigStruct{
+		EndpointGetUpdates:    "getUpdates",
+		EndpointDeleteWebhook: "deleteWebhook",
+		Timeout:               0,
+		Limit:                 100,
+	}
+}
+
+func TestPoller_Poll(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeTelegramAPI{updates: []telegram.Update{
+		newTestUpdate(10, 789, "foo"),
+		{ID: 11, Message: nil},
+		newTestUpdate(12, 789, "bar"),
+	}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	var texts []string
+
+	poller := telegram.NewPoller(provideConfig(server, 0), providePollingConfig(), func(update telegram.Update) {
+		texts = append(texts, update.Message.Text)
+	})
+
+	processed, err := poller.Poll(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	assert.Equal(t, []string{"foo", "bar"}, texts)
+	assert.Equal(t, int64(13), poller.Offset())
+
+	processed, err = poller.Poll(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 0, processed)
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	assert.Equal(t, []int64{0, 13}, api.offsets)
+}
+
+func TestPoller_PollError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, _ *http.Request) {
+		res.WriteHeader(http.StatusConflict)
+		_, _ = res.Write([]byte(`{"ok":false,"error_code":409,"description":"Conflict: can't use getUpdates method while webhook is active"}`))
+	}))
+	defer server.Close()
+
+	poller := telegram.NewPoller(provideConfig(server, 0), providePollingConfig(), func(_ telegram.Update) {})
+
+	_, err := poller.Poll(t.Context())
+	require.EqualError(t, err, "getUpdates failed with 409: Conflict: can't use getUpdates method while webhook is active")
+}
+
+func TestPoller_Run(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeTelegramAPI{updates: []telegram.Update{
+		newTestUpdate(1, 789, "foo"),
+	}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	received := make(chan string, 1)
+
+	poller := telegram.NewPoller(provideConfig(server, 0), providePollingConfig(), func(update telegram.Update) {
+		received <- update.Message.Text
+
+		cancel()
+	})
+
+	done := make(chan error)
+
+	go func() {
+		done <- poller.Run(ctx)
+	}()
+
+	select {
+	case text := <-received:
+		assert.Equal(t, "foo", text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("poller did not deliver the message")
+	}
+
+	require.NoError(t, <-done)
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	assert.True(t, api.webhookDeleted)
+	assert.Equal(t, int64(2), api.offsets[len(api.offsets)-1], "processed updates were not acknowledged")
+}
@@ -0,0 +1,94 @@
+package telegram
+
+import (
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+// Update mimics a single entry of Telegram's update list
+// https://core.telegram.org/bots/api#update
+type Update struct {
+	ID            int64    `json:"update_id"` //nolint:tagliatelle
+	Message       *Message `json:"message"`
+	EditedMessage *Message `json:"edited_message"` //nolint:tagliatelle
+}
+
+// MessageOrEdit returns the new message of an update, or the edited message
+// and true if it is an edit. The message is nil for other kinds of updates.
+func (u Update) MessageOrEdit() (*Message, bool) {
+	if u.Message != nil {
+		return u.Message, false
+	}
+
+	return u.EditedMessage, u.EditedMessage != nil
+}
+
+// Message extends the webhook message of the client by its entities
+// https://core.telegram.org/bots/api#message
+type Message struct {
+	telegramclient.WebhookMessageStruct
+
+	Entities        []interfaces.MessageEntityStruct `json:"entities"`
+	CaptionEntities []interfaces.MessageEntityStruct `json:"caption_entities"` //nolint:tagliatelle
+}
+
+// TextOrCaptionEntities returns the entities belonging to TextOrCaption.
+func (m Message) TextOrCaptionEntities() []interfaces.MessageEntityStruct {
+	if len(m.Text) > 0 {
+		return m.Entities
+	}
+
+	return m.CaptionEntities
+}
+
+type getUpdatesRequest struct {
+	Offset         int64    `json:"offset"`
+	Timeout        uint     `json:"timeout"`
+	Limit          uint     `json:"limit,omitempty"`
+	AllowedUpdates []string `json:"allowed_updates"` //nolint:tagliatelle
+}
+
+type getUpdatesResponse struct {
+	Ok          bool     `json:"ok"`
+	Result      []Update `json:"result"`
+	ErrorCode   int      `json:"error_code"` //nolint:tagliatelle
+	Description string   `json:"description"`
+}
+
+type setWebhookResponse struct {
+	Ok          bool   `json:"ok"`
+	Result      bool   `json:"result"`
+	ErrorCode   int    `json:"error_code"` //nolint:tagliatelle
+	Description string `json:"description"`
+}
+
+type deleteWebhookResponse struct {
+	Ok          bool   `json:"ok"`
+	Result      bool   `json:"result"`
+	ErrorCode   int    `json:"error_code"` //nolint:tagliatelle
+	Description string `json:"description"`
+}
+
+type getChatAdministratorsRequest struct {
+	ChatID int64 `json:"chat_id"` //nolint:tagliatelle
+}
+
+type chatMember struct {
+	Status string `json:"status"`
+	User   struct {
+		ID int64 `json:"id"`
+	} `json:"user"`
+}
+
+type getChatAdministratorsResponse struct {
+	Ok          bool         `json:"ok"`
+	Result      []chatMember `json:"result"`
+	ErrorCode   int          `json:"error_code"` //nolint:tagliatelle
+	Description string       `json:"description"`
+}
+
+type sendPhotoResponse struct {
+	Ok          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"` //nolint:tagliatelle
+	Description string `json:"description"`
+}
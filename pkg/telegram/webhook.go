@@ -0,0 +1,231 @@
+package telegram
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	logger "github.com/br0-space/bot-logger"
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+)
+
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+const (
+	rejectReasonSecretToken   = "secret_token"
+	rejectReasonSourceAddress = "source_address"
+)
+
+// WebhookHandler receives updates pushed by Telegram. Unlike the handler of
+// the telegram client, it keeps the complete update including its ID.
+// Requests without the configured secret token or from addresses outside the
+// allowed networks are rejected before they are parsed.
+type WebhookHandler struct {
+	log         logger.Interface
+	cfg         *telegramclient.ConfigStruct
+	webhookCfg  interfaces.WebhookConfigStruct
+	allowedNets []*net.IPNet
+	proxyNets   []*net.IPNet
+	fn          func(update Update)
+}
+
+func NewWebhookHandler(
+	config *telegramclient.ConfigStruct,
+	webhookConfig interfaces.WebhookConfigStruct,
+	fn func(update Update),
+) *WebhookHandler {
+	log := logger.New()
+
+	handler := &WebhookHandler{
+		log:         log,
+		cfg:         config,
+		webhookCfg:  webhookConfig,
+		allowedNets: parseCIDRs(log, webhookConfig.AllowedCIDRs),
+		proxyNets:   parseCIDRs(log, webhookConfig.TrustedProxies),
+		fn:          fn,
+	}
+
+	if webhookConfig.RealIPHeader != "" && len(handler.proxyNets) == 0 {
+		handler.log.Warningf("Ignoring the %s header, no trusted proxies are configured", webhookConfig.RealIPHeader)
+	}
+
+	handler.setWebhookURL()
+
+	return handler
+}
+
+func parseCIDRs(log logger.Interface, cidrs []string) []*net.IPNet {
+	ipNets := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Panicf("invalid CIDR %q in webhook config: %s", cidr, err)
+		}
+
+		ipNets = append(ipNets, ipNet)
+	}
+
+	return ipNets
+}
+
+func (h *WebhookHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	h.log.Debugf("%s %s %s from %s", req.Method, req.URL, req.Proto, req.RemoteAddr)
+
+	if reason := h.rejectReason(req); reason != "" {
+		rejectedTotal.WithLabelValues(reason).Inc()
+		h.log.Warningf("Rejected webhook request from %s (%s): %s", req.RemoteAddr, h.sourceAddress(req), reason)
+		http.Error(res, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+
+		return
+	}
+
+	update, status, err := h.parseRequest(req)
+	if err != nil {
+		h.log.Error(err)
+		http.Error(res, err.Error(), status)
+
+		return
+	}
+
+	// Telegram only needs to know the update arrived, or it sends it again
+	if message, _ := update.MessageOrEdit(); message == nil {
+		h.log.Debugf("Ignoring update %d without message", update.ID)
+
+		return
+	}
+
+	h.fn(*update)
+}
+
+// rejectReason checks the secret token and the source address of a request.
+// It returns an empty string if the request is allowed.
+func (h *WebhookHandler) rejectReason(req *http.Request) string {
+	if h.webhookCfg.SecretToken != "" {
+		token := req.Header.Get(secretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.webhookCfg.SecretToken)) != 1 {
+			return rejectReasonSecretToken
+		}
+	}
+
+	if len(h.allowedNets) > 0 && !containsIP(h.allowedNets, h.sourceAddress(req)) {
+		return rejectReasonSourceAddress
+	}
+
+	return ""
+}
+
+// sourceAddress returns the IP address the request originates from. If the
+// bot runs behind a reverse proxy, the address is taken from the configured
+// header, but only if the request comes from a trusted proxy. Proxies append
+// to lists like X-Forwarded-For, so the list is read from the right and the
+// first address that isn't a trusted proxy is the client. Everything left of
+// it may be set by the client itself.
+func (h *WebhookHandler) sourceAddress(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	if h.webhookCfg.RealIPHeader == "" || !containsIP(h.proxyNets, host) {
+		return host
+	}
+
+	entries := strings.Split(strings.Join(req.Header.Values(h.webhookCfg.RealIPHeader), ","), ",")
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(entries[i])
+		if address == "" {
+			continue
+		}
+
+		if !containsIP(h.proxyNets, address) {
+			return address
+		}
+
+		host = address
+	}
+
+	return host
+}
+
+// containsIP checks if an IP address is part of one of the networks.
+func containsIP(ipNets []*net.IPNet, address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *WebhookHandler) parseRequest(req *http.Request) (*Update, int, error) {
+	if req.Method != http.MethodPost {
+		return nil, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed: %s (actual) != POST (expected)", req.Method)
+	}
+
+	update := &Update{
+		ID:            0,
+		Message:       nil,
+		EditedMessage: nil,
+	}
+	if err := json.NewDecoder(req.Body).Decode(update); err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("unable to decode request body: %s", err.Error())
+	}
+
+	return update, 0, nil
+}
+
+func (h *WebhookHandler) setWebhookURL() {
+	if h.cfg.WebhookURL == "" {
+		h.log.Info("Not setting Telegram webhook URL")
+
+		return
+	}
+
+	h.log.Info("Setting Telegram webhook URL to", h.cfg.WebhookURL)
+
+	apiURL := fmt.Sprintf(h.cfg.BaseURL, h.cfg.APIKey) + h.cfg.EndpointSetWebhook
+
+	h.log.Debug("Sending POST request to", apiURL)
+
+	values := url.Values{
+		"url": {h.cfg.WebhookURL},
+	}
+	if h.webhookCfg.SecretToken != "" {
+		values.Set("secret_token", h.webhookCfg.SecretToken)
+	}
+
+	resp, err := http.PostForm(apiURL, values) //nolint:gosec,noctx
+	if err != nil {
+		h.log.Panic("Unable to set Telegram webhook URL:", err)
+	}
+	defer resp.Body.Close()
+
+	body := &setWebhookResponse{
+		Ok:          false,
+		Result:      false,
+		ErrorCode:   0,
+		Description: "",
+	}
+	if err = json.NewDecoder(resp.Body).Decode(body); err != nil {
+		h.log.Fatal("Unable to decode response body:", err)
+	}
+
+	if !body.Ok {
+		h.log.Fatal("Unable to set Telegram webhook URL:", body.Description)
+	}
+
+	h.log.Debug("Successfully set Telegram webhook URL")
+}
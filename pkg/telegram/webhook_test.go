@@ -0,0 +1,125 @@
+package telegram_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	telegramclient "github.com/br0-space/bot-telegramclient"
+	"github.com/br0-space/bot/interfaces"
+	"github.com/br0-space/bot/pkg/telegram"
+	"github.com/stretchr/testify/assert"
+)
+
+func provideWebhookConfig(chatID int64) *telegramclient.ConfigStruct {
+	return &telegramclient.ConfigStruct{
+		APIKey:              "",
+		WebhookURL:          "",
+		BaseURL:             "",
+		EndpointSetWebhook:  "",
+		EndpointSendMessage: "",
+		EndpointSendPhoto:   "",
+		ChatID:              chatID,
+	}
+}
+
+var webhookTests = []struct {
+	method         string
+	body           string
+	expectedStatus int
+	expectedID     int64
+}{
+	{http.MethodGet, ``, http.StatusMethodNotAllowed, 0},
+	{http.MethodPost, `foo`, http.StatusBadRequest, 0},
+	{http.MethodPost, `{"update_id":1}`, http.StatusOK, 0},
+	{http.MethodPost, `{"update_id":2,"message":{"message_id":3,"chat":{"id":999},"text":"foo"}}`, http.StatusOK, 2},
+	{http.MethodPost, `{"update_id":4,"message":{"message_id":5,"chat":{"id":789},"text":"foo"}}`, http.StatusOK, 4},
+	{http.MethodPost, `{"update_id":6,"edited_message":{"message_id":5,"chat":{"id":789},"text":"foo"}}`, http.StatusOK, 6},
+}
+
+func TestWebhookHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range webhookTests {
+		var received *telegram.Update
+
+		handler := telegram.NewWebhookHandler(provideWebhookConfig(789), interfaces.WebhookConfigStruct{}, func(update telegram.Update) {
+			received = &update
+		})
+
+		req := httptest.NewRequest(tt.method, "/webhook", strings.NewReader(tt.body))
+		res := httptest.NewRecorder()
+
+		handler.ServeHTTP(res, req)
+
+		assert.Equal(t, tt.expectedStatus, res.Code, tt.body)
+
+		// Updates without a message are ignored, not answered with an error
+		if tt.expectedStatus == http.StatusOK {
+			assert.Empty(t, res.Body.String(), tt.body)
+		}
+
+		if tt.expectedID == 0 {
+			assert.Nil(t, received, tt.body)
+		} else if assert.NotNil(t, received, tt.body) {
+			message, _ := received.MessageOrEdit()
+			assert.Equal(t, tt.expectedID, received.ID, tt.body)
+			assert.Equal(t, "foo", message.Text, tt.body)
+		}
+	}
+}
+
+const validWebhookBody = `{"update_id":1,"message":{"message_id":2,"chat":{"id":789},"text":"foo"}}`
+
+var webhookFilterTests = []struct {
+	name           string
+	remoteAddr     string
+	headers        map[string]string
+	expectedStatus int
+}{
+	{"missing token", "149.154.160.1:443", map[string]string{}, http.StatusForbidden},
+	{"wrong token", "149.154.160.1:443", map[string]string{"X-Telegram-Bot-Api-Secret-Token": "wrong"}, http.StatusForbidden},
+	{"valid", "149.154.160.1:443", map[string]string{"X-Telegram-Bot-Api-Secret-Token": "s3cr3t"}, http.StatusOK},
+	{"second network", "91.108.4.10:443", map[string]string{"X-Telegram-Bot-Api-Secret-Token": "s3cr3t"}, http.StatusOK},
+	{"foreign address", "203.0.113.1:443", map[string]string{"X-Telegram-Bot-Api-Secret-Token": "s3cr3t"}, http.StatusForbidden},
+	{"proxy header", "10.0.0.1:443", map[string]string{"X-Telegram-Bot-Api-Secret-Token": "s3cr3t", "X-Forwarded-For": "149.154.167.99"}, http.StatusOK},
+	{"proxy chain", "10.0.0.1:443", map[string]string{"X-Telegram-Bot-Api-Secret-Token": "s3cr3t", "X-Forwarded-For": "149.154.167.99, 10.0.0.2"}, http.StatusOK},
+	{"foreign proxy header", "10.0.0.1:443", map[string]string{"X-Telegram-Bot-Api-Secret-Token": "s3cr3t", "X-Forwarded-For": "203.0.113.1"}, http.StatusForbidden},
+	{"spoofed proxy header", "10.0.0.1:443", map[string]string{"X-Telegram-Bot-Api-Secret-Token": "s3cr3t", "X-Forwarded-For": "149.154.167.99, 203.0.113.1"}, http.StatusForbidden},
+	{"header from untrusted address", "203.0.113.1:443", map[string]string{"X-Telegram-Bot-Api-Secret-Token": "s3cr3t", "X-Forwarded-For": "149.154.167.99"}, http.StatusForbidden},
+	{"header ignored from allowed address", "149.154.160.1:443", map[string]string{"X-Telegram-Bot-Api-Secret-Token": "s3cr3t", "X-Forwarded-For": "203.0.113.1"}, http.StatusOK},
+}
+
+func TestWebhookHandler_ServeHTTPFilters(t *testing.T) {
+	t.Parallel()
+
+	webhookConfig := interfaces.WebhookConfigStruct{
+		SecretToken:    "s3cr3t",
+		AllowedCIDRs:   []string{"149.154.160.0/20", "91.108.4.0/22"},
+		RealIPHeader:   "X-Forwarded-For",
+		TrustedProxies: []string{"10.0.0.0/8"},
+	}
+
+	for _, tt := range webhookFilterTests {
+		processed := false
+
+		handler := telegram.NewWebhookHandler(provideWebhookConfig(0), webhookConfig, func(_ telegram.Update) {
+			processed = true
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(validWebhookBody))
+		req.RemoteAddr = tt.remoteAddr
+
+		for key, value := range tt.headers {
+			req.Header.Set(key, value)
+		}
+
+		res := httptest.NewRecorder()
+
+		handler.ServeHTTP(res, req)
+
+		assert.Equal(t, tt.expectedStatus, res.Code, tt.name)
+		assert.Equal(t, tt.expectedStatus == http.StatusOK, processed, tt.name)
+	}
+}